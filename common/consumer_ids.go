@@ -46,6 +46,18 @@ var ConsumerNewVersionAPIGateway = prepareConsumerID("new-version-apigateway")
 //ConsumerShutDownRequest shutdown-request
 var ConsumerShutDownRequest = prepareConsumerID("shutdown-request")
 
+//ConsumerResponseCacheInvalidate response-cache-invalidate
+var ConsumerResponseCacheInvalidate = prepareConsumerID("response-cache-invalidate")
+
+//ConsumerRouteChangeAPIGateway route-change-apigateway
+var ConsumerRouteChangeAPIGateway = prepareConsumerID("route-change-apigateway")
+
+//ConsumerWebSocketSend websocket-send
+var ConsumerWebSocketSend = prepareConsumerID("websocket-send")
+
+//ConsumerFunctionMetrics function-metrics
+var ConsumerFunctionMetrics = prepareConsumerID("function-metrics")
+
 func prepareConsumerID(id string) string {
 	return consumerPrefix + ConsumerJOIN + id + ConsumerJOIN + UUIDGen()
 }
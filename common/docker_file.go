@@ -14,14 +14,56 @@
 
 package common
 
+//default base images. runtime catalog can override them per installation
+
+//DockerImage_Java java base image
+const DockerImage_Java = "quebicdocker/quebic-faas-container-java:0.1.0"
+
+//DockerImage_NodeJS nodejs base image
+const DockerImage_NodeJS = "quebicdocker/quebic-faas-container-nodejs:0.1.0"
+
+//DockerImage_Python_2_7 python 2.7 base image
+const DockerImage_Python_2_7 = "quebicdocker/quebic-faas-container-python-2_7:0.1.0"
+
+//DockerImage_Python_3_6 python 3.6 base image
+const DockerImage_Python_3_6 = "quebicdocker/quebic-faas-container-python-3_6:0.1.0"
+
+//DockerImage_Go go base image
+const DockerImage_Go = "quebicdocker/quebic-faas-container-go:0.1.0"
+
+//DockerImage_GoBuilder go builder image. has the sdk inside GOPATH
+const DockerImage_GoBuilder = "quebicdocker/quebic-faas-container-go-builder:0.1.0"
+
+//Dockerfile templates. {{.Image}} base image, {{.BuildImage}} image of the build stage
+
+//DockerFileTemplate_Java java docker file template
+const DockerFileTemplate_Java = "FROM {{.Image}}\n" + dockerFileBody_Java
+
+//DockerFileTemplate_Handler docker file template of the runtimes which run function_handler.tar. nodejs, python
+const DockerFileTemplate_Handler = "FROM {{.Image}}\n" + dockerFileBody_Handler
+
+//DockerFileTemplate_Go multi-stage docker file template
+const DockerFileTemplate_Go = "FROM {{.BuildImage}} AS builder\n" + dockerFileBody_GoBuilder + "\nFROM {{.Image}}\n" + dockerFileBody_Go
+
+const dockerFileBody_Java = "ADD function.jar /app/function.jar\nARG access_key\nENV access_key $access_key\n"
+const dockerFileBody_Handler = "ADD function_handler.tar /app/function_handler/\nARG access_key\nENV access_key $access_key\n"
+const dockerFileBody_GoBuilder = "ARG handler_package\nADD function_handler.tar /go/src/function_handler/\nWORKDIR /go/src/function_handler\nRUN CGO_ENABLED=0 go build -o /function $handler_package\n"
+const dockerFileBody_Go = "COPY --from=builder /function /app/function\nARG access_key\nENV access_key $access_key\nENTRYPOINT [\"/app/function\"]\n"
+
 //DockerFileContent_Java java docker file
-const DockerFileContent_Java = "FROM quebicdocker/quebic-faas-container-java:0.1.0\nADD function.jar /app/function.jar\nARG access_key\nENV access_key $access_key\n"
+const DockerFileContent_Java = "FROM " + DockerImage_Java + "\n" + dockerFileBody_Java
 
 //DockerFileContent_Java java docker file
-const DockerFileContent_NodeJS = "FROM quebicdocker/quebic-faas-container-nodejs:0.1.0\nADD function_handler.tar /app/function_handler/\nARG access_key\nENV access_key $access_key\n"
+const DockerFileContent_NodeJS = "FROM " + DockerImage_NodeJS + "\n" + dockerFileBody_Handler
 
 //DockerFileContent_Python_2_7 docker file
-const DockerFileContent_Python_2_7 = "FROM quebicdocker/quebic-faas-container-python-2_7:0.1.0\nADD function_handler.tar /app/function_handler/\nARG access_key\nENV access_key $access_key\n"
+const DockerFileContent_Python_2_7 = "FROM " + DockerImage_Python_2_7 + "\n" + dockerFileBody_Handler
 
 //DockerFileContent_Python_3_6 docker file
-const DockerFileContent_Python_3_6 = "FROM quebicdocker/quebic-faas-container-python-3_6:0.1.0\nADD function_handler.tar /app/function_handler/\nARG access_key\nENV access_key $access_key\n"
+const DockerFileContent_Python_3_6 = "FROM " + DockerImage_Python_3_6 + "\n" + dockerFileBody_Handler
+
+//DockerFileContent_Go multi-stage docker file. builder stage compiles the handler package with the sdk
+const DockerFileContent_Go = "FROM " + DockerImage_GoBuilder + " AS builder\n" + dockerFileBody_GoBuilder + "\nFROM " + DockerImage_Go + "\n" + dockerFileBody_Go
+
+//DockerBuildArgGoHandlerPackage build arg carries the package which go builder compiles
+const DockerBuildArgGoHandlerPackage = "handler_package"
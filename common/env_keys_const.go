@@ -51,6 +51,10 @@ const EnvKey_rabbitmq_port = "rabbitmq_port"
 const EnvKey_rabbitmq_management_username = "rabbitmq_management_username"
 const EnvKey_rabbitmq_management_password = "rabbitmq_management_password"
 
+const EnvKey_tracing_exporter = "tracing_exporter"
+const EnvKey_tracing_otlpEndpoint = "tracing_otlpEndpoint"
+const EnvKey_tracing_filePath = "tracing_filePath"
+
 const EnvKey_eventConst_eventPrefixUserDefined = "eventConst_eventPrefixUserDefined"
 
 const EnvKey_eventConst_eventLog = "eventConst_eventLog"
@@ -58,6 +62,7 @@ const EnvKey_eventConst_eventFunctionAwake = "eventConst_eventFunctionAwake"
 const EnvKey_eventConst_eventDataFetch = "eventConst_eventDataFetch"
 const EnvKey_eventConst_eventNewVersion = "eventConst_eventNewVersion"
 const EnvKey_eventConst_eventShutDownRequest = "eventConst_eventShutDownRequest"
+const EnvKey_eventConst_eventFunctionMetrics = "eventConst_eventFunctionMetrics"
 
 const EnvKey_events = "events"
 const EnvKey_artifactLocation = "artifactLocation"
@@ -71,3 +76,19 @@ const EnvKey_mongo_db = "mongo.db"
 const EnvKey_mongo_username = "mongo.username"
 const EnvKey_mongo_password = "mongo.password"
 const EnvKey_eventbox_uri = "EVENTBOX_URI"
+
+const EnvKey_storage_backend = "storage_backend"
+const EnvKey_storage_path = "storage_path"
+const EnvKey_storage_dataSource = "storage_dataSource"
+
+const EnvKey_leaderElection_enabled = "leaderElection_enabled"
+const EnvKey_leaderElection_backend = "leaderElection_backend"
+
+const EnvKey_dockerConfig_builder = "dockerConfig_builder"
+const EnvKey_dockerConfig_registryAddress = "dockerConfig_registryAddress"
+const EnvKey_dockerConfig_registrySecret = "dockerConfig_registrySecret"
+const EnvKey_dockerConfig_buildVolumeClaim = "dockerConfig_buildVolumeClaim"
+const EnvKey_dockerConfig_credentialsKey = "dockerConfig_credentialsKey"
+const EnvKey_dockerConfig_verifyImageDigest = "dockerConfig_verifyImageDigest"
+
+const EnvKey_artifacts_path = "artifacts_path"
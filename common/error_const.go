@@ -15,3 +15,5 @@
 package common
 
 const ErrorValidationFailed = "validation-failed"
+
+const ErrorRevisionConflict = "revision-conflict"
@@ -55,5 +55,15 @@ const EventNewVersionAPIGateway = EventNewVersionPrefix + ComponentAPIGateway
 //EventNewVersionFunctionPrefix function new version
 const EventNewVersionFunctionPrefix = EventNewVersionPrefix + "function" + EventJOIN
 
+//EventRouteChange brodcast event for route add/update. apigateways reload routes without re-deploy
+const EventRouteChange = EventPrefixInternal + EventJOIN + "route-change"
+
+//EventWebSocketSendPrefix quebic-faas-event.internal.websocket-send.<connection-id>
+//connection-id => <apigateway-replica-id>.<uuid>
+const EventWebSocketSendPrefix = EventPrefixInternal + EventJOIN + "websocket-send" + EventJOIN
+
+//EventFunctionMetrics functions report invocation metrics. manager aggregates them
+const EventFunctionMetrics = EventPrefixInternal + EventJOIN + "function-metrics"
+
 //EventShutDownRequest event send from component that request to delete deployment
 const EventShutDownRequest = EventPrefixInternal + EventJOIN + "shutdown-request"
@@ -18,6 +18,12 @@ package common
 const FunctionSaveField_SPEC = "spec"
 const FunctionSaveField_SOURCE = "source"
 
+//FunctionSaveParam_STREAM when true states and build output of the function job are streamed until the job is finished
+const FunctionSaveParam_STREAM = "stream"
+
+//FunctionBuildContentType content type of the streamed job. one types.FunctionBuildMessage per line
+const FunctionBuildContentType = "application/x-ndjson"
+
 //Runtime function runtime
 type Runtime string
 
@@ -33,6 +39,12 @@ const RuntimePython_2_7 = "python_2.7"
 //RuntimePython_3_6 python 3_6
 const RuntimePython_3_6 = "python_3.6"
 
+//RuntimeGo go
+const RuntimeGo = "go"
+
+//RuntimeCustom user provided image or Dockerfile
+const RuntimeCustom = "custom"
+
 //KubeStatusTrue True
 const KubeStatusTrue = "True"
 
@@ -66,10 +78,22 @@ const FunctionLifeMinAgeSeconds = 60 * FunctionLifeMinAgeMinutes
 //FunctionInitialVersion init version
 const FunctionInitialVersion = "0.1.0"
 
+//FunctionBuildStatusRunning RUNNING
+const FunctionBuildStatusRunning = "RUNNING"
+
+//FunctionBuildStatusSucceeded SUCCEEDED
+const FunctionBuildStatusSucceeded = "SUCCEEDED"
+
+//FunctionBuildStatusFailed FAILED
+const FunctionBuildStatusFailed = "FAILED"
+
+//FunctionBuildMaxLogLines build output lines kept on the function. older lines are dropped
+const FunctionBuildMaxLogLines = 500
+
 //RuntimeValidate runtime validate
 func RuntimeValidate(runtime Runtime) bool {
 
-	runtimesAviable := [4]string{RuntimeJava, RuntimeNodeJS, RuntimePython_2_7, RuntimePython_3_6}
+	runtimesAviable := [6]string{RuntimeJava, RuntimeNodeJS, RuntimePython_2_7, RuntimePython_3_6, RuntimeGo, RuntimeCustom}
 
 	for _, runtimeAviable := range runtimesAviable {
 
@@ -19,3 +19,18 @@ const HeaderAccessKey = "accesskey"
 
 //HeaderRequestID header
 const HeaderRequestID = "request-id"
+
+//HeaderCacheStatus header. response-cache status HIT / MISS / BYPASS
+const HeaderCacheStatus = "x-cache-status"
+
+//HeaderWebSocketConnectionID header. websocket connection which event belongs to
+const HeaderWebSocketConnectionID = "websocketConnectionID"
+
+//HeaderWebSocketEvent header. connect / message / disconnect
+const HeaderWebSocketEvent = "websocketEvent"
+
+//HeaderWebSocketClose header. close the connection after the message
+const HeaderWebSocketClose = "websocketClose"
+
+//HeaderTraceparent header. W3C trace-context propagated through http requests and events
+const HeaderTraceparent = "traceparent"
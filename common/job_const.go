@@ -0,0 +1,45 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package common
+
+//JobKindFunctionCreate function_create
+const JobKindFunctionCreate = "function_create"
+
+//JobKindFunctionUpdate function_update
+const JobKindFunctionUpdate = "function_update"
+
+//JobStateUploaded source is kept. waiting to build
+const JobStateUploaded = "uploaded"
+
+//JobStateFetching git source is being fetched
+const JobStateFetching = "fetching"
+
+//JobStateBuilding building
+const JobStateBuilding = "building"
+
+//JobStatePushing pushing
+const JobStatePushing = "pushing"
+
+//JobStateDeploying deploying
+const JobStateDeploying = "deploying"
+
+//JobStateReady ready
+const JobStateReady = "ready"
+
+//JobStateFailed failed
+const JobStateFailed = "failed"
+
+//JobMaxLogLines output lines kept on the job. older lines are dropped
+const JobMaxLogLines = 500
@@ -26,5 +26,23 @@ const ResourceRequestMethodPUT = "PUT"
 //ResourceRequestMethodDELETE DELETE
 const ResourceRequestMethodDELETE = "DELETE"
 
+//ResourceRequestMethodWebSocket WEBSOCKET. gateway hold the socket and publish connection events
+const ResourceRequestMethodWebSocket = "WEBSOCKET"
+
+//WebSocketEventConnect socket connected
+const WebSocketEventConnect = "connect"
+
+//WebSocketEventMessage message received from socket
+const WebSocketEventMessage = "message"
+
+//WebSocketEventDisconnect socket disconnected
+const WebSocketEventDisconnect = "disconnect"
+
+//ResourceStreamChunked partial responses are written as newline delimited json
+const ResourceStreamChunked = "chunked"
+
+//ResourceStreamSSE partial responses are written as server-sent events
+const ResourceStreamSSE = "sse"
+
 //ResourceJOIN resource id join
 const ResourceJOIN = ":"
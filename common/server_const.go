@@ -30,6 +30,9 @@ const DockerRegistryPort = "5000"
 //ApigatewayServerPort apigateway server port
 const ApigatewayServerPort = 3000
 
+//ApigatewayGRPCServerPort apigateway grpc server port
+const ApigatewayGRPCServerPort = 3001
+
 //RabbitmqAMQPPort rabbit
 const RabbitmqAMQPPort = 5672
 
@@ -29,3 +29,10 @@ type EventBusConfig struct {
 	ManagementUserName string `json:"managementUserName" yaml:"managementUserName"`
 	ManagementPassword string `json:"managementPassword" yaml:"managementPassword"`
 }
+
+//TracingConfig span exporter config
+type TracingConfig struct {
+	Exporter     string `json:"exporter" yaml:"exporter"`         //otlp / file. empty disables exporting
+	OTLPEndpoint string `json:"otlpEndpoint" yaml:"otlpEndpoint"` //collector base url. eg: http://collector:4318
+	FilePath     string `json:"filePath" yaml:"filePath"`
+}
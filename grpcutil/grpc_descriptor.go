@@ -0,0 +1,48 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package grpcutil
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/jhump/protoreflect/desc"
+)
+
+//FindServiceDescriptor find service in FileDescriptorSet
+func FindServiceDescriptor(descriptorSet []byte, serviceName string) (*desc.ServiceDescriptor, error) {
+
+	fileDescriptorSet := &descriptor.FileDescriptorSet{}
+	err := proto.Unmarshal(descriptorSet, fileDescriptorSet)
+	if err != nil {
+		return nil, fmt.Errorf("descriptor is not a valid FileDescriptorSet : %v", err)
+	}
+
+	files, err := desc.CreateFileDescriptors(fileDescriptorSet.File)
+	if err != nil {
+		return nil, fmt.Errorf("descriptor is not a valid FileDescriptorSet : %v", err)
+	}
+
+	for _, file := range files {
+		serviceDescriptor := file.FindService(serviceName)
+		if serviceDescriptor != nil {
+			return serviceDescriptor, nil
+		}
+	}
+
+	return nil, fmt.Errorf("service %s is not found in the descriptor", serviceName)
+
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"quebic-faas/common"
+	"quebic-faas/tracing"
 	"reflect"
 )
 
@@ -26,6 +27,9 @@ const headerRequestID = "requestID"
 const headerCreated = "created"
 const headerStatuscode = "statuscode"
 const headerError = "error"
+const headerStream = "stream"
+
+const streamPartial = "partial"
 
 //BaseEvent type
 type BaseEvent struct {
@@ -121,6 +125,23 @@ func (baseEvent *BaseEvent) GetError() string {
 
 }
 
+//IsPartial check wether this reply is a partial response of a stream
+func (baseEvent *BaseEvent) IsPartial() bool {
+
+	headerval := baseEvent.headers[headerStream]
+	if headerval == nil {
+		return false
+	}
+
+	return headerval.(string) == streamPartial
+
+}
+
+//GetSpanContext get span which produced this event. empty when event is not traced
+func (baseEvent *BaseEvent) GetSpanContext() tracing.SpanContext {
+	return getSpanContext(baseEvent.headers)
+}
+
 //SetHeaderData set requestHeaders
 func (baseEvent *BaseEvent) setHeaderData(key string, value interface{}) {
 	baseEvent.headers[key] = value
@@ -0,0 +1,54 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package messenger
+
+import (
+	"fmt"
+	"quebic-faas/common"
+	"quebic-faas/metrics"
+	"quebic-faas/types"
+)
+
+var publishTimeouts = metrics.NewCounter(
+	"quebic_messenger_publish_timeouts_total",
+	"published events which did not receive a reply within request timeout",
+	"event")
+
+//ReportInvocations report invocation metrics of the function into manager
+func (messenger *Messenger) ReportInvocations(function string, invocations []types.FunctionInvocation) error {
+
+	if len(invocations) == 0 {
+		return nil
+	}
+
+	_, err := messenger.publish(
+		common.EventFunctionMetrics,
+		types.FunctionMetricsMessage{Function: function, Invocations: invocations},
+		nil,
+		defaultStatuscode,
+		emptyError,
+		nil,
+		nil,
+		nil,
+		0,
+		false,
+		nil)
+
+	if err != nil {
+		return fmt.Errorf("failed to report invocations, error : %v", err)
+	}
+
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"log"
 	"quebic-faas/common"
+	"quebic-faas/tracing"
 	"quebic-faas/types"
 	"time"
 
@@ -40,16 +41,20 @@ func (messenger *Messenger) Publish(
 	errHandler func(err string, statuscode int, context Context),
 	requestTimeout time.Duration) (string, error) {
 
+	span, requestHeaders := startPublishSpan(eventID, requestHeaders)
+
 	return messenger.publish(
 		eventID,
 		payload,
 		requestHeaders,
 		defaultStatuscode,
 		emptyError,
-		successHandler,
-		errHandler,
+		nil,
+		traceSuccessHandler(span, eventID, successHandler),
+		traceErrHandler(span, eventID, errHandler),
 		requestTimeout,
-		false)
+		false,
+		span)
 
 }
 
@@ -62,16 +67,49 @@ func (messenger *Messenger) PublishBlocking(
 	errHandler func(err string, statuscode int, context Context),
 	requestTimeout time.Duration) (string, error) {
 
+	span, requestHeaders := startPublishSpan(eventID, requestHeaders)
+
 	return messenger.publish(
 		eventID,
 		payload,
 		requestHeaders,
 		defaultStatuscode,
 		emptyError,
-		successHandler,
-		errHandler,
+		nil,
+		traceSuccessHandler(span, eventID, successHandler),
+		traceErrHandler(span, eventID, errHandler),
 		requestTimeout,
-		true)
+		true,
+		span)
+
+}
+
+//PublishStream publish and wait caller thread until stream is completed
+//partialHandler called for each partial response. stream is completed by success or error response
+//requestTimeout is applied between two responses
+func (messenger *Messenger) PublishStream(
+	eventID string,
+	payload interface{},
+	requestHeaders map[string]interface{},
+	partialHandler func(message BaseEvent, statuscode int, context Context),
+	successHandler func(message BaseEvent, statuscode int, context Context),
+	errHandler func(err string, statuscode int, context Context),
+	requestTimeout time.Duration) (string, error) {
+
+	span, requestHeaders := startPublishSpan(eventID, requestHeaders)
+
+	return messenger.publish(
+		eventID,
+		payload,
+		requestHeaders,
+		defaultStatuscode,
+		emptyError,
+		partialHandler,
+		traceSuccessHandler(span, eventID, successHandler),
+		traceErrHandler(span, eventID, errHandler),
+		requestTimeout,
+		true,
+		span)
 
 }
 
@@ -82,12 +120,18 @@ func (messenger *Messenger) publish(
 	requestHeaders map[string]interface{},
 	statuscode int,
 	errorMessage string,
+	partialHandler func(message BaseEvent, statuscode int, context Context),
 	successHandler func(message BaseEvent, statuscode int, context Context),
 	errHandler func(err string, statuscode int, context Context),
 	requestTimeout time.Duration,
-	blocking bool) (string, error) {
+	blocking bool,
+	span *tracing.Span) (string, error) {
 
 	if eventID == "" {
+		if span != nil {
+			span.SetError("eventID should not be empty")
+			span.Finish()
+		}
 		return "", fmt.Errorf("eventID should not be empty")
 	}
 
@@ -116,8 +160,8 @@ func (messenger *Messenger) publish(
 	}
 	requestID := baseEvent.GetRequestID()
 
-	//request-tracker setup
-	if eventID != common.EventRequestTracker {
+	//request-tracker setup. not for internal reporting events
+	if eventID != common.EventRequestTracker && eventID != common.EventFunctionMetrics {
 		messenger.setUpRequestTracker(requestID)
 	}
 
@@ -126,6 +170,7 @@ func (messenger *Messenger) publish(
 
 	//responseHandler setup
 	waitForResponse := make(chan bool)
+	partialReceived := make(chan bool, 1)
 	if successHandler != nil || errHandler != nil {
 
 		err := messenger.Subscribe(requestID, func(be BaseEvent) {
@@ -133,6 +178,20 @@ func (messenger *Messenger) publish(
 			//reply
 			err := be.GetError()
 			statuscode := be.GetStatuscode()
+
+			//partial reply of a stream. keep waiting for the completion
+			if be.IsPartial() && partialHandler != nil {
+
+				partialHandler(be, statuscode, Context{RequestID: requestID})
+
+				select {
+				case partialReceived <- true:
+				default:
+				}
+
+				return
+
+			}
 			if err == "" {
 
 				if successHandler != nil {
@@ -178,6 +237,14 @@ func (messenger *Messenger) publish(
 			Headers:     baseEvent.headers,
 		},
 	)
+	if span != nil {
+		span.SetAttribute("faas.request_id", requestID)
+		if err != nil {
+			span.SetError(err.Error())
+		}
+		span.Finish()
+	}
+
 	if err != nil {
 		messenger.ReleseQueue(requestID)
 		return "", fmt.Errorf("failed to publish message, error : %v", err)
@@ -186,10 +253,10 @@ func (messenger *Messenger) publish(
 	if successHandler != nil || errHandler != nil {
 
 		if blocking {
-			wait(messenger, requestID, waitForResponse, requestTimeout, errHandler)
+			wait(messenger, eventID, requestID, waitForResponse, partialReceived, requestTimeout, errHandler)
 		} else {
 			go func() {
-				wait(messenger, requestID, waitForResponse, requestTimeout, errHandler)
+				wait(messenger, eventID, requestID, waitForResponse, partialReceived, requestTimeout, errHandler)
 			}()
 		}
 
@@ -201,19 +268,27 @@ func (messenger *Messenger) publish(
 
 func wait(
 	messenger *Messenger,
+	eventID string,
 	requestID string,
 	waitForResponse chan bool,
+	partialReceived chan bool,
 	requestTimeout time.Duration,
 	errHandler func(err string, statuscode int, context Context),
 ) {
-	//wait for response
-	select {
-	case <-waitForResponse:
-		break
-	case <-time.After(requestTimeout):
-		messenger.ReleseQueue(requestID)
-		if errHandler != nil {
-			errHandler(fmt.Sprintf("request timeout for %s", requestID), 500, Context{RequestID: requestID})
+	//wait for response. partial responses extend the timeout
+	for {
+		select {
+		case <-waitForResponse:
+			return
+		case <-partialReceived:
+			continue
+		case <-time.After(requestTimeout):
+			publishTimeouts.Inc(eventID)
+			messenger.ReleseQueue(requestID)
+			if errHandler != nil {
+				errHandler(fmt.Sprintf("request timeout for %s", requestID), 500, Context{RequestID: requestID})
+			}
+			return
 		}
 	}
 }
@@ -238,8 +313,10 @@ func (messenger *Messenger) setUpRequestTracker(requestID string) {
 		"",
 		nil,
 		nil,
+		nil,
 		0,
 		false,
+		nil,
 	)
 
 }
@@ -29,8 +29,10 @@ func (messenger *Messenger) ReplySuccess(requestBaseEvent BaseEvent, replyPayloa
 		emptyError,
 		nil,
 		nil,
+		nil,
 		0,
-		false)
+		false,
+		startReplySpan(requestBaseEvent))
 
 	if err != nil {
 		return fmt.Errorf("failed to reply success, error : %v", err)
@@ -50,8 +52,10 @@ func (messenger *Messenger) ReplyError(requestBaseEvent BaseEvent, errMessage st
 		errMessage,
 		nil,
 		nil,
+		nil,
 		0,
-		false)
+		false,
+		startReplySpan(requestBaseEvent))
 
 	if err != nil {
 		return fmt.Errorf("failed to reply error, error : %v", err)
@@ -59,3 +63,29 @@ func (messenger *Messenger) ReplyError(requestBaseEvent BaseEvent, errMessage st
 
 	return nil
 }
+
+//ReplyPartial reply partial response of a stream. stream is completed by ReplySuccess or ReplyError
+func (messenger *Messenger) ReplyPartial(requestBaseEvent BaseEvent, replyPayload interface{}, statuscode int) error {
+
+	replyHeaders := make(map[string]interface{})
+	replyHeaders[headerStream] = streamPartial
+
+	_, err := messenger.publish(
+		requestBaseEvent.GetRequestID(),
+		replyPayload,
+		replyHeaders,
+		statuscode,
+		emptyError,
+		nil,
+		nil,
+		nil,
+		0,
+		false,
+		startReplySpan(requestBaseEvent))
+
+	if err != nil {
+		return fmt.Errorf("failed to reply partial, error : %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package messenger
+
+import (
+	"quebic-faas/common"
+	"quebic-faas/tracing"
+)
+
+//startPublishSpan start producer span of the event. parent is taken from traceparent of requestHeaders
+//returned headers carry the traceparent of the new span to the consumer
+func startPublishSpan(eventID string, requestHeaders map[string]interface{}) (*tracing.Span, map[string]interface{}) {
+
+	headers := make(map[string]interface{})
+	for k, v := range requestHeaders {
+		headers[k] = v
+	}
+
+	span := tracing.StartSpan("publish "+eventID, tracing.SpanKindProducer, getSpanContext(headers))
+	span.SetAttribute("messaging.system", "rabbitmq")
+	span.SetAttribute("messaging.destination", eventID)
+
+	headers[common.HeaderTraceparent] = span.Traceparent()
+
+	return span, headers
+
+}
+
+//startReplySpan start producer span of the reply. child of the request which is replied
+func startReplySpan(requestBaseEvent BaseEvent) *tracing.Span {
+
+	span := tracing.StartSpan("reply "+requestBaseEvent.GetEventID(), tracing.SpanKindProducer, requestBaseEvent.GetSpanContext())
+	span.SetAttribute("messaging.system", "rabbitmq")
+	span.SetAttribute("faas.request_id", requestBaseEvent.GetRequestID())
+
+	return span
+
+}
+
+//traceSuccessHandler record function execution span when success reply is received
+func traceSuccessHandler(
+	span *tracing.Span,
+	eventID string,
+	successHandler func(message BaseEvent, statuscode int, context Context)) func(message BaseEvent, statuscode int, context Context) {
+
+	if successHandler == nil {
+		return nil
+	}
+
+	return func(message BaseEvent, statuscode int, context Context) {
+		recordExecutionSpan(span, eventID, "", statuscode, context)
+		successHandler(message, statuscode, context)
+	}
+
+}
+
+//traceErrHandler record function execution span when error reply is received or request timeout
+func traceErrHandler(
+	span *tracing.Span,
+	eventID string,
+	errHandler func(err string, statuscode int, context Context)) func(err string, statuscode int, context Context) {
+
+	if errHandler == nil {
+		return nil
+	}
+
+	return func(err string, statuscode int, context Context) {
+		recordExecutionSpan(span, eventID, err, statuscode, context)
+		errHandler(err, statuscode, context)
+	}
+
+}
+
+//recordExecutionSpan function execution as observed by the publisher. from delivery to the eventbus until the reply
+func recordExecutionSpan(publishSpan *tracing.Span, eventID string, err string, statuscode int, context Context) {
+
+	start := publishSpan.EndedAt()
+	if start.IsZero() {
+		start = publishSpan.Start
+	}
+
+	span := tracing.StartSpanAt("execute "+eventID, tracing.SpanKindConsumer, publishSpan.Context, start)
+	span.SetAttribute("faas.event", eventID)
+	span.SetAttribute("faas.request_id", context.RequestID)
+	span.SetAttribute("faas.statuscode", common.IntToStr(statuscode))
+
+	if err != "" {
+		span.SetError(err)
+	}
+
+	span.Finish()
+
+}
+
+func getSpanContext(headers map[string]interface{}) tracing.SpanContext {
+
+	traceparent, ok := headers[common.HeaderTraceparent].(string)
+	if !ok {
+		return tracing.SpanContext{}
+	}
+
+	return tracing.ParseTraceparent(traceparent)
+
+}
@@ -0,0 +1,67 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package messenger
+
+import (
+	"fmt"
+	"quebic-faas/common"
+)
+
+//SendToConnection send message into websocket connection. apigateway which holds the socket deliver it
+func (messenger *Messenger) SendToConnection(connectionID string, payload interface{}) error {
+
+	if connectionID == "" {
+		return fmt.Errorf("connectionID should not be empty")
+	}
+
+	_, err := messenger.Publish(
+		common.EventWebSocketSendPrefix+connectionID,
+		payload,
+		nil,
+		nil,
+		nil,
+		0,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to send to connection, error : %v", err)
+	}
+
+	return nil
+}
+
+//CloseConnection close websocket connection
+func (messenger *Messenger) CloseConnection(connectionID string) error {
+
+	if connectionID == "" {
+		return fmt.Errorf("connectionID should not be empty")
+	}
+
+	requestHeaders := make(map[string]interface{})
+	requestHeaders[common.HeaderWebSocketClose] = "true"
+
+	_, err := messenger.Publish(
+		common.EventWebSocketSendPrefix+connectionID,
+		nil,
+		requestHeaders,
+		nil,
+		nil,
+		0,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to close connection, error : %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,407 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+const labelValuesJOIN = "\xff"
+
+//DefaultBuckets latency buckets in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+//collector writes its samples in prometheus text format
+type collector interface {
+	name() string
+	write(w io.Writer)
+}
+
+//Registry holds collectors exposed by the process
+type Registry struct {
+	collectors []collector
+	mutex      sync.RWMutex
+}
+
+//DefaultRegistry registry used by the package level constructors
+var DefaultRegistry = &Registry{}
+
+//register add collector. metric names should be unique within the registry
+func (registry *Registry) register(c collector) {
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	for _, existing := range registry.collectors {
+		if existing.name() == c.name() {
+			panic(fmt.Sprintf("metric %s already registered", c.name()))
+		}
+	}
+
+	registry.collectors = append(registry.collectors, c)
+
+}
+
+//Write write all metrics in prometheus text format
+func (registry *Registry) Write(w io.Writer) {
+
+	registry.mutex.RLock()
+	collectors := make([]collector, len(registry.collectors))
+	copy(collectors, registry.collectors)
+	registry.mutex.RUnlock()
+
+	sort.Slice(collectors, func(i, j int) bool {
+		return collectors[i].name() < collectors[j].name()
+	})
+
+	buffered := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(buffered)
+	}
+	buffered.Flush()
+
+}
+
+//Handler serve metrics of the DefaultRegistry
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		DefaultRegistry.Write(w)
+	})
+}
+
+//metric common parts of labeled metrics
+type metric struct {
+	metricName string
+	help       string
+	metricType string
+	labelNames []string
+	mutex      sync.Mutex
+}
+
+func (m *metric) name() string {
+	return m.metricName
+}
+
+func (m *metric) key(labelValues []string) string {
+
+	if len(labelValues) != len(m.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", m.metricName, len(m.labelNames), len(labelValues)))
+	}
+
+	return strings.Join(labelValues, labelValuesJOIN)
+
+}
+
+func (m *metric) writeHeader(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", m.metricName, escapeHelp(m.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", m.metricName, m.metricType)
+}
+
+//Counter monotonically increasing value
+type Counter struct {
+	metric
+	values map[string]float64
+}
+
+//NewCounter create and register counter
+func NewCounter(name string, help string, labelNames ...string) *Counter {
+
+	counter := &Counter{
+		metric: metric{metricName: name, help: help, metricType: "counter", labelNames: labelNames},
+		values: make(map[string]float64),
+	}
+
+	DefaultRegistry.register(counter)
+
+	return counter
+
+}
+
+//Inc increment by 1
+func (counter *Counter) Inc(labelValues ...string) {
+	counter.Add(1, labelValues...)
+}
+
+//Add increment by value. negative values are ignored
+func (counter *Counter) Add(value float64, labelValues ...string) {
+
+	if value < 0 {
+		return
+	}
+
+	key := counter.key(labelValues)
+
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	counter.values[key] += value
+
+}
+
+func (counter *Counter) write(w io.Writer) {
+
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	counter.writeHeader(w)
+	for _, key := range sortedKeys(counter.values) {
+		fmt.Fprintf(w, "%s%s %s\n", counter.metricName, formatLabels(counter.labelNames, key, "", ""), formatValue(counter.values[key]))
+	}
+
+}
+
+//Gauge value which can go up and down
+type Gauge struct {
+	metric
+	values map[string]float64
+}
+
+//NewGauge create and register gauge
+func NewGauge(name string, help string, labelNames ...string) *Gauge {
+
+	gauge := &Gauge{
+		metric: metric{metricName: name, help: help, metricType: "gauge", labelNames: labelNames},
+		values: make(map[string]float64),
+	}
+
+	DefaultRegistry.register(gauge)
+
+	return gauge
+
+}
+
+//Set set value
+func (gauge *Gauge) Set(value float64, labelValues ...string) {
+
+	key := gauge.key(labelValues)
+
+	gauge.mutex.Lock()
+	defer gauge.mutex.Unlock()
+
+	gauge.values[key] = value
+
+}
+
+//Add add value. use negative value to decrease
+func (gauge *Gauge) Add(value float64, labelValues ...string) {
+
+	key := gauge.key(labelValues)
+
+	gauge.mutex.Lock()
+	defer gauge.mutex.Unlock()
+
+	gauge.values[key] += value
+
+}
+
+func (gauge *Gauge) write(w io.Writer) {
+
+	gauge.mutex.Lock()
+	defer gauge.mutex.Unlock()
+
+	gauge.writeHeader(w)
+	for _, key := range sortedKeys(gauge.values) {
+		fmt.Fprintf(w, "%s%s %s\n", gauge.metricName, formatLabels(gauge.labelNames, key, "", ""), formatValue(gauge.values[key]))
+	}
+
+}
+
+//GaugeFunc gauge which is evaluated on each scrape
+type GaugeFunc struct {
+	metric
+	fn func() float64
+}
+
+//NewGaugeFunc create and register gauge func
+func NewGaugeFunc(name string, help string, fn func() float64) *GaugeFunc {
+
+	gaugeFunc := &GaugeFunc{
+		metric: metric{metricName: name, help: help, metricType: "gauge"},
+		fn:     fn,
+	}
+
+	DefaultRegistry.register(gaugeFunc)
+
+	return gaugeFunc
+
+}
+
+func (gaugeFunc *GaugeFunc) write(w io.Writer) {
+	gaugeFunc.writeHeader(w)
+	fmt.Fprintf(w, "%s %s\n", gaugeFunc.metricName, formatValue(gaugeFunc.fn()))
+}
+
+//Histogram distribution of observed values
+type Histogram struct {
+	metric
+	buckets []float64
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []uint64 //per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+//NewHistogram create and register histogram. DefaultBuckets are used when buckets is nil
+func NewHistogram(name string, help string, buckets []float64, labelNames ...string) *Histogram {
+
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+
+	histogram := &Histogram{
+		metric:  metric{metricName: name, help: help, metricType: "histogram", labelNames: labelNames},
+		buckets: sorted,
+		series:  make(map[string]*histogramSeries),
+	}
+
+	DefaultRegistry.register(histogram)
+
+	return histogram
+
+}
+
+//Observe add observation
+func (histogram *Histogram) Observe(value float64, labelValues ...string) {
+
+	key := histogram.key(labelValues)
+
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	series, ok := histogram.series[key]
+	if !ok {
+		series = &histogramSeries{counts: make([]uint64, len(histogram.buckets))}
+		histogram.series[key] = series
+	}
+
+	index := sort.SearchFloat64s(histogram.buckets, value)
+	if index < len(histogram.buckets) {
+		series.counts[index]++
+	}
+
+	series.sum += value
+	series.count++
+
+}
+
+func (histogram *Histogram) write(w io.Writer) {
+
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	histogram.writeHeader(w)
+
+	keys := make([]string, 0, len(histogram.series))
+	for key := range histogram.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+
+		series := histogram.series[key]
+
+		var cumulative uint64
+		for i, upperBound := range histogram.buckets {
+			cumulative += series.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", histogram.metricName, formatLabels(histogram.labelNames, key, "le", formatValue(upperBound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", histogram.metricName, formatLabels(histogram.labelNames, key, "le", "+Inf"), series.count)
+
+		fmt.Fprintf(w, "%s_sum%s %s\n", histogram.metricName, formatLabels(histogram.labelNames, key, "", ""), formatValue(series.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", histogram.metricName, formatLabels(histogram.labelNames, key, "", ""), series.count)
+
+	}
+
+}
+
+func sortedKeys(values map[string]float64) []string {
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+
+}
+
+func formatLabels(labelNames []string, key string, extraName string, extraValue string) string {
+
+	pairs := []string{}
+
+	if len(labelNames) > 0 {
+		labelValues := strings.Split(key, labelValuesJOIN)
+		for i, labelName := range labelNames {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labelName, escapeLabelValue(labelValues[i])))
+		}
+	}
+
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", extraName, extraValue))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+
+}
+
+func formatValue(value float64) string {
+
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+
+}
+
+func escapeHelp(help string) string {
+	help = strings.Replace(help, "\\", "\\\\", -1)
+	return strings.Replace(help, "\n", "\\n", -1)
+}
+
+func escapeLabelValue(value string) string {
+	value = strings.Replace(value, "\\", "\\\\", -1)
+	value = strings.Replace(value, "\"", "\\\"", -1)
+	return strings.Replace(value, "\n", "\\n", -1)
+}
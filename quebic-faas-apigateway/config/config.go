@@ -27,7 +27,9 @@ type AppConfig struct {
 	CurrentDeploymentVersion string                `json:"currentDeploymentVersion"`
 	Auth                     AuthConfig            `json:"auth"`
 	ServerConfig             config.ServerConfig   `json:"serverConfig"`
+	GRPCServerConfig         config.ServerConfig   `json:"grpcServerConfig"`
 	EventBusConfig           config.EventBusConfig `json:"eventBusConfig"`
+	Tracing                  config.TracingConfig  `json:"tracing"`
 }
 
 //SetDefault set default
@@ -40,6 +42,11 @@ func (appConfig *AppConfig) SetDefault() {
 		Port: common.ApigatewayServerPort,
 	}
 
+	appConfig.GRPCServerConfig = config.ServerConfig{
+		Host: common.HostMachineIP,
+		Port: common.ApigatewayGRPCServerPort,
+	}
+
 	//No need port configurations. All services are run under same network
 	appConfig.EventBusConfig = config.EventBusConfig{
 		AMQPHost:           common.DockerServiceEventBus,
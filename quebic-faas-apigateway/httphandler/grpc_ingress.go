@@ -0,0 +1,302 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"quebic-faas/common"
+	"quebic-faas/grpcutil"
+	"quebic-faas/messenger"
+	"quebic-faas/types"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var grpcJSONMarshaler = &jsonpb.Marshaler{OrigName: true}
+var grpcJSONUnmarshaler = &jsonpb.Unmarshaler{AllowUnknownFields: true}
+
+//GRPCIngress grpc methods which are bound to events
+type GRPCIngress struct {
+	bindings atomic.Value //map[string]*grpcBinding. key => /<service>/<method>
+}
+
+type grpcBinding struct {
+	binding types.GRPCMethodBinding
+	input   *desc.MessageDescriptor
+	output  *desc.MessageDescriptor
+}
+
+//NewGRPCIngress create ingress without bindings
+func NewGRPCIngress() *GRPCIngress {
+	ingress := &GRPCIngress{}
+	ingress.bindings.Store(make(map[string]*grpcBinding))
+	return ingress
+}
+
+//setBindings replace all bindings. services with invalid descriptors are skipped
+func (ingress *GRPCIngress) setBindings(grpcServices []types.GRPCService) {
+
+	bindings := make(map[string]*grpcBinding)
+
+	for _, grpcService := range grpcServices {
+
+		serviceDescriptor, err := grpcutil.FindServiceDescriptor(grpcService.Descriptor, grpcService.Name)
+		if err != nil {
+			log.Printf("grpc-service %s skipped : %v", grpcService.Name, err)
+			continue
+		}
+
+		for _, binding := range grpcService.Methods {
+
+			methodDescriptor := serviceDescriptor.FindMethodByName(binding.Method)
+			if methodDescriptor == nil || methodDescriptor.IsClientStreaming() || methodDescriptor.IsServerStreaming() {
+				log.Printf("grpc-method %s/%s skipped : not found or streaming", grpcService.Name, binding.Method)
+				continue
+			}
+
+			fullMethod := "/" + grpcService.Name + "/" + binding.Method
+			bindings[fullMethod] = &grpcBinding{
+				binding: binding,
+				input:   methodDescriptor.GetInputType(),
+				output:  methodDescriptor.GetOutputType(),
+			}
+
+		}
+
+	}
+
+	ingress.bindings.Store(bindings)
+
+}
+
+func (ingress *GRPCIngress) getBinding(fullMethod string) *grpcBinding {
+	bindings := ingress.bindings.Load().(map[string]*grpcBinding)
+	return bindings[fullMethod]
+}
+
+//ServeGRPC start grpc server. every method is dispatched through the bindings
+func (httphandler *Httphandler) ServeGRPC(address string) error {
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return makeError("grpc listen failed : %v", err)
+	}
+
+	server := grpc.NewServer(
+		grpc.CustomCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(httphandler.grpcInvoke),
+	)
+
+	return server.Serve(listener)
+
+}
+
+func (httphandler *Httphandler) grpcInvoke(srv interface{}, stream grpc.ServerStream) error {
+
+	httphandler.usageUp()
+	defer httphandler.usageDown()
+
+	fullMethod, ok := grpc.MethodFromServerStream(stream)
+	if !ok {
+		return status.Errorf(codes.Internal, "unable to find grpc method")
+	}
+
+	binding := httphandler.GRPCIngress.getBinding(fullMethod)
+	if binding == nil {
+		return status.Errorf(codes.Unimplemented, "method %s is not bound to an event", fullMethod)
+	}
+
+	frame := &rawFrame{}
+	err := stream.RecvMsg(frame)
+	if err != nil {
+		return err
+	}
+
+	request := dynamic.NewMessage(binding.input)
+	err = request.Unmarshal(frame.payload)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "unable to parse request : %v", err)
+	}
+
+	requestJSON, err := request.MarshalJSONPB(grpcJSONMarshaler)
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to prepare payload : %v", err)
+	}
+
+	payload := make(map[string]interface{})
+	json.Unmarshal(requestJSON, &payload)
+
+	md, _ := metadata.FromIncomingContext(stream.Context())
+
+	for _, mappingTemplate := range binding.binding.HeaderMapping {
+		value := getMetadataValue(md, mappingTemplate.HeaderAttribute)
+		if value != "" {
+			payload[mappingTemplate.EventAttribute] = value
+		}
+	}
+
+	//user's metadata
+	requestHeaders := make(map[string]interface{})
+	for _, header := range binding.binding.HeadersToPass {
+		requestHeaders[header] = getMetadataValue(md, header)
+	}
+
+	requestHeaders["requestPath"] = fullMethod
+	requestHeaders["requestHTTPMethod"] = http.MethodPost
+	requestHeaders["async"] = strconv.FormatBool(false)
+
+	var requestID string
+	var replyPayload []byte
+	var replyErr error
+
+	requestTimeout := time.Second * time.Duration(binding.binding.RequestTimeout)
+
+	_, err = httphandler.Messenger.PublishBlocking(
+		binding.binding.Event,
+		payload,
+		requestHeaders,
+		func(message messenger.BaseEvent, statuscode int, context messenger.Context) {
+			requestID = context.RequestID
+			replyPayload = message.GetPayload()
+		},
+		func(message string, statuscode int, context messenger.Context) {
+			requestID = context.RequestID
+			replyErr = status.Error(grpcCodeFromHTTPStatus(statuscode), message)
+		},
+		requestTimeout,
+	)
+	if err != nil {
+		log.Printf("internal server error, cause : %s\n", err.Error())
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	stream.SetHeader(metadata.Pairs(common.HeaderRequestID, requestID))
+
+	if replyErr != nil {
+		return replyErr
+	}
+
+	response := dynamic.NewMessage(binding.output)
+	if len(replyPayload) > 0 {
+		err = response.UnmarshalJSONPB(grpcJSONUnmarshaler, replyPayload)
+		if err != nil {
+			return status.Errorf(codes.Internal, "function reply is not match to %s : %v", binding.output.GetFullyQualifiedName(), err)
+		}
+	}
+
+	responsePayload, err := response.Marshal()
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to prepare response : %v", err)
+	}
+
+	return stream.SendMsg(&rawFrame{payload: responsePayload})
+
+}
+
+func getMetadataValue(md metadata.MD, key string) string {
+
+	values := md[strings.ToLower(key)]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+
+}
+
+func grpcCodeFromHTTPStatus(statuscode int) codes.Code {
+
+	switch statuscode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+
+	if statuscode >= 500 {
+		return codes.Internal
+	}
+
+	return codes.Unknown
+
+}
+
+//rawFrame grpc message kept as bytes. messages are decoded with uploaded descriptors
+type rawFrame struct {
+	payload []byte
+}
+
+//rawCodec pass grpc messages through without generated types
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+
+	frame, ok := v.(*rawFrame)
+	if !ok {
+		return nil, fmt.Errorf("unexpected grpc message type %T", v)
+	}
+
+	return frame.payload, nil
+
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+
+	frame, ok := v.(*rawFrame)
+	if !ok {
+		return fmt.Errorf("unexpected grpc message type %T", v)
+	}
+
+	frame.payload = append([]byte(nil), data...)
+
+	return nil
+
+}
+
+func (rawCodec) String() string {
+	return "proto"
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
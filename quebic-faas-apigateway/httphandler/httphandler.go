@@ -35,7 +35,11 @@ type Httphandler struct {
 	Config        config.AppConfig
 	Messenger     _messenger.Messenger
 	AppStatusList map[string]string
+	ResponseCache *ResponseCache
+	WebSocketHub  *WebSocketHub
+	GRPCIngress   *GRPCIngress
 	Usage         int32
+	router        atomic.Value //*mux.Router. swapped on route reload
 }
 
 //SuccessResponse successResponse
@@ -59,22 +63,51 @@ type RequestTrackerResponse struct {
 func SetUpHTTPHandlers(
 	config config.AppConfig,
 	resources []types.Resource,
-	router *mux.Router,
+	grpcServices []types.GRPCService,
 	messenger _messenger.Messenger,
-	appStatusList map[string]string) {
+	appStatusList map[string]string) *Httphandler {
 
 	httphandler := &Httphandler{
 		Config:        config,
 		Messenger:     messenger,
 		AppStatusList: appStatusList,
+		ResponseCache: NewResponseCache(),
+		WebSocketHub:  NewWebSocketHub(),
+		GRPCIngress:   NewGRPCIngress(),
 	}
 
+	httphandler.registerUsageMetric()
+
 	//check wether this deployment is latest version
 	httphandler.checkForShutdown(config.CurrentDeploymentVersion)
 
 	httphandler.listeningForNewVersion()
 
+	httphandler.ResponseCache.Reset(resources)
+	httphandler.listeningForFunctionNewVersion()
+
+	httphandler.listeningForWebSocketSend()
+
+	httphandler.router.Store(httphandler.prepareRouter(resources))
+	httphandler.GRPCIngress.setBindings(grpcServices)
+	httphandler.listeningForRouteChange()
+
+	return httphandler
+
+}
+
+//ServeHTTP dispatch request into the current router
+func (httphandler *Httphandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	router := httphandler.router.Load().(*mux.Router)
+	router.ServeHTTP(w, r)
+}
+
+func (httphandler *Httphandler) prepareRouter(resources []types.Resource) *mux.Router {
+
+	router := mux.NewRouter()
+
 	httphandler.healthCheckEndpointHandler(router)
+	httphandler.metricsEndpointHandler(router)
 	httphandler.requestTrackerHandler(router)
 
 	//go throught each resource
@@ -82,6 +115,8 @@ func SetUpHTTPHandlers(
 		httphandler.createHandler(router, resource)
 	}
 
+	return router
+
 }
 
 func (httphandler *Httphandler) healthCheckEndpointHandler(router *mux.Router) {
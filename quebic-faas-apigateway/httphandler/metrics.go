@@ -0,0 +1,94 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"quebic-faas/common"
+	"quebic-faas/metrics"
+	"quebic-faas/types"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+var requestsTotal = metrics.NewCounter(
+	"quebic_apigateway_requests_total",
+	"http requests served per resource",
+	"resource", "method", "status")
+
+var requestDuration = metrics.NewHistogram(
+	"quebic_apigateway_request_duration_seconds",
+	"http request latency per resource",
+	nil,
+	"resource", "method")
+
+func (httphandler *Httphandler) metricsEndpointHandler(router *mux.Router) {
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+}
+
+func (httphandler *Httphandler) registerUsageMetric() {
+	metrics.NewGaugeFunc(
+		"quebic_apigateway_requests_in_flight",
+		"requests currently served by this apigateway",
+		func() float64 {
+			return float64(httphandler.getUsage())
+		})
+}
+
+//observeRequest record status and latency of the resource request
+func observeRequest(resource types.Resource, w *statusRecorder, start time.Time) {
+
+	resourceID := resource.GetID()
+
+	requestsTotal.Inc(resourceID, resource.RequestMethod, common.IntToStr(w.status))
+	requestDuration.Observe(time.Since(start).Seconds(), resourceID, resource.RequestMethod)
+
+}
+
+//statusRecorder keeps the response status. flushing and hijacking are passed to the underline writer
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func newStatusRecorder(w http.ResponseWriter) *statusRecorder {
+	return &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *statusRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (recorder *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+
+	hijacker, ok := recorder.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+
+	return hijacker.Hijack()
+
+}
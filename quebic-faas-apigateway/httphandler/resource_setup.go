@@ -19,6 +19,7 @@ import (
 	"net/http"
 	"quebic-faas/common"
 	"quebic-faas/messenger"
+	"quebic-faas/tracing"
 	"quebic-faas/types"
 	"strconv"
 	"time"
@@ -31,9 +32,17 @@ func (httphandler *Httphandler) createHandler(router *mux.Router, resource types
 	url := resource.URL
 	requestMethod := resource.RequestMethod
 
+	if requestMethod == common.ResourceRequestMethodWebSocket {
+		httphandler.createWebSocketHandler(router, resource)
+		return
+	}
+
 	router.HandleFunc(url, func(w http.ResponseWriter, r *http.Request) {
 		httphandler.usageUp()
-		httphandler.eventInvoke(w, r, resource)
+		start := time.Now()
+		recorder := newStatusRecorder(w)
+		httphandler.eventInvoke(recorder, r, resource)
+		observeRequest(resource, recorder, start)
 		httphandler.usageDown()
 	}).Methods(requestMethod)
 
@@ -41,31 +50,37 @@ func (httphandler *Httphandler) createHandler(router *mux.Router, resource types
 
 func (httphandler *Httphandler) eventInvoke(w http.ResponseWriter, r *http.Request, resource types.Resource) {
 
-	payload := make(map[string]interface{})
-	processRequest(r, &payload)
-
-	for _, mappingTemplate := range resource.RequestMapping {
-		httphandler.processForRequestMapping(r, mappingTemplate, payload)
+	//gateway receive span. parent comes from the caller's traceparent
+	span := tracing.StartSpan(
+		"receive "+r.Method+" "+resource.URL,
+		tracing.SpanKindServer,
+		tracing.ParseTraceparent(r.Header.Get(common.HeaderTraceparent)))
+	span.SetAttribute("http.method", r.Method)
+	span.SetAttribute("http.route", resource.URL)
+	span.SetAttribute("faas.event", resource.Event)
+	defer span.Finish()
+
+	cacheable := isCacheable(r, resource)
+	var cacheKey string
+	if cacheable {
+		cacheKey = prepareCacheKey(r, resource.Cache)
+		if httphandler.serveFromCache(w, r, cacheKey) {
+			span.SetAttribute("faas.cache", "HIT")
+			return
+		}
 	}
 
-	for _, mappingTemplate := range resource.HeaderMapping {
-		httphandler.processForHeaderMapping(r, mappingTemplate, payload)
-	}
+	payload := httphandler.prepareEventPayload(r, resource)
+	requestHeaders := prepareEventHeaders(r, resource)
+	requestHeaders[common.HeaderTraceparent] = span.Traceparent()
 
-	//user's headers
-	requestHeaders := make(map[string]interface{})
-	for _, header := range resource.HeadersToPass {
-		requestHeaders[header] = r.Header.Get(header)
-	}
+	m := httphandler.Messenger
 
-	//set request http method
-	requestHeaders["requestPath"] = r.RequestURI
-	requestHeaders["requestHTTPMethod"] = r.Method
-	requestHeaders["async"] = strconv.FormatBool(resource.Async)
+	if resource.Stream != "" {
 
-	m := httphandler.Messenger
+		httphandler.streamInvoke(w, resource, payload, requestHeaders)
 
-	if !resource.Async {
+	} else if !resource.Async {
 
 		requestTimeout := time.Second * time.Duration(resource.RequestTimeout)
 
@@ -79,11 +94,17 @@ func (httphandler *Httphandler) eventInvoke(w http.ResponseWriter, r *http.Reque
 					statuscode = resource.SuccessResponseStatus
 				}
 
+				if cacheable {
+					httphandler.writeCacheableResponse(w, r, resource, cacheKey, statuscode, message.GetPayloadAsObject(), context.RequestID)
+					return
+				}
+
 				makeAPIGatewaySuccessResponse(w, statuscode, message.GetPayloadAsObject(), context.RequestID)
 
 			},
 			func(message string, statuscode int, context messenger.Context) {
 
+				span.SetError(message)
 				makeAPIGatewayErrorResponse(w, statuscode, message, context.RequestID)
 
 			},
@@ -93,6 +114,7 @@ func (httphandler *Httphandler) eventInvoke(w http.ResponseWriter, r *http.Reque
 
 			log.Printf("internal server error, cause : %s\n", err.Error())
 
+			span.SetError(err.Error())
 			makeErrorResponse(w, http.StatusInternalServerError, err)
 			return
 		}
@@ -112,6 +134,7 @@ func (httphandler *Httphandler) eventInvoke(w http.ResponseWriter, r *http.Reque
 
 			log.Printf("internal server error, cause : %s\n", err.Error())
 
+			span.SetError(err.Error())
 			makeErrorResponse(w, http.StatusInternalServerError, err)
 			return
 		}
@@ -122,6 +145,92 @@ func (httphandler *Httphandler) eventInvoke(w http.ResponseWriter, r *http.Reque
 
 }
 
+func (httphandler *Httphandler) prepareEventPayload(r *http.Request, resource types.Resource) map[string]interface{} {
+
+	payload := make(map[string]interface{})
+	processRequest(r, &payload)
+
+	for _, mappingTemplate := range resource.RequestMapping {
+		httphandler.processForRequestMapping(r, mappingTemplate, payload)
+	}
+
+	for _, mappingTemplate := range resource.HeaderMapping {
+		httphandler.processForHeaderMapping(r, mappingTemplate, payload)
+	}
+
+	return payload
+
+}
+
+func prepareEventHeaders(r *http.Request, resource types.Resource) map[string]interface{} {
+
+	//user's headers
+	requestHeaders := make(map[string]interface{})
+	for _, header := range resource.HeadersToPass {
+		requestHeaders[header] = r.Header.Get(header)
+	}
+
+	//set request http method
+	requestHeaders["requestPath"] = r.RequestURI
+	requestHeaders["requestHTTPMethod"] = r.Method
+	requestHeaders["async"] = strconv.FormatBool(resource.Async)
+	requestHeaders["stream"] = resource.Stream
+
+	return requestHeaders
+
+}
+
+//streamInvoke forward partial replies until function complete the stream
+func (httphandler *Httphandler) streamInvoke(
+	w http.ResponseWriter,
+	resource types.Resource,
+	payload map[string]interface{},
+	requestHeaders map[string]interface{}) {
+
+	stream := newStreamWriter(w, resource.Stream)
+
+	//applied between two replies
+	requestTimeout := time.Second * time.Duration(resource.RequestTimeout)
+
+	_, err := httphandler.Messenger.PublishStream(
+		resource.Event,
+		payload,
+		requestHeaders,
+		func(message messenger.BaseEvent, statuscode int, context messenger.Context) {
+
+			if statuscode == 0 {
+				statuscode = resource.SuccessResponseStatus
+			}
+
+			stream.partial(message.GetPayload(), statuscode, context.RequestID)
+
+		},
+		func(message messenger.BaseEvent, statuscode int, context messenger.Context) {
+
+			if statuscode == 0 {
+				statuscode = resource.SuccessResponseStatus
+			}
+
+			stream.complete(message.GetPayload(), statuscode, context.RequestID)
+
+		},
+		func(message string, statuscode int, context messenger.Context) {
+
+			stream.fail(message, statuscode, context.RequestID)
+
+		},
+		requestTimeout,
+	)
+	if err != nil {
+
+		log.Printf("internal server error, cause : %s\n", err.Error())
+
+		stream.fail(err.Error(), http.StatusInternalServerError, "")
+		return
+	}
+
+}
+
 //check wether requestExpressions value is came as request parms
 func (httphandler *Httphandler) processForRequestMapping(r *http.Request, mappingTemplate types.RequestMappingTemplate, payload map[string]interface{}) {
 
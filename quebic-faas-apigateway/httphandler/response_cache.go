@@ -0,0 +1,326 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"quebic-faas/common"
+	_messenger "quebic-faas/messenger"
+	"quebic-faas/types"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const responseCacheMaxEntries = 10000
+
+const cacheStatusHit = "HIT"
+const cacheStatusMiss = "MISS"
+const cacheStatusBypass = "BYPASS"
+
+const cacheControlNoCache = "no-cache"
+const cacheControlNoStore = "no-store"
+
+//ResponseCache in-memory response cache shared by every resource of this apigateway
+type ResponseCache struct {
+	mutex            sync.RWMutex
+	entries          map[string]*responseCacheEntry
+	invalidateEvents map[string][]string //function-name : events served by the function
+}
+
+type responseCacheEntry struct {
+	event     string
+	status    int
+	body      []byte
+	etag      string
+	expiresAt time.Time
+}
+
+//NewResponseCache create empty response cache
+func NewResponseCache() *ResponseCache {
+	return &ResponseCache{
+		entries:          make(map[string]*responseCacheEntry),
+		invalidateEvents: make(map[string][]string),
+	}
+}
+
+func (cache *ResponseCache) get(key string) *responseCacheEntry {
+
+	cache.mutex.RLock()
+	entry := cache.entries[key]
+	cache.mutex.RUnlock()
+
+	if entry == nil {
+		return nil
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		cache.mutex.Lock()
+		delete(cache.entries, key)
+		cache.mutex.Unlock()
+		return nil
+	}
+
+	return entry
+
+}
+
+func (cache *ResponseCache) put(key string, entry *responseCacheEntry) {
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if len(cache.entries) >= responseCacheMaxEntries {
+		cache.removeExpired()
+	}
+
+	//still full. skip caching rather than evicting live entries
+	if len(cache.entries) >= responseCacheMaxEntries {
+		return
+	}
+
+	cache.entries[key] = entry
+
+}
+
+//InvalidateFunction remove every cached entry which is served by the function
+func (cache *ResponseCache) InvalidateFunction(functionName string) int {
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	removed := 0
+	for _, event := range cache.invalidateEvents[functionName] {
+		for key, entry := range cache.entries {
+			if entry.event == event {
+				delete(cache.entries, key)
+				removed++
+			}
+		}
+	}
+
+	return removed
+
+}
+
+//Reset drop all cached entries and re-map functions into events of the given resources
+func (cache *ResponseCache) Reset(resources []types.Resource) {
+
+	invalidateEvents := prepareCacheInvalidateEvents(resources)
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.entries = make(map[string]*responseCacheEntry)
+	cache.invalidateEvents = invalidateEvents
+
+}
+
+//removeExpired caller should hold the lock
+func (cache *ResponseCache) removeExpired() {
+	now := time.Now()
+	for key, entry := range cache.entries {
+		if now.After(entry.expiresAt) {
+			delete(cache.entries, key)
+		}
+	}
+}
+
+//prepareCacheKey key => method|path|q:name=value..|h:name=value..
+func prepareCacheKey(r *http.Request, resourceCache types.ResourceCache) string {
+
+	key := r.Method + "|" + r.URL.Path
+
+	query := r.URL.Query()
+	queryParams := append([]string{}, resourceCache.QueryParams...)
+	sort.Strings(queryParams)
+	for _, q := range queryParams {
+		key = key + "|q:" + q + "=" + strings.Join(query[q], ",")
+	}
+
+	headers := append([]string{}, resourceCache.Headers...)
+	sort.Strings(headers)
+	for _, h := range headers {
+		key = key + "|h:" + strings.ToLower(h) + "=" + r.Header.Get(h)
+	}
+
+	return key
+
+}
+
+func isCacheable(r *http.Request, resource types.Resource) bool {
+	return resource.Cache.Enabled && resource.Cache.TTL > 0 && !resource.Async && r.Method == http.MethodGet
+}
+
+func requestCacheControlHas(r *http.Request, directive string) bool {
+	for _, d := range strings.Split(r.Header.Get("Cache-Control"), ",") {
+		if strings.TrimSpace(strings.ToLower(d)) == directive {
+			return true
+		}
+	}
+	return false
+}
+
+//serveFromCache write cached response. return false if there is nothing to serve
+func (httphandler *Httphandler) serveFromCache(w http.ResponseWriter, r *http.Request, cacheKey string) bool {
+
+	if requestCacheControlHas(r, cacheControlNoCache) || requestCacheControlHas(r, cacheControlNoStore) {
+		return false
+	}
+
+	entry := httphandler.ResponseCache.get(cacheKey)
+	if entry == nil {
+		return false
+	}
+
+	maxAge := int(entry.expiresAt.Sub(time.Now()).Seconds())
+	w.Header().Set("ETag", entry.etag)
+	w.Header().Set("Cache-Control", "max-age="+common.IntToStr(maxAge))
+	w.Header().Set(common.HeaderCacheStatus, cacheStatusHit)
+
+	if r.Header.Get("If-None-Match") == entry.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(entry.status)
+	w.Write(entry.body)
+
+	return true
+
+}
+
+//writeCacheableResponse write success response and keep it in the cache
+func (httphandler *Httphandler) writeCacheableResponse(
+	w http.ResponseWriter,
+	r *http.Request,
+	resource types.Resource,
+	cacheKey string,
+	status int,
+	message interface{},
+	requestID string) {
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(SuccessResponse{Status: status, Message: message})
+	if err != nil {
+		makeAPIGatewayErrorResponse(w, http.StatusInternalServerError, err.Error(), requestID)
+		return
+	}
+
+	w.Header().Set(common.HeaderRequestID, requestID)
+
+	//only success responses are cached
+	if status >= 300 || requestCacheControlHas(r, cacheControlNoStore) {
+		w.Header().Set(common.HeaderCacheStatus, cacheStatusBypass)
+	} else {
+
+		checksum := sha1.Sum(body.Bytes())
+		etag := "\"" + hex.EncodeToString(checksum[:]) + "\""
+
+		httphandler.ResponseCache.put(cacheKey, &responseCacheEntry{
+			event:     resource.Event,
+			status:    status,
+			body:      body.Bytes(),
+			etag:      etag,
+			expiresAt: time.Now().Add(time.Second * time.Duration(resource.Cache.TTL)),
+		})
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "max-age="+common.IntToStr(resource.Cache.TTL))
+		w.Header().Set(common.HeaderCacheStatus, cacheStatusMiss)
+
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	w.Write(body.Bytes())
+
+}
+
+//prepareCacheInvalidateEvents function-name : events served by the function
+func prepareCacheInvalidateEvents(resources []types.Resource) map[string][]string {
+
+	invalidateEvents := make(map[string][]string)
+
+	for _, resource := range resources {
+
+		if !resource.Cache.Enabled {
+			continue
+		}
+
+		//function invoker route
+		if strings.HasPrefix(resource.Event, common.EventPrefixFunction+common.EventJOIN) {
+			functionName := strings.TrimPrefix(resource.Event, common.EventPrefixFunction+common.EventJOIN)
+			invalidateEvents[functionName] = append(invalidateEvents[functionName], resource.Event)
+		}
+
+		for _, functionName := range resource.Cache.Functions {
+			invalidateEvents[functionName] = append(invalidateEvents[functionName], resource.Event)
+		}
+
+	}
+
+	return invalidateEvents
+
+}
+
+//listeningForFunctionNewVersion invalidate cached entries when backing function is re-deployed
+func (httphandler *Httphandler) listeningForFunctionNewVersion() {
+
+	//each replica needs own queue to receive every new-version event
+	appID := httphandler.Config.DeploymentID + "-" + common.UUIDGen()
+	messenger := _messenger.Messenger{
+		AppID:          appID,
+		EventBusConfig: httphandler.Config.EventBusConfig,
+	}
+	err := messenger.Init()
+	if err != nil {
+		log.Printf("response-cache invalidate listener setup failed : %v", err)
+		return
+	}
+
+	//quebic-faas-event.internal.new-version.function.<function-name>
+	newVersionEvents := common.EventNewVersionFunctionPrefix + "#"
+
+	err = messenger.Subscribe(newVersionEvents, func(event _messenger.BaseEvent) {
+
+		functionName := strings.TrimPrefix(event.GetEventID(), common.EventNewVersionFunctionPrefix)
+		removed := httphandler.ResponseCache.InvalidateFunction(functionName)
+
+		if removed > 0 {
+			log.Printf("response-cache invalidated %v entries for function %s", removed, functionName)
+		}
+
+	}, common.ConsumerResponseCacheInvalidate)
+	if err != nil {
+		log.Printf("response-cache invalidate listener setup failed : %v", err)
+		return
+	}
+
+	log.Printf("response-cache invalidate listener setup")
+
+}
@@ -0,0 +1,120 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"fmt"
+	"log"
+	"quebic-faas/common"
+	_messenger "quebic-faas/messenger"
+	"quebic-faas/types"
+	"time"
+)
+
+//listeningForRouteChange reload routes in place when manager broadcast a route change
+func (httphandler *Httphandler) listeningForRouteChange() {
+
+	//each replica needs own queue to receive every route-change event
+	appID := httphandler.Config.DeploymentID + "-" + common.UUIDGen()
+	messenger := _messenger.Messenger{
+		AppID:          appID,
+		EventBusConfig: httphandler.Config.EventBusConfig,
+	}
+	err := messenger.Init()
+	if err != nil {
+		log.Printf("route-change-listener setup failed : %v", err)
+		return
+	}
+
+	//events are consumed one by one. so reloads never overlap
+	err = messenger.Subscribe(common.EventRouteChange, func(event _messenger.BaseEvent) {
+
+		routeChange := types.RouteChangeMessage{}
+		event.ParsePayloadAsObject(&routeChange)
+
+		log.Printf("route-change received : %v", routeChange.Route)
+
+		err := httphandler.reloadRoutes()
+		if err != nil {
+			log.Printf("route reload failed. keep serving current routes : %v", err)
+		}
+
+	}, common.ConsumerRouteChangeAPIGateway)
+	if err != nil {
+		log.Printf("route-change-listener setup failed : %v", err)
+		return
+	}
+
+	log.Printf("route-change-listener setup")
+
+}
+
+//reloadRoutes fetch latest routes from manager and swap the router
+func (httphandler *Httphandler) reloadRoutes() error {
+
+	apigatewayData, err := httphandler.fetchAPIGatewayData()
+	if err != nil {
+		return err
+	}
+
+	resources := apigatewayData.Resources
+
+	//in-flight requests complete on the router they were dispatched to
+	httphandler.router.Store(httphandler.prepareRouter(resources))
+	httphandler.GRPCIngress.setBindings(apigatewayData.GRPCServices)
+
+	//cache settings might be changed with routes
+	httphandler.ResponseCache.Reset(resources)
+
+	log.Printf("routes reloaded. resources : %v, grpc-services : %v", len(resources), len(apigatewayData.GRPCServices))
+
+	return nil
+
+}
+
+func (httphandler *Httphandler) fetchAPIGatewayData() (*types.ApigatewayData, error) {
+
+	managerAccessKey := httphandler.Config.Auth.Accesstoken
+	requestHeaders := make(map[string]interface{})
+	requestHeaders[common.HeaderAccessKey] = managerAccessKey
+
+	apigatewayData := &types.ApigatewayData{}
+	var fetchErr error
+
+	_, err := httphandler.Messenger.PublishBlocking(
+		common.EventApigatewayDataFetch,
+		"",
+		requestHeaders,
+		func(message _messenger.BaseEvent, status int, context _messenger.Context) {
+
+			message.ParsePayloadAsObject(apigatewayData)
+
+		},
+		func(err string, statuscode int, context _messenger.Context) {
+			fetchErr = fmt.Errorf("apigateway-data fetch failed : %v", err)
+		},
+		time.Second*5,
+	)
+	if err != nil {
+		return nil, makeError("apigateway-data fetch failed : %v", err)
+	}
+
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+
+	return apigatewayData, nil
+
+}
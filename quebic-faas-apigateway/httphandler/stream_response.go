@@ -0,0 +1,157 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"encoding/json"
+	"net/http"
+	"quebic-faas/common"
+	"strings"
+	"sync"
+)
+
+const sseEventComplete = "complete"
+const sseEventError = "error"
+
+//streamWriter forward partial replies of a function into the http response
+type streamWriter struct {
+	mutex     sync.Mutex
+	w         http.ResponseWriter
+	mode      string //chunked or sse
+	requestID string
+	started   bool
+	closed    bool
+}
+
+func newStreamWriter(w http.ResponseWriter, mode string) *streamWriter {
+	return &streamWriter{w: w, mode: mode}
+}
+
+//start write headers. status of the first reply become the http status
+func (stream *streamWriter) start(status int) {
+
+	if stream.started {
+		return
+	}
+
+	stream.started = true
+
+	if stream.mode == common.ResourceStreamSSE {
+		stream.w.Header().Set("Content-Type", "text/event-stream; charset=UTF-8")
+		stream.w.Header().Set("Cache-Control", "no-cache")
+		stream.w.Header().Set("Connection", "keep-alive")
+	} else {
+		stream.w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	}
+
+	stream.w.Header().Set("X-Accel-Buffering", "no")
+	stream.w.Header().Set(common.HeaderRequestID, stream.requestID)
+	stream.w.WriteHeader(status)
+
+}
+
+func (stream *streamWriter) partial(payload []byte, status int, requestID string) {
+
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+
+	if stream.closed {
+		return
+	}
+
+	stream.requestID = requestID
+	stream.start(status)
+	stream.write("", payload)
+
+}
+
+//complete write completion marker and close the stream
+func (stream *streamWriter) complete(payload []byte, status int, requestID string) {
+
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+
+	if stream.closed {
+		return
+	}
+
+	stream.requestID = requestID
+	stream.start(status)
+
+	if stream.mode == common.ResourceStreamSSE {
+		stream.write(sseEventComplete, payload)
+	} else if len(payload) > 0 {
+		stream.write("", payload)
+	}
+
+	stream.closed = true
+
+}
+
+//fail write error. before the stream started it is a normal error response
+func (stream *streamWriter) fail(message string, status int, requestID string) {
+
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+
+	if stream.closed {
+		return
+	}
+
+	stream.closed = true
+
+	if !stream.started {
+		makeAPIGatewayErrorResponse(stream.w, status, message, requestID)
+		return
+	}
+
+	errorJSON, _ := json.Marshal(ErrorResponse{Status: status, Cause: message})
+
+	if stream.mode == common.ResourceStreamSSE {
+		stream.write(sseEventError, errorJSON)
+	} else {
+		stream.write("", errorJSON)
+	}
+
+}
+
+func (stream *streamWriter) write(event string, payload []byte) {
+
+	if stream.mode == common.ResourceStreamSSE {
+
+		var frame string
+		if event != "" {
+			frame = "event: " + event + "\n"
+		}
+
+		//each line of the payload become a data field
+		for _, line := range strings.Split(string(payload), "\n") {
+			frame = frame + "data: " + strings.TrimSuffix(line, "\r") + "\n"
+		}
+
+		stream.w.Write([]byte(frame + "\n"))
+
+	} else {
+
+		stream.w.Write(payload)
+		stream.w.Write([]byte("\n"))
+
+	}
+
+	if flusher, ok := stream.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+}
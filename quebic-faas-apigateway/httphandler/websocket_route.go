@@ -0,0 +1,236 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"quebic-faas/common"
+	_messenger "quebic-faas/messenger"
+	"quebic-faas/types"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+const websocketMaxMessageSize = 1024 * 1024
+const websocketWriteTimeout = time.Second * 10
+
+var websocketUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+//WebSocketHub websocket connections held by this apigateway replica
+type WebSocketHub struct {
+	ReplicaID   string
+	mutex       sync.RWMutex
+	connections map[string]*websocketConnection
+}
+
+type websocketConnection struct {
+	conn       *websocket.Conn
+	writeMutex sync.Mutex //gorilla websocket allows only one concurrent writer
+}
+
+//NewWebSocketHub create hub. replicaID is used to route function replies back to this replica
+func NewWebSocketHub() *WebSocketHub {
+	return &WebSocketHub{
+		ReplicaID:   common.UUIDGen(),
+		connections: make(map[string]*websocketConnection),
+	}
+}
+
+func (hub *WebSocketHub) add(conn *websocket.Conn) string {
+
+	//connection-id => <apigateway-replica-id>.<uuid>
+	connectionID := hub.ReplicaID + common.EventJOIN + common.UUIDGen()
+
+	hub.mutex.Lock()
+	hub.connections[connectionID] = &websocketConnection{conn: conn}
+	hub.mutex.Unlock()
+
+	return connectionID
+
+}
+
+func (hub *WebSocketHub) remove(connectionID string) {
+	hub.mutex.Lock()
+	delete(hub.connections, connectionID)
+	hub.mutex.Unlock()
+}
+
+func (hub *WebSocketHub) get(connectionID string) *websocketConnection {
+	hub.mutex.RLock()
+	defer hub.mutex.RUnlock()
+	return hub.connections[connectionID]
+}
+
+func (connection *websocketConnection) write(messageType int, data []byte) error {
+
+	connection.writeMutex.Lock()
+	defer connection.writeMutex.Unlock()
+
+	connection.conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+	return connection.conn.WriteMessage(messageType, data)
+
+}
+
+func (httphandler *Httphandler) createWebSocketHandler(router *mux.Router, resource types.Resource) {
+
+	router.HandleFunc(resource.URL, func(w http.ResponseWriter, r *http.Request) {
+		httphandler.websocketInvoke(w, r, resource)
+	}).Methods(common.ResourceRequestMethodGET)
+
+}
+
+//websocketInvoke hold the socket and publish connection events into resource's event
+//long lived sockets are not counted as usage. otherwise new version never get free
+func (httphandler *Httphandler) websocketInvoke(w http.ResponseWriter, r *http.Request, resource types.Resource) {
+
+	payload := httphandler.prepareEventPayload(r, resource)
+	requestHeaders := prepareEventHeaders(r, resource)
+
+	conn, err := websocketUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		//upgrader already replied to the client
+		log.Printf("websocket upgrade failed : %v", err)
+		return
+	}
+	defer conn.Close()
+
+	conn.SetReadLimit(websocketMaxMessageSize)
+
+	hub := httphandler.WebSocketHub
+	connectionID := hub.add(conn)
+	defer hub.remove(connectionID)
+
+	requestHeaders[common.HeaderWebSocketConnectionID] = connectionID
+
+	httphandler.publishWebSocketEvent(resource, common.WebSocketEventConnect, payload, requestHeaders)
+
+	for {
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("websocket %s closed unexpectedly : %v", connectionID, err)
+			}
+			break
+		}
+
+		httphandler.publishWebSocketEvent(resource, common.WebSocketEventMessage, parseWebSocketMessage(message), requestHeaders)
+
+	}
+
+	httphandler.publishWebSocketEvent(resource, common.WebSocketEventDisconnect, nil, requestHeaders)
+
+}
+
+func (httphandler *Httphandler) publishWebSocketEvent(
+	resource types.Resource,
+	websocketEvent string,
+	payload interface{},
+	requestHeaders map[string]interface{}) {
+
+	httphandler.usageUp()
+	defer httphandler.usageDown()
+
+	headers := make(map[string]interface{})
+	for k, v := range requestHeaders {
+		headers[k] = v
+	}
+	headers[common.HeaderWebSocketEvent] = websocketEvent
+
+	_, err := httphandler.Messenger.Publish(
+		resource.Event,
+		payload,
+		headers,
+		nil,
+		nil,
+		0,
+	)
+	if err != nil {
+		log.Printf("websocket %s event publish failed : %v", websocketEvent, err)
+	}
+
+}
+
+//parseWebSocketMessage json messages are passed as objects. others as plain string
+func parseWebSocketMessage(message []byte) interface{} {
+
+	payload := make(map[string]interface{})
+	err := json.Unmarshal(message, &payload)
+	if err != nil {
+		return string(message)
+	}
+
+	return payload
+
+}
+
+//listeningForWebSocketSend deliver function replies into sockets held by this replica
+func (httphandler *Httphandler) listeningForWebSocketSend() {
+
+	hub := httphandler.WebSocketHub
+
+	appID := httphandler.Config.DeploymentID + "-" + hub.ReplicaID
+	messenger := _messenger.Messenger{
+		AppID:          appID,
+		EventBusConfig: httphandler.Config.EventBusConfig,
+	}
+	err := messenger.Init()
+	if err != nil {
+		log.Printf("websocket-send-listener setup failed : %v", err)
+		return
+	}
+
+	//quebic-faas-event.internal.websocket-send.<apigateway-replica-id>.<uuid>
+	sendEvents := common.EventWebSocketSendPrefix + hub.ReplicaID + common.EventJOIN + "*"
+
+	err = messenger.Subscribe(sendEvents, func(event _messenger.BaseEvent) {
+
+		connectionID := strings.TrimPrefix(event.GetEventID(), common.EventWebSocketSendPrefix)
+
+		connection := hub.get(connectionID)
+		if connection == nil {
+			log.Printf("websocket %s not found", connectionID)
+			return
+		}
+
+		if event.GetHeaderData(common.HeaderWebSocketClose) == "true" {
+			connection.write(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			connection.conn.Close()
+			return
+		}
+
+		err := connection.write(websocket.TextMessage, event.GetPayload())
+		if err != nil {
+			log.Printf("websocket %s write failed : %v", connectionID, err)
+		}
+
+	}, common.ConsumerWebSocketSend)
+	if err != nil {
+		log.Printf("websocket-send-listener setup failed : %v", err)
+		return
+	}
+
+	log.Printf("websocket-send-listener setup")
+
+}
@@ -27,10 +27,9 @@ import (
 	_messenger "quebic-faas/messenger"
 	"quebic-faas/quebic-faas-apigateway/config"
 	"quebic-faas/quebic-faas-apigateway/httphandler"
+	"quebic-faas/tracing"
 	"quebic-faas/types"
 	"time"
-
-	"github.com/gorilla/mux"
 )
 
 const appStatusKeyEventbusConnect = "eventbus-connect-failed"
@@ -40,7 +39,7 @@ const appStatusKeyApigatewatDataFetch = "apigateway-data-fetch-failed"
 type App struct {
 	config        config.AppConfig
 	resources     []types.Resource
-	router        *mux.Router
+	grpcServices  []types.GRPCService
 	messenger     _messenger.Messenger
 	appStatusList map[string]string
 }
@@ -51,6 +50,9 @@ func (app *App) Start() {
 	//setup configuration
 	app.setupConfiguration()
 
+	//setup tracing
+	app.setupTracing()
+
 	//setup messenger
 	app.setupMessenger()
 
@@ -58,7 +60,6 @@ func (app *App) Start() {
 	app.loadAPIGatewayData()
 
 	//setup router
-	app.router = mux.NewRouter()
 	app.setUpHTTPHandlers()
 
 }
@@ -100,26 +101,51 @@ func (app *App) setupEnvVariables() {
 		app.config.EventBusConfig.ManagementPassword = rabbitmq_management_password
 	}
 
+	app.config.Tracing.Exporter = os.Getenv(common.EnvKey_tracing_exporter)
+	app.config.Tracing.OTLPEndpoint = os.Getenv(common.EnvKey_tracing_otlpEndpoint)
+	app.config.Tracing.FilePath = os.Getenv(common.EnvKey_tracing_filePath)
+
+}
+
+func (app *App) setupTracing() {
+
+	err := tracing.Init(app.config.AppID, app.config.Tracing)
+	if err != nil {
+		log.Printf("tracing setup failed. spans are not exported. error : %v", err)
+	}
+
 }
 
 func (app *App) setUpHTTPHandlers() {
 
-	router := app.router
 	messenger := app.messenger
 	appStatusList := app.appStatusList
 
-	httphandler.SetUpHTTPHandlers(
+	handler := httphandler.SetUpHTTPHandlers(
 		app.config,
 		app.resources,
-		router,
+		app.grpcServices,
 		messenger,
 		appStatusList)
 
+	grpcAddress := app.config.GRPCServerConfig.Host + ":" + common.IntToStr(app.config.GRPCServerConfig.Port)
+
+	go func() {
+
+		log.Printf("quebic-faas apigateway grpc start %s\n", grpcAddress)
+
+		err := handler.ServeGRPC(grpcAddress)
+		if err != nil {
+			log.Printf("quebic-faas apigateway grpc start failed. error : %v", err)
+		}
+
+	}()
+
 	address := app.config.ServerConfig.Host + ":" + common.IntToStr(app.config.ServerConfig.Port)
 
 	log.Printf("quebic-faas apigateway start %s\n", address)
 
-	err := http.ListenAndServe(address, router)
+	err := http.ListenAndServe(address, handler)
 	if err != nil {
 		log.Panicf("quebic-faas apigateway start failed. error : %v", err)
 	}
@@ -154,6 +180,7 @@ func (app *App) loadAPIGatewayData() {
 			apigatewayData := &types.ApigatewayData{}
 			message.ParsePayloadAsObject(apigatewayData)
 			app.resources = apigatewayData.Resources
+			app.grpcServices = apigatewayData.GRPCServices
 			app.config.CurrentDeploymentVersion = apigatewayData.CurrentDeploymentVersion
 
 			log.Printf("apigateway-data fetched")
@@ -0,0 +1,160 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"quebic-faas/common"
+	"quebic-faas/types"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var auditEntity string
+var auditEntityID string
+var auditActor string
+var auditFrom string
+var auditTo string
+var auditLimit int
+var auditCursor string
+var auditChanges bool
+
+func init() {
+	setupAuditCmds()
+	setupAuditFlags()
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit commonds",
+	Long:  `Audit commonds`,
+}
+
+func setupAuditCmds() {
+	auditCmd.AddCommand(auditGetALLCmd)
+}
+
+func setupAuditFlags() {
+
+	auditGetALLCmd.PersistentFlags().StringVarP(&auditEntity, "entity", "e", "", "entity. eg : functions, routes, users")
+	auditGetALLCmd.PersistentFlags().StringVarP(&auditEntityID, "id", "i", "", "entity id. use with entity")
+	auditGetALLCmd.PersistentFlags().StringVarP(&auditActor, "actor", "a", "", "user who made the change")
+	auditGetALLCmd.PersistentFlags().StringVarP(&auditFrom, "from", "", "", "changed after. format : "+common.DefaultTimeLayout)
+	auditGetALLCmd.PersistentFlags().StringVarP(&auditTo, "to", "", "", "changed before. format : "+common.DefaultTimeLayout)
+	auditGetALLCmd.PersistentFlags().IntVarP(&auditLimit, "limit", "l", 0, "page size")
+	auditGetALLCmd.PersistentFlags().StringVarP(&auditCursor, "cursor", "c", "", "next cursor of previous page")
+	auditGetALLCmd.PersistentFlags().BoolVarP(&auditChanges, "changes", "", false, "show before and after values")
+
+}
+
+var auditGetALLCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "audit : get-all",
+	Long:  `audit : get-all`,
+	Run: func(cmd *cobra.Command, args []string) {
+		auditGetALL(cmd, args)
+	},
+}
+
+func auditGetALL(cmd *cobra.Command, args []string) {
+
+	query := url.Values{}
+	setQueryValue(query, "entity", auditEntity)
+	setQueryValue(query, "id", auditEntityID)
+	setQueryValue(query, "actor", auditActor)
+	setQueryValue(query, "from", auditFrom)
+	setQueryValue(query, "to", auditTo)
+	setQueryValue(query, "cursor", auditCursor)
+
+	if auditLimit != 0 {
+		query.Set("limit", common.IntToStr(auditLimit))
+	}
+
+	mgrService := appContainer.GetMgrService()
+	page, err := mgrService.AuditGetALL(query)
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Time", "Actor", "Action", "Entity", "ID", "Revision", "Changes"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(prepareAuditTable(page.Records))
+	table.Render()
+
+	if page.NextCursor != "" {
+		fmt.Printf("next page : --cursor \"%s\"\n", page.NextCursor)
+	}
+
+}
+
+func prepareAuditTable(records []types.AuditRecord) [][]string {
+
+	var data [][]string
+
+	for _, record := range records {
+		data = append(data, []string{
+			record.Time,
+			record.Actor,
+			record.Action,
+			record.Entity,
+			record.EntityID,
+			fmt.Sprintf("%d", record.Revision),
+			prepareAuditChanges(record),
+		})
+	}
+
+	return data
+
+}
+
+func prepareAuditChanges(record types.AuditRecord) string {
+
+	var changes []string
+
+	for _, change := range record.Changes {
+
+		if !auditChanges {
+			changes = append(changes, change.Field)
+			continue
+		}
+
+		changes = append(changes, fmt.Sprintf("%s : %s => %s", change.Field, auditValue(change.Before), auditValue(change.After)))
+
+	}
+
+	if auditChanges && len(record.Before) > 0 {
+		changes = append(changes, string(record.Before))
+	}
+
+	if auditChanges && len(record.After) > 0 {
+		changes = append(changes, string(record.After))
+	}
+
+	return strings.Join(changes, "\n")
+
+}
+
+func auditValue(value []byte) string {
+	if len(value) == 0 {
+		return "-"
+	}
+	return string(value)
+}
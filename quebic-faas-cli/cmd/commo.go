@@ -15,6 +15,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"quebic-faas/quebic-faas-cli/common"
 	"quebic-faas/types"
@@ -32,6 +33,16 @@ func prepareError(cmd *cobra.Command, err error) {
 	os.Exit(1)
 }
 
+//checkUpdateRevision update is checked against the revision which is read with inspect
+// update without a revision overwrites changes made in between, so it is only sent with --force
+func checkUpdateRevision(cmd *cobra.Command, revision int64, force bool) {
+
+	if revision == 0 && !force {
+		prepareError(cmd, fmt.Errorf("revision is not set. take it from inspect, or use --force to update without the revision check"))
+	}
+
+}
+
 //PrepareErrorResponse prepare errorResponse
 func prepareErrorResponse(cmd *cobra.Command, errorResponse *types.ErrorResponse) {
 
@@ -20,6 +20,7 @@ import (
 	"os"
 	quebic_common "quebic-faas/common"
 	"quebic-faas/quebic-faas-cli/common"
+	"quebic-faas/quebic-faas-cli/service"
 	"quebic-faas/types"
 	"reflect"
 
@@ -36,6 +37,8 @@ var functionVersion string
 var functionReplicas int
 var functionTestPayload string
 var functionStart bool
+var functionDetach bool
+var functionForce bool
 
 func init() {
 	setupFunctionCmds()
@@ -59,10 +62,14 @@ func setupFunctionCmds() {
 	functionCmd.AddCommand(functionDeleteCmd)
 	functionCmd.AddCommand(functionGetALLCmd)
 	functionCmd.AddCommand(functionInspectCmd)
+	functionCmd.AddCommand(functionBuildCmd)
 
 	//function-logs
 	functionCmd.AddCommand(functionLogsCmd)
 
+	//function-artifacts
+	functionCmd.AddCommand(functionArtifactsCmd)
+
 }
 
 func setupFunctionFlags() {
@@ -70,10 +77,15 @@ func setupFunctionFlags() {
 	//function-create
 	functionCreateCmd.PersistentFlags().StringVarP(&functionInputFile, "file", "f", "function.yml", "function spec file")
 	functionCreateCmd.PersistentFlags().BoolVarP(&functionStart, "start", "s", true, "if true function-container will start. otherwise not")
+	functionCreateCmd.PersistentFlags().BoolVarP(&functionDetach, "detach", "d", false, "if true returns once the function is saved. job is not followed")
+	functionCreateCmd.PersistentFlags().IntVarP(&jobTimeout, "timeout", "t", 0, "minutes to follow the job. 0 follows until the job is finished")
 
 	//function-update
 	functionUpdateCmd.PersistentFlags().StringVarP(&functionInputFile, "file", "f", "function.yml", "function spec file")
 	functionUpdateCmd.PersistentFlags().BoolVarP(&functionStart, "start", "s", true, "if true function-container will start. otherwise not")
+	functionUpdateCmd.PersistentFlags().BoolVarP(&functionDetach, "detach", "d", false, "if true returns once the function is saved. job is not followed")
+	functionUpdateCmd.PersistentFlags().IntVarP(&jobTimeout, "timeout", "t", 0, "minutes to follow the job. 0 follows until the job is finished")
+	functionUpdateCmd.PersistentFlags().BoolVar(&functionForce, "force", false, "if true function is updated even when the spec has no revision")
 
 	//function-deploy
 	functionDeployCmd.PersistentFlags().StringVarP(&functionName, "name", "n", "", "function name")
@@ -93,6 +105,9 @@ func setupFunctionFlags() {
 	//function-inspect
 	functionInspectCmd.PersistentFlags().StringVarP(&functionName, "name", "n", "", "function name")
 
+	//function-build
+	functionBuildCmd.PersistentFlags().StringVarP(&functionName, "name", "n", "", "function name")
+
 }
 
 var functionCreateCmd = &cobra.Command{
@@ -167,6 +182,15 @@ var functionInspectCmd = &cobra.Command{
 	},
 }
 
+var functionBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "function : last image build status and logs",
+	Long:  `function : last image build status and logs`,
+	Run: func(cmd *cobra.Command, args []string) {
+		functionBuild(cmd, args)
+	},
+}
+
 func functionSave(cmd *cobra.Command, args []string, isAdd bool) {
 
 	functionDTO := &types.FunctionDTO{}
@@ -175,20 +199,39 @@ func functionSave(cmd *cobra.Command, args []string, isAdd bool) {
 		prepareError(cmd, err)
 	}
 
+	if !isAdd {
+		checkUpdateRevision(cmd, functionDTO.Function.Revision, functionForce)
+	}
+
 	mgrService := appContainer.GetMgrService()
 
+	//job progress is streamed into the cli unless it is detached
+	var jobHandlers *service.JobHandlers
+	if !functionDetach {
+		handlers := newJobHandlers()
+		jobHandlers = &handlers
+	}
+
+	var job *types.Job
 	var errResponse *types.ErrorResponse
 	if isAdd {
-		errResponse = mgrService.FunctionCreate(functionDTO)
+		job, errResponse = mgrService.FunctionCreate(functionDTO, jobHandlers)
 	} else {
-		errResponse = mgrService.FunctionUpdate(functionDTO)
+		job, errResponse = mgrService.FunctionUpdate(functionDTO, jobHandlers)
 	}
 
 	if errResponse != nil {
 		prepareErrorResponse(cmd, errResponse)
 	}
 
-	color.Green("%s:%s function is saved", functionDTO.Function.GetID(), functionDTO.Function.Version)
+	if functionDetach {
+		color.Green("%s:%s function is saved. job : %s", functionDTO.Function.GetID(), functionDTO.Function.Version, job.ID)
+		return
+	}
+
+	checkJob(cmd, job)
+
+	color.Green("%s:%s function is ready. job : %s", functionDTO.Function.GetID(), functionDTO.Function.Version, job.ID)
 
 }
 
@@ -310,6 +353,31 @@ func functionGetByName(cmd *cobra.Command, args []string) {
 
 }
 
+func functionBuild(cmd *cobra.Command, args []string) {
+
+	mgrService := appContainer.GetMgrService()
+	function, err := mgrService.FunctionsGetByName(functionName)
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	build := function.Build
+
+	for _, line := range build.Logs {
+		fmt.Println(line)
+	}
+
+	fmt.Printf("\nbuild : %s\nstarted : %s\nfinished : %s\n", build.ID, build.StartedAt, build.FinishedAt)
+
+	if build.Status == quebic_common.FunctionBuildStatusFailed {
+		color.Red("status : %s\nerror : %s", build.Status, build.Error)
+		return
+	}
+
+	color.Green("status : %s\nimage : %s", build.Status, build.Image)
+
+}
+
 func prepareFunctionsTable(data []types.Function) [][]string {
 
 	var rows [][]string
@@ -0,0 +1,155 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"quebic-faas/types"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var functionArtifactOutput string
+
+func init() {
+	setupFunctionArtifactCmds()
+	setupFunctionArtifactFlags()
+}
+
+var functionArtifactsCmd = &cobra.Command{
+	Use:   "artifacts",
+	Short: "function : artifacts",
+	Long:  `function : artifacts which are uploaded for each version`,
+}
+
+func setupFunctionArtifactCmds() {
+	functionArtifactsCmd.AddCommand(functionArtifactGetALLCmd)
+	functionArtifactsCmd.AddCommand(functionArtifactDownloadCmd)
+}
+
+func setupFunctionArtifactFlags() {
+
+	//function-artifacts-ls
+	functionArtifactGetALLCmd.PersistentFlags().StringVarP(&functionName, "name", "n", "", "function name")
+
+	//function-artifacts-download
+	functionArtifactDownloadCmd.PersistentFlags().StringVarP(&functionName, "name", "n", "", "function name")
+	functionArtifactDownloadCmd.PersistentFlags().StringVarP(&functionVersion, "version", "v", "", "function version")
+	functionArtifactDownloadCmd.PersistentFlags().StringVarP(&functionArtifactOutput, "output", "o", "", "output file. default <name>-<version>")
+
+}
+
+var functionArtifactGetALLCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "function : artifacts ls",
+	Long:  `function : artifacts ls`,
+	Run: func(cmd *cobra.Command, args []string) {
+		functionArtifactGetALL(cmd, args)
+	},
+}
+
+var functionArtifactDownloadCmd = &cobra.Command{
+	Use:   "download",
+	Short: "function : artifacts download",
+	Long:  `function : download the exact artifact of the version`,
+	Run: func(cmd *cobra.Command, args []string) {
+		functionArtifactDownload(cmd, args)
+	},
+}
+
+func functionArtifactGetALL(cmd *cobra.Command, args []string) {
+
+	if functionName == "" {
+		prepareErrorResponse(cmd, &types.ErrorResponse{Cause: "function name is empty"})
+	}
+
+	mgrService := appContainer.GetMgrService()
+	artifacts, err := mgrService.FunctionArtifactGetALL(functionName)
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{
+		"Version",
+		"Digest",
+		"Size",
+		"Image",
+		"Actor",
+		"Created",
+	})
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(prepareFunctionArtifactsTable(artifacts))
+	table.Render()
+
+}
+
+func functionArtifactDownload(cmd *cobra.Command, args []string) {
+
+	if functionName == "" {
+		prepareErrorResponse(cmd, &types.ErrorResponse{Cause: "function name is empty"})
+	}
+
+	if functionVersion == "" {
+		prepareErrorResponse(cmd, &types.ErrorResponse{Cause: "function version is empty"})
+	}
+
+	output := functionArtifactOutput
+	if output == "" {
+		output = functionName + "-" + functionVersion
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		prepareError(cmd, err)
+	}
+
+	mgrService := appContainer.GetMgrService()
+	errResponse := mgrService.FunctionArtifactDownload(functionName, functionVersion, file)
+
+	closeErr := file.Close()
+
+	if errResponse != nil {
+		os.Remove(output)
+		prepareErrorResponse(cmd, errResponse)
+	}
+
+	if closeErr != nil {
+		prepareError(cmd, closeErr)
+	}
+
+	color.Green("%s %s artifact saved into %s", functionName, functionVersion, output)
+
+}
+
+func prepareFunctionArtifactsTable(data []types.FunctionArtifact) [][]string {
+
+	var rows [][]string
+
+	for _, val := range data {
+
+		size := strconv.FormatInt(val.Size, 10)
+
+		rows = append(rows, []string{val.Version, val.Digest, size, val.Image, val.Actor, val.CreatedAt})
+
+	}
+
+	return rows
+
+}
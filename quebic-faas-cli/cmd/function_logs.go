@@ -15,6 +15,9 @@
 package cmd
 
 import (
+	"fmt"
+	"quebic-faas/types"
+
 	"github.com/spf13/cobra"
 )
 
@@ -58,29 +61,31 @@ var functionLogsCmd = &cobra.Command{
 
 func functionLogs(cmd *cobra.Command, args []string) {
 
-	/*mgrService := appContainer.GetMgrService()
-	function, err := mgrService.FunctionsGetByName(functionName)
-	if err != nil {
-		prepareErrorResponse(cmd, err)
+	if functionName == "" {
+		prepareErrorResponse(cmd, &types.ErrorResponse{Cause: "function name is empty"})
 	}
 
-	options := dockerTypes.ContainerLogsOptions{
-		Details:    functionLogDetails,
-		Follow:     functionLogFollow,
-		ShowStderr: functionLogShowStderr,
-		ShowStdout: functionLogShowStdout,
-		Since:      functionLogSince,
-		Tail:       functionLogTail,
-		Timestamps: functionLogTimestamps,
-		Until:      functionLogUntil,
+	logDTO := &types.FunctionContainerLogDTO{
+		Function: types.Function{Name: functionName},
+		Options: types.FunctionContainerLogOptions{
+			Details:    functionLogDetails,
+			Follow:     functionLogFollow,
+			ShowStderr: functionLogShowStderr,
+			ShowStdout: functionLogShowStdout,
+			Since:      functionLogSince,
+			Tail:       functionLogTail,
+			Timestamps: functionLogTimestamps,
+			Until:      functionLogUntil,
+		},
 	}
 
-	functionService := functionutil.GetServiceID(function.Name)
-	out, errO := common.DockerServiceLogs(functionService, options)
-	if errO != nil {
-		prepareError(cmd, errO)
+	//logs of all replicas are interleaved by manager
+	mgrService := appContainer.GetMgrService()
+	err := mgrService.FunctionLogs(logDTO, func(line string) {
+		fmt.Println(line)
+	})
+	if err != nil {
+		prepareErrorResponse(cmd, err)
 	}
 
-	io.Copy(os.Stdout, out)*/
-
 }
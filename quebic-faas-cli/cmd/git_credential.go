@@ -0,0 +1,228 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"quebic-faas/types"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var gitCredentialHost string
+var gitCredentialUsername string
+var gitCredentialPassword string
+var gitCredentialPasswordStdin bool
+var gitCredentialKeyFile string
+var gitCredentialRevision int64
+var gitCredentialForce bool
+
+func init() {
+	setupGitCredentialCmds()
+	setupGitCredentialFlags()
+}
+
+var gitCredentialCmd = &cobra.Command{
+	Use:   "git-credential",
+	Short: "Git credentials commonds",
+	Long:  `Git credentials commonds. private repositories of the functions sources are fetched with them`,
+}
+
+func setupGitCredentialCmds() {
+
+	gitCredentialCmd.AddCommand(gitCredentialAddCmd)
+	gitCredentialCmd.AddCommand(gitCredentialUpdateCmd)
+	gitCredentialCmd.AddCommand(gitCredentialGetALLCmd)
+	gitCredentialCmd.AddCommand(gitCredentialInspectCmd)
+	gitCredentialCmd.AddCommand(gitCredentialDeleteCmd)
+
+}
+
+func setupGitCredentialFlags() {
+
+	//git-credential-add, git-credential-update
+	for _, c := range []*cobra.Command{gitCredentialAddCmd, gitCredentialUpdateCmd} {
+		c.PersistentFlags().StringVarP(&gitCredentialHost, "host", "", "", "git server host. eg: github.com, git.example.com:8443")
+		c.PersistentFlags().StringVarP(&gitCredentialUsername, "username", "u", "", "username")
+		c.PersistentFlags().StringVarP(&gitCredentialPassword, "password", "p", "", "password or access token. used with https")
+		c.PersistentFlags().BoolVar(&gitCredentialPasswordStdin, "password_stdin", false, "read password from stdin")
+		c.PersistentFlags().StringVarP(&gitCredentialKeyFile, "ssh_key", "k", "", "ssh private key file. used with ssh")
+	}
+
+	//git-credential-update
+	gitCredentialUpdateCmd.PersistentFlags().Int64Var(&gitCredentialRevision, "revision", 0, "revision of the credential. eg: taken from inspect")
+	gitCredentialUpdateCmd.PersistentFlags().BoolVar(&gitCredentialForce, "force", false, "if true credential is updated without a revision")
+
+	//git-credential-inspect
+	gitCredentialInspectCmd.PersistentFlags().StringVarP(&gitCredentialHost, "host", "", "", "git server host")
+
+	//git-credential-delete
+	gitCredentialDeleteCmd.PersistentFlags().StringVarP(&gitCredentialHost, "host", "", "", "git server host")
+
+}
+
+var gitCredentialAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "git-credential : add credentials",
+	Long:  `git-credential : add credentials of a git server`,
+	Run: func(cmd *cobra.Command, args []string) {
+		gitCredentialSave(cmd, args, true)
+	},
+}
+
+var gitCredentialUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "git-credential : update credentials",
+	Long:  `git-credential : update credentials. empty password or ssh key keeps the saved one`,
+	Run: func(cmd *cobra.Command, args []string) {
+		gitCredentialSave(cmd, args, false)
+	},
+}
+
+var gitCredentialGetALLCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "git-credential : get-all",
+	Long:  `git-credential : get-all`,
+	Run: func(cmd *cobra.Command, args []string) {
+		gitCredentialGetALL(cmd, args)
+	},
+}
+
+var gitCredentialInspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "git-credential : inspect credential details",
+	Long:  `git-credential : inspect credential details`,
+	Run: func(cmd *cobra.Command, args []string) {
+		gitCredentialGetByHost(cmd, args)
+	},
+}
+
+var gitCredentialDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "git-credential : delete credentials",
+	Long:  `git-credential : delete credentials`,
+	Run: func(cmd *cobra.Command, args []string) {
+		gitCredentialDelete(cmd, args)
+	},
+}
+
+func gitCredentialSave(cmd *cobra.Command, args []string, isAdd bool) {
+
+	if gitCredentialPasswordStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			prepareError(cmd, fmt.Errorf("unable to read password from stdin %v", err))
+		}
+		gitCredentialPassword = strings.TrimRight(line, "\r\n")
+	}
+
+	privateKey := ""
+	if gitCredentialKeyFile != "" {
+		key, err := ioutil.ReadFile(gitCredentialKeyFile)
+		if err != nil {
+			prepareError(cmd, err)
+		}
+		privateKey = string(key)
+	}
+
+	credential := &types.GitCredential{
+		Host:       gitCredentialHost,
+		Username:   gitCredentialUsername,
+		Password:   gitCredentialPassword,
+		PrivateKey: privateKey,
+	}
+
+	if !isAdd {
+		credential.Revision = gitCredentialRevision
+		checkUpdateRevision(cmd, credential.Revision, gitCredentialForce)
+	}
+
+	mgrService := appContainer.GetMgrService()
+
+	var errResponse *types.ErrorResponse
+	if isAdd {
+		errResponse = mgrService.GitCredentialCreate(credential)
+	} else {
+		errResponse = mgrService.GitCredentialUpdate(credential)
+	}
+
+	if errResponse != nil {
+		prepareErrorResponse(cmd, errResponse)
+	}
+
+	color.Green("%s git credential is saved", credential.Host)
+
+}
+
+func gitCredentialGetALL(cmd *cobra.Command, args []string) {
+
+	mgrService := appContainer.GetMgrService()
+	credentials, err := mgrService.GitCredentialGetALL()
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Host", "Username", "Modified"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(prepareGitCredentialTable(credentials))
+	table.Render()
+
+}
+
+func gitCredentialGetByHost(cmd *cobra.Command, args []string) {
+
+	mgrService := appContainer.GetMgrService()
+	credential, err := mgrService.GitCredentialGetByHost(gitCredentialHost)
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	ymlStr, _ := yaml.Marshal(credential)
+	fmt.Printf("%s", ymlStr)
+
+}
+
+func gitCredentialDelete(cmd *cobra.Command, args []string) {
+
+	mgrService := appContainer.GetMgrService()
+	err := mgrService.GitCredentialDelete(gitCredentialHost)
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	color.Green("%s git credential is deleted", gitCredentialHost)
+
+}
+
+func prepareGitCredentialTable(data []types.GitCredential) [][]string {
+
+	var rows [][]string
+
+	for _, val := range data {
+		rows = append(rows, []string{val.Host, val.Username, val.ModifiedAt})
+	}
+
+	return rows
+
+}
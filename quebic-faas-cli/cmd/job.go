@@ -0,0 +1,126 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	quebic_common "quebic-faas/common"
+	"quebic-faas/quebic-faas-cli/service"
+	"quebic-faas/types"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var jobID string
+var jobTimeout int
+
+func init() {
+	setupJobCmds()
+	setupJobFlags()
+}
+
+var jobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "Job commonds",
+	Long:  `Job commonds`,
+}
+
+func setupJobCmds() {
+
+	jobCmd.AddCommand(jobInspectCmd)
+	jobCmd.AddCommand(jobWaitCmd)
+
+}
+
+func setupJobFlags() {
+
+	//job-inspect
+	jobInspectCmd.PersistentFlags().StringVarP(&jobID, "id", "i", "", "job id")
+
+	//job-wait
+	jobWaitCmd.PersistentFlags().StringVarP(&jobID, "id", "i", "", "job id")
+	jobWaitCmd.PersistentFlags().IntVarP(&jobTimeout, "timeout", "t", 0, "minutes to wait for the job. 0 waits until the job is finished")
+
+}
+
+var jobInspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "job : inspect job details",
+	Long:  `job : inspect job details`,
+	Run: func(cmd *cobra.Command, args []string) {
+		jobGetByID(cmd, args)
+	},
+}
+
+var jobWaitCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "job : follow job until it is ready or failed",
+	Long:  `job : follow job until it is ready or failed`,
+	Run: func(cmd *cobra.Command, args []string) {
+		followJob(cmd, jobID)
+	},
+}
+
+func jobGetByID(cmd *cobra.Command, args []string) {
+
+	mgrService := appContainer.GetMgrService()
+	job, err := mgrService.JobGetByID(jobID)
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	ymlStr, _ := yaml.Marshal(job)
+	fmt.Printf("%s", ymlStr)
+
+}
+
+//followJob print states and output of the job until it is finished. exits when job is failed
+func followJob(cmd *cobra.Command, id string) *types.Job {
+
+	mgrService := appContainer.GetMgrService()
+
+	job, errResponse := mgrService.JobWait(id, newJobHandlers())
+	if errResponse != nil {
+		prepareErrorResponse(cmd, errResponse)
+	}
+
+	checkJob(cmd, job)
+
+	return job
+
+}
+
+//newJobHandlers print states and output of the followed job
+func newJobHandlers() service.JobHandlers {
+	return service.JobHandlers{
+		StateHandler: func(state string) {
+			color.Cyan("==> %s", state)
+		},
+		LogHandler: func(line string) {
+			fmt.Println(line)
+		},
+		Timeout: time.Minute * time.Duration(jobTimeout),
+	}
+}
+
+//checkJob exits when job is failed
+func checkJob(cmd *cobra.Command, job *types.Job) {
+	if job.State == quebic_common.JobStateFailed {
+		prepareErrorResponse(cmd, &types.ErrorResponse{Cause: job.Error})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"quebic-faas/common"
+	mgrconfig "quebic-faas/quebic-faas-mgr/config"
 	dep "quebic-faas/quebic-faas-mgr/deployment"
 	"quebic-faas/quebic-faas-mgr/deployment/kube_deployment"
 	"quebic-faas/types"
@@ -36,17 +37,37 @@ const quebicManagerPort = 1028
 
 const dockerSockVolume = "docker-sock-volume"
 
+//quebicManagerSecret keeps the credentials key out of the manager deployment
+const quebicManagerSecret = "quebic-faas-manager"
+const quebicManagerSecretCredentialsKey = "credentialsKey"
+const quebicManagerSecretStorageDataSource = "storageDataSource"
+
 const defaultDockerSockVolumePath = "/var/run/docker.sock"
 const defaultWaitForAvailable = true
+const defaultManagerReplicas = 1
 
 var dockerSockVolumePath string
 var ingressStaticIP string
 
+var builder string
+var registryAddress string
+var registrySecret string
+var buildVolumeClaim string
+var credentialsKey string
+var verifyImageDigest bool
+
+var managerReplicas int
+var storageBackend string
+var storageDataSource string
+var leaderElection bool
+var leaderElectionBackend string
+
 var waitForAvailable bool
 
 func init() {
 	setupManagerCompCmds()
 	setupManagerCompFlags()
+	setupManagerBackupFlags()
 }
 
 var mgrCmd = &cobra.Command{
@@ -61,6 +82,8 @@ func setupManagerCompCmds() {
 	mgrCmd.AddCommand(managerConnectCmd)
 	mgrCmd.AddCommand(managerStatusCmd)
 	mgrCmd.AddCommand(managerLogsCmd)
+	mgrCmd.AddCommand(managerBackupCmd)
+	mgrCmd.AddCommand(managerRestoreCmd)
 
 }
 
@@ -68,6 +91,21 @@ func setupManagerCompFlags() {
 	mgrCmd.PersistentFlags().StringVarP(&dockerSockVolumePath, "docker_sock_path", "d", defaultDockerSockVolumePath, "docker sock path. eg: /var/run/docker.sock")
 	mgrCmd.PersistentFlags().StringVarP(&ingressStaticIP, "static_ip_name", "n", "", "gce static_ip_name.")
 	mgrCmd.PersistentFlags().BoolVarP(&waitForAvailable, "wait_for_available", "w", defaultWaitForAvailable, "manager status wait for available")
+
+	//in-cluster builds. docker socket is not mounted into manager
+	managerStartCmd.PersistentFlags().StringVarP(&builder, "builder", "b", mgrconfig.BuilderDocker, "function image builder. docker or kaniko")
+	managerStartCmd.PersistentFlags().StringVarP(&registryAddress, "registry_address", "", "", "registry which function images are pushed. credentials are added by registry add. required by kaniko builder")
+	managerStartCmd.PersistentFlags().StringVarP(&registrySecret, "registry_secret", "", "", "docker config secret of the registry. used by kaniko builder to push images")
+	managerStartCmd.PersistentFlags().StringVarP(&buildVolumeClaim, "build_volume_claim", "", "", "persistent volume claim which build contexts are handed over. required by kaniko builder")
+	managerStartCmd.PersistentFlags().StringVarP(&credentialsKey, "credentials_key", "", "", "key which seals registry and git credentials. required to add them")
+	managerStartCmd.PersistentFlags().BoolVarP(&verifyImageDigest, "verify_image_digest", "", false, "pin function deployments to the image digest which is recorded when the image is built")
+
+	//replicated managers. replicas share postgres storage and elect a leader
+	managerStartCmd.PersistentFlags().IntVarP(&managerReplicas, "replicas", "", defaultManagerReplicas, "manager replicas. more than 1 requires leader_election and postgres storage_backend")
+	managerStartCmd.PersistentFlags().StringVarP(&storageBackend, "storage_backend", "", "", "storage of the manager state. bolt (default), sqlite or postgres")
+	managerStartCmd.PersistentFlags().StringVarP(&storageDataSource, "storage_datasource", "", "", "postgres connection string. kept in the manager secret")
+	managerStartCmd.PersistentFlags().BoolVarP(&leaderElection, "leader_election", "", false, "leader election among manager replicas")
+	managerStartCmd.PersistentFlags().StringVarP(&leaderElectionBackend, "leader_election_backend", "", "", "leader election backend. kubernetes (default) or storage")
 }
 
 var managerStartCmd = &cobra.Command{
@@ -196,11 +234,70 @@ func managerDeploy(cmd *cobra.Command, deployment dep.Deployment) error {
 
 	color.Green("%s : starting", quebicManagerComponentID)
 
+	err := checkManagerReplicas()
+	if err != nil {
+		return err
+	}
+
 	portConfig := getManagerPortConfigs()
 
 	envkeys := make(map[string]string)
 	envkeys[common.EnvKey_ingressConfig_staticIP] = ingressStaticIP
 
+	if registryAddress != "" {
+		envkeys[common.EnvKey_dockerConfig_registryAddress] = registryAddress
+	}
+
+	if storageBackend != "" {
+		envkeys[common.EnvKey_storage_backend] = storageBackend
+	}
+
+	if leaderElection {
+		envkeys[common.EnvKey_leaderElection_enabled] = "true"
+	}
+
+	if leaderElectionBackend != "" {
+		envkeys[common.EnvKey_leaderElection_backend] = leaderElectionBackend
+	}
+
+	//credentials key and storage connection string are kept in the manager secret
+	secretData := make(map[string][]byte)
+	secretEnvkeys := make(map[string]dep.SecretKeyRef)
+
+	if credentialsKey != "" {
+		secretData[quebicManagerSecretCredentialsKey] = []byte(credentialsKey)
+		secretEnvkeys[common.EnvKey_dockerConfig_credentialsKey] = dep.SecretKeyRef{
+			Secret: quebicManagerSecret,
+			Key:    quebicManagerSecretCredentialsKey,
+		}
+	}
+
+	if storageDataSource != "" {
+		secretData[quebicManagerSecretStorageDataSource] = []byte(storageDataSource)
+		secretEnvkeys[common.EnvKey_storage_dataSource] = dep.SecretKeyRef{
+			Secret: quebicManagerSecret,
+			Key:    quebicManagerSecretStorageDataSource,
+		}
+	}
+
+	if len(secretData) > 0 {
+
+		kubeDeployment, ok := deployment.(kube_deployment.Deployment)
+		if !ok {
+			return fmt.Errorf("credentials_key and storage_datasource require %s deployment", mgrconfig.Deployment_Kubernetes)
+		}
+
+		err := kubeDeployment.SecretCreateOrUpdate(quebicManagerSecret, secretData)
+		if err != nil {
+			return err
+		}
+
+	}
+
+	if verifyImageDigest {
+		envkeys[common.EnvKey_dockerConfig_verifyImageDigest] = "true"
+	}
+
 	volumes := []dep.Volume{
 		{
 			HostPath:      dockerSockVolumePath,
@@ -209,16 +306,37 @@ func managerDeploy(cmd *cobra.Command, deployment dep.Deployment) error {
 		},
 	}
 
+	if builder == mgrconfig.BuilderKaniko {
+
+		if registryAddress == "" || buildVolumeClaim == "" {
+			return fmt.Errorf("%s builder requires registry_address and build_volume_claim", mgrconfig.BuilderKaniko)
+		}
+
+		envkeys[common.EnvKey_dockerConfig_builder] = builder
+		envkeys[common.EnvKey_dockerConfig_registrySecret] = registrySecret
+		envkeys[common.EnvKey_dockerConfig_buildVolumeClaim] = buildVolumeClaim
+
+		//build contexts are handed over to build jobs through the volume
+		volumes = []dep.Volume{
+			{
+				ContainerPath: mgrconfig.DefaultBuildVolumePath,
+				ClaimName:     buildVolumeClaim,
+			},
+		}
+
+	}
+
 	deploymentSpec := dep.Spec{
-		Name:        quebicManagerComponentID,
-		Dockerimage: quebicManagerDockerImage,
-		PortConfigs: portConfig,
-		Envkeys:     envkeys,
-		Replicas:    1,
-		Volumes:     volumes,
+		Name:          quebicManagerComponentID,
+		Dockerimage:   quebicManagerDockerImage,
+		PortConfigs:   portConfig,
+		Envkeys:       envkeys,
+		SecretEnvkeys: secretEnvkeys,
+		Replicas:      dep.Replicas(managerReplicas),
+		Volumes:       volumes,
 	}
 
-	_, err := deployment.CreateOrUpdate(deploymentSpec)
+	_, err = deployment.CreateOrUpdate(deploymentSpec)
 	if err != nil {
 		prepareError(cmd, err)
 	}
@@ -229,6 +347,36 @@ func managerDeploy(cmd *cobra.Command, deployment dep.Deployment) error {
 
 }
 
+//checkManagerReplicas replicas keep their state apart unless they share postgres storage,
+//and every replica would run gc and reconciliation without leader election
+func checkManagerReplicas() error {
+
+	if managerReplicas < 1 {
+		return fmt.Errorf("replicas should be at least 1")
+	}
+
+	if storageBackend != "" && storageBackend != mgrconfig.StorageBolt && storageBackend != mgrconfig.StorageSQLite && storageBackend != mgrconfig.StoragePostgres {
+		return fmt.Errorf("unsupported storage_backend %s", storageBackend)
+	}
+
+	sharedStorage := mgrconfig.StorageConfig{Backend: storageBackend}.Shared()
+
+	if sharedStorage && storageDataSource == "" {
+		return fmt.Errorf("%s storage_backend requires storage_datasource", mgrconfig.StoragePostgres)
+	}
+
+	if leaderElection && !sharedStorage {
+		return fmt.Errorf("leader_election requires %s storage_backend", mgrconfig.StoragePostgres)
+	}
+
+	if managerReplicas > 1 && (!leaderElection || !sharedStorage) {
+		return fmt.Errorf("%d replicas require leader_election and %s storage_backend", managerReplicas, mgrconfig.StoragePostgres)
+	}
+
+	return nil
+
+}
+
 func getManagerPortConfigs() []dep.PortConfig {
 
 	publishPort := dep.Port(quebicManagerPort)
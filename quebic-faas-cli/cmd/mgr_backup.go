@@ -0,0 +1,158 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"quebic-faas/types"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const defaultBackupFile = "quebic-faas-mgr-backup.json"
+
+var backupFile string
+var backupEntities []string
+var restoreDryRun bool
+var restoreRedeploy bool
+
+func setupManagerBackupFlags() {
+
+	managerBackupCmd.PersistentFlags().StringVarP(&backupFile, "file", "f", defaultBackupFile, "backup file")
+	managerBackupCmd.PersistentFlags().StringSliceVarP(&backupEntities, "entities", "e", nil, "entities to backup. users, events, functions, routes, grpc-services, components, request-trackers. default all")
+
+	managerRestoreCmd.PersistentFlags().StringVarP(&backupFile, "file", "f", defaultBackupFile, "backup file")
+	managerRestoreCmd.PersistentFlags().StringSliceVarP(&backupEntities, "entities", "e", nil, "entities to restore. default all")
+	managerRestoreCmd.PersistentFlags().BoolVarP(&restoreDryRun, "dry-run", "", false, "only show the differences")
+	managerRestoreCmd.PersistentFlags().BoolVarP(&restoreRedeploy, "redeploy", "", false, "redeploy all functions after restore")
+
+}
+
+var managerBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "manager : backup",
+	Long:  `manager : backup state into a file`,
+	Run: func(cmd *cobra.Command, args []string) {
+		managerBackup(cmd, args)
+	},
+}
+
+var managerRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "manager : restore",
+	Long:  `manager : restore state from a backup file. restored entities are replaced`,
+	Run: func(cmd *cobra.Command, args []string) {
+		managerRestore(cmd, args)
+	},
+}
+
+func managerBackup(cmd *cobra.Command, args []string) {
+
+	file, err := os.Create(backupFile)
+	if err != nil {
+		prepareError(cmd, err)
+	}
+
+	mgrService := appContainer.GetMgrService()
+	errResponse := mgrService.ManagerBackup(backupEntities, file)
+
+	closeErr := file.Close()
+
+	if errResponse != nil {
+		os.Remove(backupFile)
+		prepareErrorResponse(cmd, errResponse)
+	}
+
+	if closeErr != nil {
+		prepareError(cmd, closeErr)
+	}
+
+	color.Green("manager backup saved into %s", backupFile)
+
+}
+
+func managerRestore(cmd *cobra.Command, args []string) {
+
+	file, err := os.Open(backupFile)
+	if err != nil {
+		prepareError(cmd, err)
+	}
+	defer file.Close()
+
+	mgrService := appContainer.GetMgrService()
+	report, errResponse := mgrService.ManagerRestore(backupEntities, file, restoreDryRun, restoreRedeploy)
+	if errResponse != nil {
+		prepareErrorResponse(cmd, errResponse)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Entity", "Added", "Updated", "Removed", "Unchanged"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(prepareRestoreReportTable(report.Entities))
+	table.Render()
+
+	if report.DryRun {
+
+		for _, diff := range report.Entities {
+			for _, key := range diff.Added {
+				color.Green("+ %s %s", diff.Entity, key)
+			}
+			for _, key := range diff.Updated {
+				color.Yellow("~ %s %s", diff.Entity, key)
+			}
+			for _, key := range diff.Removed {
+				color.Red("- %s %s", diff.Entity, key)
+			}
+		}
+
+		color.Yellow("dry-run : nothing restored")
+		return
+
+	}
+
+	for _, function := range report.Redeployed {
+		color.Green("%s redeployed", function)
+	}
+
+	for function, cause := range report.RedeployFailed {
+		color.Red("%s redeploy failed : %s", function, cause)
+	}
+
+	color.Green("manager restored from %s", backupFile)
+
+}
+
+func prepareRestoreReportTable(data []types.RestoreEntityDiff) [][]string {
+
+	var rows [][]string
+
+	for _, val := range data {
+
+		added := fmt.Sprint(len(val.Added))
+		updated := fmt.Sprint(len(val.Updated))
+		removed := fmt.Sprint(len(val.Removed))
+		unchanged := fmt.Sprint(val.Unchanged)
+
+		rows = append(rows, []string{val.Entity, added, updated, removed, unchanged})
+
+	}
+
+	return rows
+
+}
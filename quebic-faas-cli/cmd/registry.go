@@ -0,0 +1,218 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"quebic-faas/types"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var registryServer string
+var registryUsername string
+var registryPassword string
+var registryPasswordStdin bool
+var registryEmail string
+var registryRevision int64
+var registryForce bool
+
+func init() {
+	setupRegistryCmds()
+	setupRegistryFlags()
+}
+
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Registry credentials commonds",
+	Long:  `Registry credentials commonds`,
+}
+
+func setupRegistryCmds() {
+
+	registryCmd.AddCommand(registryAddCmd)
+	registryCmd.AddCommand(registryUpdateCmd)
+	registryCmd.AddCommand(registryGetALLCmd)
+	registryCmd.AddCommand(registryInspectCmd)
+	registryCmd.AddCommand(registryDeleteCmd)
+
+}
+
+func setupRegistryFlags() {
+
+	//registry-add, registry-update
+	for _, c := range []*cobra.Command{registryAddCmd, registryUpdateCmd} {
+		c.PersistentFlags().StringVarP(&registryServer, "server", "s", "", "registry host. eg: registry.example.com:5000, docker.io")
+		c.PersistentFlags().StringVarP(&registryUsername, "username", "u", "", "username")
+		c.PersistentFlags().StringVarP(&registryPassword, "password", "p", "", "password")
+		c.PersistentFlags().BoolVar(&registryPasswordStdin, "password_stdin", false, "read password from stdin")
+		c.PersistentFlags().StringVarP(&registryEmail, "email", "e", "", "email")
+	}
+
+	//registry-update
+	registryUpdateCmd.PersistentFlags().Int64Var(&registryRevision, "revision", 0, "revision of the registry. eg: taken from inspect")
+	registryUpdateCmd.PersistentFlags().BoolVar(&registryForce, "force", false, "if true registry is updated without a revision")
+
+	//registry-inspect
+	registryInspectCmd.PersistentFlags().StringVarP(&registryServer, "server", "s", "", "registry host")
+
+	//registry-delete
+	registryDeleteCmd.PersistentFlags().StringVarP(&registryServer, "server", "s", "", "registry host")
+
+}
+
+var registryAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "registry : add credentials",
+	Long:  `registry : add credentials. functions images of the registry are pulled with them`,
+	Run: func(cmd *cobra.Command, args []string) {
+		registrySave(cmd, args, true)
+	},
+}
+
+var registryUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "registry : update credentials",
+	Long:  `registry : update credentials. empty password keeps the saved password`,
+	Run: func(cmd *cobra.Command, args []string) {
+		registrySave(cmd, args, false)
+	},
+}
+
+var registryGetALLCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "registry : get-all",
+	Long:  `registry : get-all`,
+	Run: func(cmd *cobra.Command, args []string) {
+		registryGetALL(cmd, args)
+	},
+}
+
+var registryInspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "registry : inspect registry details",
+	Long:  `registry : inspect registry details`,
+	Run: func(cmd *cobra.Command, args []string) {
+		registryGetByServer(cmd, args)
+	},
+}
+
+var registryDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "registry : delete credentials",
+	Long:  `registry : delete credentials`,
+	Run: func(cmd *cobra.Command, args []string) {
+		registryDelete(cmd, args)
+	},
+}
+
+func registrySave(cmd *cobra.Command, args []string, isAdd bool) {
+
+	if registryPasswordStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			prepareError(cmd, fmt.Errorf("unable to read password from stdin %v", err))
+		}
+		registryPassword = strings.TrimRight(line, "\r\n")
+	}
+
+	registry := &types.Registry{
+		Server:   registryServer,
+		Username: registryUsername,
+		Password: registryPassword,
+		Email:    registryEmail,
+	}
+
+	if !isAdd {
+		registry.Revision = registryRevision
+		checkUpdateRevision(cmd, registry.Revision, registryForce)
+	}
+
+	mgrService := appContainer.GetMgrService()
+
+	var errResponse *types.ErrorResponse
+	if isAdd {
+		errResponse = mgrService.RegistryCreate(registry)
+	} else {
+		errResponse = mgrService.RegistryUpdate(registry)
+	}
+
+	if errResponse != nil {
+		prepareErrorResponse(cmd, errResponse)
+	}
+
+	color.Green("%s registry is saved. pull secret : %s", registry.Server, registry.PullSecret)
+
+}
+
+func registryGetALL(cmd *cobra.Command, args []string) {
+
+	mgrService := appContainer.GetMgrService()
+	registries, err := mgrService.RegistryGetALL()
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Server", "Username", "PullSecret", "Modified"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(prepareRegistryTable(registries))
+	table.Render()
+
+}
+
+func registryGetByServer(cmd *cobra.Command, args []string) {
+
+	mgrService := appContainer.GetMgrService()
+	registry, err := mgrService.RegistryGetByServer(registryServer)
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	ymlStr, _ := yaml.Marshal(registry)
+	fmt.Printf("%s", ymlStr)
+
+}
+
+func registryDelete(cmd *cobra.Command, args []string) {
+
+	mgrService := appContainer.GetMgrService()
+	err := mgrService.RegistryDelete(registryServer)
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	color.Green("%s registry is deleted", registryServer)
+
+}
+
+func prepareRegistryTable(data []types.Registry) [][]string {
+
+	var rows [][]string
+
+	for _, val := range data {
+		rows = append(rows, []string{val.Server, val.Username, val.PullSecret, val.ModifiedAt})
+	}
+
+	return rows
+
+}
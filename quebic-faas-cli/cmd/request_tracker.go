@@ -30,9 +30,12 @@ package cmd
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"quebic-faas/common"
 	"quebic-faas/types"
 
+	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	yaml "gopkg.in/yaml.v2"
@@ -40,6 +43,21 @@ import (
 
 var requestID string
 
+var requestTrackerFrom string
+var requestTrackerTo string
+var requestTrackerSource string
+var requestTrackerStatus int
+var requestTrackerState string
+var requestTrackerLimit int
+var requestTrackerCursor string
+
+var requestTrackerLogType string
+var requestTrackerQuery string
+var requestTrackerRegex string
+
+var requestTrackerFollow bool
+var requestTrackerFunction string
+
 func init() {
 	setupRequestTrackerCmds()
 	setupRequestTrackerFlags()
@@ -57,6 +75,7 @@ func setupRequestTrackerCmds() {
 	requestTrackerCmd.AddCommand(requestTrackerGetALLCmd)
 	requestTrackerCmd.AddCommand(requestTrackerInspectCmd)
 	requestTrackerCmd.AddCommand(requestTrackerLogsCmd)
+	requestTrackerCmd.AddCommand(requestTrackerSearchCmd)
 
 }
 
@@ -64,6 +83,24 @@ func setupRequestTrackerFlags() {
 
 	requestTrackerInspectCmd.PersistentFlags().StringVarP(&requestID, "request-id", "i", "", "request id")
 	requestTrackerLogsCmd.PersistentFlags().StringVarP(&requestID, "request-id", "i", "", "request id")
+	requestTrackerLogsCmd.PersistentFlags().StringVarP(&requestTrackerFunction, "function", "n", "", "follow all requests of the function")
+	requestTrackerLogsCmd.PersistentFlags().BoolVarP(&requestTrackerFollow, "follow", "f", false, "follow new logs")
+
+	requestTrackerGetALLCmd.PersistentFlags().StringVarP(&requestTrackerFrom, "from", "", "", "created after. format : "+common.DefaultTimeLayout)
+	requestTrackerGetALLCmd.PersistentFlags().StringVarP(&requestTrackerTo, "to", "", "", "created before. format : "+common.DefaultTimeLayout)
+	requestTrackerGetALLCmd.PersistentFlags().StringVarP(&requestTrackerSource, "source", "s", "", "source function")
+	requestTrackerGetALLCmd.PersistentFlags().IntVarP(&requestTrackerStatus, "status", "", 0, "response status")
+	requestTrackerGetALLCmd.PersistentFlags().StringVarP(&requestTrackerState, "state", "", "", "completed or pending")
+	requestTrackerGetALLCmd.PersistentFlags().IntVarP(&requestTrackerLimit, "limit", "l", 0, "page size")
+	requestTrackerGetALLCmd.PersistentFlags().StringVarP(&requestTrackerCursor, "cursor", "c", "", "next cursor of previous page")
+
+	requestTrackerSearchCmd.PersistentFlags().StringVarP(&requestTrackerLogType, "type", "t", "", "log type. eg : info, error")
+	requestTrackerSearchCmd.PersistentFlags().StringVarP(&requestTrackerSource, "source", "s", "", "log source. function-container-id or app-id")
+	requestTrackerSearchCmd.PersistentFlags().StringVarP(&requestTrackerFrom, "from", "", "", "logged after. format : "+common.DefaultTimeLayout)
+	requestTrackerSearchCmd.PersistentFlags().StringVarP(&requestTrackerTo, "to", "", "", "logged before. format : "+common.DefaultTimeLayout)
+	requestTrackerSearchCmd.PersistentFlags().StringVarP(&requestTrackerQuery, "query", "q", "", "case-insensitive text in log message")
+	requestTrackerSearchCmd.PersistentFlags().StringVarP(&requestTrackerRegex, "regex", "r", "", "regular expression matched against log message")
+	requestTrackerSearchCmd.PersistentFlags().IntVarP(&requestTrackerLimit, "limit", "l", 0, "max request-trackers")
 
 }
 
@@ -94,21 +131,49 @@ var requestTrackerLogsCmd = &cobra.Command{
 	},
 }
 
+var requestTrackerSearchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "request-tracker : search logs",
+	Long:  `request-tracker : search logs`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requestTrackerSearch(cmd, args)
+	},
+}
+
 func requestTrackerGetALL(cmd *cobra.Command, args []string) {
 
+	query := url.Values{}
+	setQueryValue(query, "from", requestTrackerFrom)
+	setQueryValue(query, "to", requestTrackerTo)
+	setQueryValue(query, "source", requestTrackerSource)
+	setQueryValue(query, "state", requestTrackerState)
+	setQueryValue(query, "cursor", requestTrackerCursor)
+
+	if requestTrackerStatus != 0 {
+		query.Set("status", common.IntToStr(requestTrackerStatus))
+	}
+
+	if requestTrackerLimit != 0 {
+		query.Set("limit", common.IntToStr(requestTrackerLimit))
+	}
+
 	mgrService := appContainer.GetMgrService()
-	requestTrackers, err := mgrService.RequestTrackerGetALL()
+	page, err := mgrService.RequestTrackerGetALL(query)
 	if err != nil {
 		prepareErrorResponse(cmd, err)
 	}
 
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Request_ID", "Source", "Created"})
+	table.SetHeader([]string{"Request_ID", "Source", "Status", "Created", "Completed"})
 	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 	table.SetCenterSeparator("|")
-	table.AppendBulk(prepareRequestTrackerTable(requestTrackers))
+	table.AppendBulk(prepareRequestTrackerTable(page.RequestTrackers))
 	table.Render()
 
+	if page.NextCursor != "" {
+		fmt.Printf("next page : --cursor \"%s\"\n", page.NextCursor)
+	}
+
 }
 
 func requestTrackerGetByRequestID(cmd *cobra.Command, args []string) {
@@ -130,6 +195,11 @@ func requestTrackerGetByRequestID(cmd *cobra.Command, args []string) {
 
 func requestTrackerLogs(cmd *cobra.Command, args []string) {
 
+	if requestTrackerFollow {
+		requestTrackerLogsFollow(cmd)
+		return
+	}
+
 	if requestID == "" {
 		prepareErrorResponse(cmd, &types.ErrorResponse{Cause: "request-id is empty"})
 	}
@@ -149,6 +219,62 @@ func requestTrackerLogs(cmd *cobra.Command, args []string) {
 
 }
 
+func requestTrackerSearch(cmd *cobra.Command, args []string) {
+
+	query := url.Values{}
+	setQueryValue(query, "type", requestTrackerLogType)
+	setQueryValue(query, "source", requestTrackerSource)
+	setQueryValue(query, "from", requestTrackerFrom)
+	setQueryValue(query, "to", requestTrackerTo)
+	setQueryValue(query, "q", requestTrackerQuery)
+	setQueryValue(query, "regex", requestTrackerRegex)
+
+	if requestTrackerLimit != 0 {
+		query.Set("limit", common.IntToStr(requestTrackerLimit))
+	}
+
+	mgrService := appContainer.GetMgrService()
+	results, err := mgrService.RequestTrackerSearch(query)
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Request_ID", "Type", "Time", "Message", "Source"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(prepareRequestTrackerSearchTable(results.Results))
+	table.Render()
+
+	if results.Truncated {
+		fmt.Println("more request-trackers may match. narrow the search")
+	}
+
+}
+
+func requestTrackerLogsFollow(cmd *cobra.Command) {
+
+	if requestID == "" && requestTrackerFunction == "" {
+		prepareErrorResponse(cmd, &types.ErrorResponse{Cause: "request-id or function should be provided"})
+	}
+
+	mgrService := appContainer.GetMgrService()
+	err := mgrService.RequestTrackerFollow(requestID, requestTrackerFunction, func(message types.RequestTrackerMessage) {
+
+		l := message.Log
+		fmt.Printf("%s %s %s [%s] %s\n", message.RequestID, l.Time, l.Type, l.Source, l.Message)
+
+		if message.Completed {
+			fmt.Printf("%s completed. status : %d\n", message.RequestID, message.Response.Status)
+		}
+
+	})
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+}
+
 func prepareRequestTrackerTable(data []types.RequestTracker) [][]string {
 
 	var rows [][]string
@@ -158,8 +284,14 @@ func prepareRequestTrackerTable(data []types.RequestTracker) [][]string {
 		requestID := val.RequestID
 		source := val.Source
 		createdAt := val.CreatedAt
+		completedAt := val.CompletedAt
 
-		rows = append(rows, []string{requestID, source, createdAt})
+		status := ""
+		if val.Response.Status != 0 {
+			status = common.IntToStr(val.Response.Status)
+		}
+
+		rows = append(rows, []string{requestID, source, status, createdAt, completedAt})
 
 	}
 
@@ -186,3 +318,50 @@ func prepareRequestTrackerLogsTable(requestTracker types.RequestTracker) [][]str
 	return rows
 
 }
+
+func prepareRequestTrackerSearchTable(data []types.RequestTrackerSearchResult) [][]string {
+
+	var rows [][]string
+
+	for _, val := range data {
+		for _, highlight := range val.Highlights {
+
+			requestID := val.RequestTracker.RequestID
+			logType := highlight.Log.Type
+			time := highlight.Log.Time
+			message := highlightMatches(highlight.Log.Message, highlight.Matches)
+			source := highlight.Log.Source
+
+			rows = append(rows, []string{requestID, logType, time, message, source})
+
+		}
+	}
+
+	return rows
+
+}
+
+//highlightMatches color matched parts of the message
+func highlightMatches(message string, matches [][]int) string {
+
+	highlight := color.New(color.FgRed, color.Bold).SprintFunc()
+
+	result := ""
+	last := 0
+	for _, match := range matches {
+		if len(match) != 2 || match[0] < last || match[1] > len(message) {
+			continue
+		}
+		result += message[last:match[0]] + highlight(message[match[0]:match[1]])
+		last = match[1]
+	}
+
+	return result + message[last:]
+
+}
+
+func setQueryValue(query url.Values, key string, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
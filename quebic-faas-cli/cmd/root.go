@@ -32,6 +32,10 @@ func init() {
 func setupCmds() {
 	rootCmd.AddCommand(functionCmd)
 	rootCmd.AddCommand(routeCmd)
+	rootCmd.AddCommand(runtimeCmd)
+	rootCmd.AddCommand(registryCmd)
+	rootCmd.AddCommand(gitCredentialCmd)
+	rootCmd.AddCommand(jobCmd)
 	rootCmd.AddCommand(requestTrackerCmd)
 	rootCmd.AddCommand(mgrCompCmd)
 	rootCmd.AddCommand(apigatewayCmd)
@@ -40,6 +44,7 @@ func setupCmds() {
 	rootCmd.AddCommand(mgrCmd)
 	rootCmd.AddCommand(ingressCmd)
 	rootCmd.AddCommand(userCmd)
+	rootCmd.AddCommand(auditCmd)
 }
 
 func setupFlags() {
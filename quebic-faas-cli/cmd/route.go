@@ -28,6 +28,7 @@ import (
 
 var routeSpecFile string
 var routeName string
+var routeForce bool
 
 func init() {
 	setupRouteCmds()
@@ -56,6 +57,7 @@ func setupRouteFlags() {
 
 	//route-update
 	routeUpdateCmd.PersistentFlags().StringVarP(&routeSpecFile, "spec", "f", "route.yml", "route input file")
+	routeUpdateCmd.PersistentFlags().BoolVar(&routeForce, "force", false, "if true route is updated even when the spec has no revision")
 
 	//route-inspect
 	routeInspectCmd.PersistentFlags().StringVarP(&routeName, "name", "n", "", "route name")
@@ -106,6 +108,10 @@ func routeSave(cmd *cobra.Command, args []string, isAdd bool) {
 		prepareError(cmd, err)
 	}
 
+	if !isAdd {
+		checkUpdateRevision(cmd, route.Revision, routeForce)
+	}
+
 	mgrService := appContainer.GetMgrService()
 
 	var errResponse *types.ErrorResponse
@@ -0,0 +1,206 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"quebic-faas/quebic-faas-cli/common"
+	"quebic-faas/types"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var runtimeSpecFile string
+var runtimeName string
+var runtimeForce bool
+
+func init() {
+	setupRuntimeCmds()
+	setupRuntimeFlags()
+}
+
+var runtimeCmd = &cobra.Command{
+	Use:   "runtime",
+	Short: "Runtime catalog commonds",
+	Long:  `Runtime catalog commonds`,
+}
+
+func setupRuntimeCmds() {
+
+	runtimeCmd.AddCommand(runtimeCreateCmd)
+	runtimeCmd.AddCommand(runtimeUpdateCmd)
+	runtimeCmd.AddCommand(runtimeGetALLCmd)
+	runtimeCmd.AddCommand(runtimeInspectCmd)
+	runtimeCmd.AddCommand(runtimeDeleteCmd)
+
+}
+
+func setupRuntimeFlags() {
+
+	//runtime-create
+	runtimeCreateCmd.PersistentFlags().StringVarP(&runtimeSpecFile, "spec", "f", "runtime.yml", "runtime input file")
+
+	//runtime-update
+	runtimeUpdateCmd.PersistentFlags().StringVarP(&runtimeSpecFile, "spec", "f", "runtime.yml", "runtime input file")
+	runtimeUpdateCmd.PersistentFlags().BoolVar(&runtimeForce, "force", false, "if true runtime is updated even when the spec has no revision")
+
+	//runtime-inspect
+	runtimeInspectCmd.PersistentFlags().StringVarP(&runtimeName, "name", "n", "", "runtime name")
+
+	//runtime-delete
+	runtimeDeleteCmd.PersistentFlags().StringVarP(&runtimeName, "name", "n", "", "runtime name")
+
+}
+
+var runtimeCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "runtime : create",
+	Long:  `runtime : create`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runtimeSave(cmd, args, true)
+	},
+}
+
+var runtimeUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "runtime : update",
+	Long:  `runtime : update`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runtimeSave(cmd, args, false)
+	},
+}
+
+var runtimeGetALLCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "runtime : get-all",
+	Long:  `runtime : get-all`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runtimeGetALL(cmd, args)
+	},
+}
+
+var runtimeInspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "runtime : inspect runtime details",
+	Long:  `runtime : inspect runtime details`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runtimeGetByName(cmd, args)
+	},
+}
+
+var runtimeDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "runtime : delete",
+	Long:  `runtime : delete`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runtimeDelete(cmd, args)
+	},
+}
+
+func runtimeSave(cmd *cobra.Command, args []string, isAdd bool) {
+
+	runtimeSpec := &types.RuntimeSpec{}
+	err := common.ParseYAMLFileToObject(runtimeSpecFile, runtimeSpec)
+	if err != nil {
+		prepareError(cmd, err)
+	}
+
+	if !isAdd {
+		checkUpdateRevision(cmd, runtimeSpec.Revision, runtimeForce)
+	}
+
+	mgrService := appContainer.GetMgrService()
+
+	var errResponse *types.ErrorResponse
+	if isAdd {
+		errResponse = mgrService.RuntimeCreate(runtimeSpec)
+	} else {
+		errResponse = mgrService.RuntimeUpdate(runtimeSpec)
+	}
+
+	if errResponse != nil {
+		prepareErrorResponse(cmd, errResponse)
+	}
+
+	color.Green("%s runtime is saved", runtimeSpec.GetID())
+
+}
+
+func runtimeGetALL(cmd *cobra.Command, args []string) {
+
+	mgrService := appContainer.GetMgrService()
+	runtimes, err := mgrService.RuntimeGetALL()
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Version", "Base", "Image", "Extensions"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(prepareRuntimeTable(runtimes))
+	table.Render()
+
+}
+
+func runtimeGetByName(cmd *cobra.Command, args []string) {
+
+	mgrService := appContainer.GetMgrService()
+	runtimeSpec, err := mgrService.RuntimeGetByName(runtimeName)
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	ymlStr, _ := yaml.Marshal(runtimeSpec)
+	fmt.Printf("%s", ymlStr)
+
+}
+
+func runtimeDelete(cmd *cobra.Command, args []string) {
+
+	mgrService := appContainer.GetMgrService()
+	err := mgrService.RuntimeDelete(runtimeName)
+	if err != nil {
+		prepareErrorResponse(cmd, err)
+	}
+
+	color.Green("%s runtime is deleted", runtimeName)
+
+}
+
+func prepareRuntimeTable(data []types.RuntimeSpec) [][]string {
+
+	var rows [][]string
+
+	for _, val := range data {
+
+		name := val.Name
+		version := val.Version
+		base := val.Base
+		image := val.Image
+		extensions := strings.Join(val.Extensions, ", ")
+
+		rows = append(rows, []string{name, version, base, image, extensions})
+
+	}
+
+	return rows
+
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"net/url"
+	"quebic-faas/types"
+)
+
+const api_audit = "/audit"
+
+//AuditGetALL get audit records. newest first
+//query => entity, id, actor, from, to, limit, cursor
+func (mgrService *MgrService) AuditGetALL(query url.Values) (*types.AuditPage, *types.ErrorResponse) {
+
+	response, err := mgrService.GET(api_audit+"?"+query.Encode(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		return nil, processErrorResponse(response)
+	}
+
+	page := &types.AuditPage{}
+	parseResponseData(response.Data, page)
+
+	return page, nil
+
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"io"
+	"quebic-faas/types"
+)
+
+const api_function_artifacts = "/artifacts"
+
+//FunctionArtifactGetALL get all artifacts of the function
+func (mgrService *MgrService) FunctionArtifactGetALL(function string) ([]types.FunctionArtifact, *types.ErrorResponse) {
+
+	response, err := mgrService.GET(api_function+"/"+function+api_function_artifacts, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		return nil, processErrorResponse(response)
+	}
+
+	var artifacts []types.FunctionArtifact
+	parseResponseData(response.Data, &artifacts)
+
+	return artifacts, nil
+
+}
+
+//FunctionArtifactDownload stream artifact of the function version into w
+func (mgrService *MgrService) FunctionArtifactDownload(function string, version string, w io.Writer) *types.ErrorResponse {
+
+	path := api_function + "/" + function + api_function_artifacts + "/" + version
+
+	response, err := mgrService.RAW(path, request_get, nil, nil, w)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= 300 {
+		return processErrorResponse(response)
+	}
+
+	return nil
+
+}
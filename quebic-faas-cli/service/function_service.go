@@ -1,31 +1,37 @@
 package service
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
 	"quebic-faas/common"
 	"quebic-faas/types"
+	"strings"
 )
 
 const api_function = "/functions"
 
-//FunctionCreate create function
-func (mgrService *MgrService) FunctionCreate(functionDTO *types.FunctionDTO) *types.ErrorResponse {
+//FunctionCreate create function. function is built and deployed by the returned job
+//job is followed until it is finished when jobHandlers is given. otherwise returns once function is saved
+func (mgrService *MgrService) FunctionCreate(functionDTO *types.FunctionDTO, jobHandlers *JobHandlers) (*types.Job, *types.ErrorResponse) {
 
-	return mgrService.functionSave(functionDTO, request_post)
+	return mgrService.functionSave(functionDTO, request_post, jobHandlers)
 
 }
 
-//FunctionUpdate update function
-func (mgrService *MgrService) FunctionUpdate(functionDTO *types.FunctionDTO) *types.ErrorResponse {
+//FunctionUpdate update function. function is built and deployed by the returned job
+//job is followed until it is finished when jobHandlers is given. otherwise returns once function is saved
+func (mgrService *MgrService) FunctionUpdate(functionDTO *types.FunctionDTO, jobHandlers *JobHandlers) (*types.Job, *types.ErrorResponse) {
 
-	return mgrService.functionSave(functionDTO, request_put)
+	return mgrService.functionSave(functionDTO, request_put, jobHandlers)
 
 }
 
@@ -156,25 +162,115 @@ func (mgrService *MgrService) FunctionsGetByName(name string) (*types.Function,
 
 }
 
-func (mgrService *MgrService) functionSave(functionDTO *types.FunctionDTO, requestMethod string) *types.ErrorResponse {
+func (mgrService *MgrService) functionSave(
+	functionDTO *types.FunctionDTO,
+	requestMethod string,
+	jobHandlers *JobHandlers) (*types.Job, *types.ErrorResponse) {
 
-	response, err := mgrService.makeMultipartFormRequest(functionDTO, requestMethod, nil)
+	path := api_function
+	if jobHandlers != nil {
+		path += "?" + common.FunctionSaveParam_STREAM + "=true"
+	}
+
+	req, errResponse := mgrService.prepareMultipartFormRequest(functionDTO, path, requestMethod, nil)
+	if errResponse != nil {
+		return nil, errResponse
+	}
+
+	//streamed job is followed within this request
+	ctx := context.Background()
+	if jobHandlers != nil && jobHandlers.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, jobHandlers.Timeout)
+		defer cancel()
+	}
+	req = req.WithContext(ctx)
+
+	res, err := newHTTPClient().Do(req)
 	if err != nil {
-		return err
+		return nil, makeErrorToErrorResponse(err)
 	}
+	defer res.Body.Close()
+
+	//job is not streamed. eg: validation failed, detached save, manager is not able to stream
+	if !strings.HasPrefix(res.Header.Get("Content-Type"), common.FunctionBuildContentType) {
+
+		responseBody, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, makeErrorToErrorResponse(err)
+		}
+
+		if res.StatusCode >= 300 {
+			return nil, processErrorResponse(&ResponseMessage{StatusCode: res.StatusCode, Data: responseBody})
+		}
+
+		job := &types.Job{}
+		parseResponseData(responseBody, job)
+
+		if jobHandlers == nil {
+			return job, nil
+		}
+
+		return mgrService.JobWait(job.ID, *jobHandlers)
 
-	if response.StatusCode >= 300 {
-		return processErrorResponse(response)
 	}
 
-	parseResponseData(response.Data, functionDTO)
+	return readFunctionBuildStream(ctx, res.Body, *jobHandlers)
 
-	return nil
 }
 
-func (mgrService *MgrService) makeMultipartFormRequest(functionDTO *types.FunctionDTO, method string, header map[string]string) (*ResponseMessage, *types.ErrorResponse) {
+//readFunctionBuildStream read job messages until the finished job is received
+func readFunctionBuildStream(ctx context.Context, reader io.Reader, jobHandlers JobHandlers) (*types.Job, *types.ErrorResponse) {
+
+	scanner := bufio.NewScanner(reader)
+	//last message carries whole job
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
 
-	url := mgrService.prepareURL(api_function)
+	jobID := ""
+
+	for scanner.Scan() {
+
+		message := types.FunctionBuildMessage{}
+		err := json.Unmarshal(scanner.Bytes(), &message)
+		if err != nil {
+			return nil, makeErrorToErrorResponse(fmt.Errorf("unable to parse job message %v", err))
+		}
+
+		jobID = message.JobID
+
+		if message.Stream != "" {
+			jobHandlers.LogHandler(message.Stream)
+		}
+
+		if message.State != "" {
+			jobHandlers.StateHandler(message.State)
+		}
+
+		if message.Job != nil {
+			return message.Job, nil
+		}
+
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, jobTimeoutError(jobID, jobHandlers.Timeout)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, makeErrorToErrorResponse(fmt.Errorf("job %s stream is broken %v", jobID, err))
+	}
+
+	return nil, makeErrorToErrorResponse(fmt.Errorf("job %s stream is closed before job is finished", jobID))
+
+}
+
+func (mgrService *MgrService) prepareMultipartFormRequest(
+	functionDTO *types.FunctionDTO,
+	path string,
+	method string,
+	header map[string]string) (*http.Request, *types.ErrorResponse) {
+
+	url := mgrService.prepareURL(path)
 
 	requestBody := &bytes.Buffer{}
 	writer := multipart.NewWriter(requestBody)
@@ -183,12 +279,16 @@ func (mgrService *MgrService) makeMultipartFormRequest(functionDTO *types.Functi
 	specDataProcess(writer, functionDTO)
 
 	//Artifact file
-	err := artifactFileProcess(writer, functionDTO.Function.Source)
-	if err != nil {
-		return nil, makeErrorToErrorResponse(err)
+	//function which deployed from an existing image or fetched from git does not upload source
+	function := functionDTO.Function
+	if function.Source != "" || (function.Image == "" && function.Git == nil) {
+		err := artifactFileProcess(writer, function.Source)
+		if err != nil {
+			return nil, makeErrorToErrorResponse(err)
+		}
 	}
 
-	err = writer.Close()
+	err := writer.Close()
 	if err != nil {
 		return nil, makeErrorToErrorResponse(err)
 	}
@@ -207,7 +307,7 @@ func (mgrService *MgrService) makeMultipartFormRequest(functionDTO *types.Functi
 		}
 	}
 
-	return call(req)
+	return req, nil
 
 }
 
@@ -246,3 +346,8 @@ func artifactFileProcess(writer *multipart.Writer, filePath string) error {
 
 	return err
 }
+
+//FunctionLogs stream logs of all function replicas. blocks until stream is closed when follow is set
+func (mgrService *MgrService) FunctionLogs(logDTO *types.FunctionContainerLogDTO, lineHandler func(line string)) *types.ErrorResponse {
+	return mgrService.STREAM("/function_containers/logs", request_post, logDTO, nil, lineHandler)
+}
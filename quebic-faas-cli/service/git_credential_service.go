@@ -0,0 +1,91 @@
+package service
+
+import (
+	"quebic-faas/types"
+)
+
+const api_git_credential = "/git_credentials"
+
+//GitCredentialGetALL get all git credentials. secrets are not replied
+func (mgrService *MgrService) GitCredentialGetALL() ([]types.GitCredential, *types.ErrorResponse) {
+
+	response, err := mgrService.GET(api_git_credential, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		return nil, processErrorResponse(response)
+	}
+
+	var credentials []types.GitCredential
+	parseResponseData(response.Data, &credentials)
+
+	return credentials, nil
+
+}
+
+//GitCredentialGetByHost get git credential by host
+func (mgrService *MgrService) GitCredentialGetByHost(host string) (*types.GitCredential, *types.ErrorResponse) {
+
+	response, err := mgrService.GET(api_git_credential+"/"+host, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		return nil, processErrorResponse(response)
+	}
+
+	credential := new(types.GitCredential)
+	parseResponseData(response.Data, credential)
+
+	return credential, nil
+
+}
+
+//GitCredentialCreate create git credential
+func (mgrService *MgrService) GitCredentialCreate(credential *types.GitCredential) *types.ErrorResponse {
+
+	return mgrService.gitCredentialSave(credential, request_post)
+
+}
+
+//GitCredentialUpdate update git credential
+func (mgrService *MgrService) GitCredentialUpdate(credential *types.GitCredential) *types.ErrorResponse {
+
+	return mgrService.gitCredentialSave(credential, request_put)
+
+}
+
+//GitCredentialDelete delete git credential
+func (mgrService *MgrService) GitCredentialDelete(host string) *types.ErrorResponse {
+
+	response, err := mgrService.DELETE(api_git_credential+"/"+host, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= 300 {
+		return processErrorResponse(response)
+	}
+
+	return nil
+
+}
+
+func (mgrService *MgrService) gitCredentialSave(credential *types.GitCredential, requestMethod string) *types.ErrorResponse {
+
+	response, err := mgrService.makeRequest(api_git_credential, requestMethod, credential, nil)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= 300 {
+		return processErrorResponse(response)
+	}
+
+	parseResponseData(response.Data, credential)
+
+	return nil
+}
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"bufio"
 	"crypto/tls"
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"quebic-faas/common"
@@ -35,8 +37,102 @@ func (mgrService *MgrService) DELETE(path string, payload interface{}, header ma
 	return mgrService.makeRequest(path, request_delete, payload, header)
 }
 
+//STREAM request. lineHandler is called for each line of the response until the stream is closed
+func (mgrService *MgrService) STREAM(
+	path string,
+	method string,
+	payload interface{},
+	header map[string]string,
+	lineHandler func(line string)) *types.ErrorResponse {
+
+	req, err := mgrService.prepareRequest(path, method, payload, header)
+	if err != nil {
+		return makeErrorToErrorResponse(err)
+	}
+
+	res, err := newHTTPClient().Do(req)
+	if err != nil {
+		return makeErrorToErrorResponse(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		responseBody, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return makeErrorToErrorResponse(err)
+		}
+		return processErrorResponse(&ResponseMessage{StatusCode: res.StatusCode, Data: responseBody})
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		lineHandler(scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return makeErrorToErrorResponse(err)
+	}
+
+	return nil
+
+}
+
+//RAW request. body is sent as it is. successful response is copied into responseWriter
+//when responseWriter is nil or request failed, response is returned in ResponseMessage
+func (mgrService *MgrService) RAW(
+	path string,
+	method string,
+	body io.Reader,
+	header map[string]string,
+	responseWriter io.Writer) (*ResponseMessage, *types.ErrorResponse) {
+
+	req, err := mgrService.prepareRequest(path, method, nil, header)
+	if err != nil {
+		return nil, makeErrorToErrorResponse(err)
+	}
+
+	if body != nil {
+		req.Body = ioutil.NopCloser(body)
+		req.ContentLength = -1
+		req.GetBody = nil
+	}
+
+	res, err := newHTTPClient().Do(req)
+	if err != nil {
+		return nil, makeErrorToErrorResponse(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 300 && responseWriter != nil {
+		_, err = io.Copy(responseWriter, res.Body)
+		if err != nil {
+			return nil, makeErrorToErrorResponse(err)
+		}
+		return &ResponseMessage{StatusCode: res.StatusCode}, nil
+	}
+
+	responseBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, makeErrorToErrorResponse(err)
+	}
+
+	return &ResponseMessage{StatusCode: res.StatusCode, Data: responseBody}, nil
+
+}
+
 func (mgrService *MgrService) makeRequest(path string, method string, payload interface{}, header map[string]string) (*ResponseMessage, *types.ErrorResponse) {
 
+	req, err := mgrService.prepareRequest(path, method, payload, header)
+	if err != nil {
+		return nil, makeErrorToErrorResponse(err)
+	}
+
+	return call(req)
+
+}
+
+func (mgrService *MgrService) prepareRequest(path string, method string, payload interface{}, header map[string]string) (*http.Request, error) {
+
 	url := mgrService.prepareURL(path)
 
 	var jsonPayload []byte
@@ -50,7 +146,7 @@ func (mgrService *MgrService) makeRequest(path string, method string, payload in
 
 	req, err := http.NewRequest(method, url, requestBody)
 	if err != nil {
-		return nil, makeErrorToErrorResponse(err)
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -62,20 +158,25 @@ func (mgrService *MgrService) makeRequest(path string, method string, payload in
 		}
 	}
 
-	return call(req)
+	req.Host = common.IngressHostManager
 
-}
+	return req, nil
 
-func call(req *http.Request) (*ResponseMessage, *types.ErrorResponse) {
+}
 
-	req.Host = common.IngressHostManager
+func newHTTPClient() *http.Client {
 
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 	}
-	client := &http.Client{Transport: tr}
 
-	res, err := client.Do(req)
+	return &http.Client{Transport: tr}
+
+}
+
+func call(req *http.Request) (*ResponseMessage, *types.ErrorResponse) {
+
+	res, err := newHTTPClient().Do(req)
 	if err != nil {
 		return nil, makeErrorToErrorResponse(err)
 	}
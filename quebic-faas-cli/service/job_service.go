@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"quebic-faas/types"
+	"time"
+)
+
+const api_job = "/jobs"
+
+//jobPollInterval interval of polling the job until it is finished
+const jobPollInterval = time.Second
+
+//JobHandlers called while a job is followed
+//StateHandler is called when job moves into a state. LogHandler is called for each new output line
+//Timeout stops following the job after it. zero follows until the job is finished
+type JobHandlers struct {
+	StateHandler func(state string)
+	LogHandler   func(line string)
+	Timeout      time.Duration
+}
+
+//JobGetByID get job by id
+func (mgrService *MgrService) JobGetByID(id string) (*types.Job, *types.ErrorResponse) {
+
+	response, err := mgrService.GET(api_job+"/"+id, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		return nil, processErrorResponse(response)
+	}
+
+	job := new(types.Job)
+	parseResponseData(response.Data, job)
+
+	return job, nil
+
+}
+
+//JobWait poll the job until it is ready or failed
+func (mgrService *MgrService) JobWait(id string, jobHandlers JobHandlers) (*types.Job, *types.ErrorResponse) {
+
+	state := ""
+	printedLogs := 0
+
+	deadline := time.Now().Add(jobHandlers.Timeout)
+
+	for {
+
+		job, err := mgrService.JobGetByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		//job keeps last lines only. lines which are dropped before poll are skipped
+		newLogs := job.LogCount - printedLogs
+		if newLogs > len(job.Logs) {
+			newLogs = len(job.Logs)
+		}
+		for _, line := range job.Logs[len(job.Logs)-newLogs:] {
+			jobHandlers.LogHandler(line)
+		}
+		printedLogs = job.LogCount
+
+		if job.State != state {
+			state = job.State
+			jobHandlers.StateHandler(state)
+		}
+
+		if job.IsFinished() {
+			return job, nil
+		}
+
+		if jobHandlers.Timeout > 0 && time.Now().After(deadline) {
+			return nil, jobTimeoutError(id, jobHandlers.Timeout)
+		}
+
+		time.Sleep(jobPollInterval)
+
+	}
+
+}
+
+//jobTimeoutError timeout only stops following the job
+func jobTimeoutError(id string, timeout time.Duration) *types.ErrorResponse {
+	return makeErrorToErrorResponse(fmt.Errorf("job %s is not finished within %v. job keeps running on the manager", id, timeout))
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"io"
+	"net/url"
+	"quebic-faas/types"
+	"strconv"
+	"strings"
+)
+
+const api_mgr_backup = "/manager/backup"
+const api_mgr_restore = "/manager/restore"
+
+//ManagerBackup stream snapshot of the entities into w. empty entities => all entities
+func (mgrService *MgrService) ManagerBackup(entities []string, w io.Writer) *types.ErrorResponse {
+
+	query := url.Values{}
+	if len(entities) > 0 {
+		query.Set("entities", strings.Join(entities, ","))
+	}
+
+	response, err := mgrService.RAW(api_mgr_backup+"?"+query.Encode(), request_get, nil, nil, w)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= 300 {
+		return processErrorResponse(response)
+	}
+
+	return nil
+
+}
+
+//ManagerRestore restore the entities from backup. dryRun only reports the differences
+func (mgrService *MgrService) ManagerRestore(
+	entities []string,
+	backup io.Reader,
+	dryRun bool,
+	redeploy bool) (*types.RestoreReport, *types.ErrorResponse) {
+
+	query := url.Values{}
+	if len(entities) > 0 {
+		query.Set("entities", strings.Join(entities, ","))
+	}
+	query.Set("dryRun", strconv.FormatBool(dryRun))
+	query.Set("redeploy", strconv.FormatBool(redeploy))
+
+	header := map[string]string{"Content-Type": "application/x-ndjson"}
+
+	response, err := mgrService.RAW(api_mgr_restore+"?"+query.Encode(), request_post, backup, header, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		return nil, processErrorResponse(response)
+	}
+
+	report := &types.RestoreReport{}
+	parseResponseData(response.Data, report)
+
+	return report, nil
+
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"quebic-faas/types"
+)
+
+const api_registry = "/registries"
+
+//RegistryGetALL get all registries. passwords are not replied
+func (mgrService *MgrService) RegistryGetALL() ([]types.Registry, *types.ErrorResponse) {
+
+	response, err := mgrService.GET(api_registry, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		return nil, processErrorResponse(response)
+	}
+
+	var registries []types.Registry
+	parseResponseData(response.Data, &registries)
+
+	return registries, nil
+
+}
+
+//RegistryGetByServer get registry by server
+func (mgrService *MgrService) RegistryGetByServer(server string) (*types.Registry, *types.ErrorResponse) {
+
+	response, err := mgrService.GET(api_registry+"/"+server, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		return nil, processErrorResponse(response)
+	}
+
+	registry := new(types.Registry)
+	parseResponseData(response.Data, registry)
+
+	return registry, nil
+
+}
+
+//RegistryCreate create registry
+func (mgrService *MgrService) RegistryCreate(registry *types.Registry) *types.ErrorResponse {
+
+	return mgrService.registrySave(registry, request_post)
+
+}
+
+//RegistryUpdate update registry
+func (mgrService *MgrService) RegistryUpdate(registry *types.Registry) *types.ErrorResponse {
+
+	return mgrService.registrySave(registry, request_put)
+
+}
+
+//RegistryDelete delete registry
+func (mgrService *MgrService) RegistryDelete(server string) *types.ErrorResponse {
+
+	response, err := mgrService.DELETE(api_registry+"/"+server, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= 300 {
+		return processErrorResponse(response)
+	}
+
+	return nil
+
+}
+
+func (mgrService *MgrService) registrySave(registry *types.Registry, requestMethod string) *types.ErrorResponse {
+
+	response, err := mgrService.makeRequest(api_registry, requestMethod, registry, nil)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= 300 {
+		return processErrorResponse(response)
+	}
+
+	parseResponseData(response.Data, registry)
+
+	return nil
+}
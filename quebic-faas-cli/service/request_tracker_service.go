@@ -1,13 +1,24 @@
 package service
 
-import "quebic-faas/types"
+import (
+	"encoding/json"
+	"net/url"
+	"quebic-faas/types"
+	"strings"
+)
 
 const api_request_tracker = "/request-trackers"
 
-//RequestTrackerGetALL get all
-func (mgrService *MgrService) RequestTrackerGetALL() ([]types.RequestTracker, *types.ErrorResponse) {
+//RequestTrackerGetALL get page of request-trackers
+//query => from, to, source, status, state, limit, cursor
+func (mgrService *MgrService) RequestTrackerGetALL(query url.Values) (*types.RequestTrackerPage, *types.ErrorResponse) {
 
-	response, err := mgrService.GET(api_request_tracker, nil, nil)
+	path := api_request_tracker
+	if len(query) > 0 {
+		path = path + "?" + query.Encode()
+	}
+
+	response, err := mgrService.GET(path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -16,11 +27,30 @@ func (mgrService *MgrService) RequestTrackerGetALL() ([]types.RequestTracker, *t
 		return nil, processErrorResponse(response)
 	}
 
-	type requestTrackerArray []types.RequestTracker
-	var requestTrackers requestTrackerArray
-	parseResponseData(response.Data, &requestTrackers)
+	page := &types.RequestTrackerPage{}
+	parseResponseData(response.Data, page)
 
-	return requestTrackers, nil
+	return page, nil
+
+}
+
+//RequestTrackerSearch search logs of request-trackers
+//query => type, source, from, to, q, regex, limit
+func (mgrService *MgrService) RequestTrackerSearch(query url.Values) (*types.RequestTrackerSearchResults, *types.ErrorResponse) {
+
+	response, err := mgrService.GET(api_request_tracker+"/search?"+query.Encode(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		return nil, processErrorResponse(response)
+	}
+
+	results := &types.RequestTrackerSearchResults{}
+	parseResponseData(response.Data, results)
+
+	return results, nil
 
 }
 
@@ -42,3 +72,35 @@ func (mgrService *MgrService) RequestTrackerGetByID(requestID string) (*types.Re
 	return rt, nil
 
 }
+
+//RequestTrackerFollow follow request-tracker messages of a request or a function. blocks until stream is closed
+func (mgrService *MgrService) RequestTrackerFollow(requestID string, function string, messageHandler func(message types.RequestTrackerMessage)) *types.ErrorResponse {
+
+	query := url.Values{}
+	if requestID != "" {
+		query.Set("requestID", requestID)
+	}
+	if function != "" {
+		query.Set("function", function)
+	}
+
+	path := api_request_tracker + "/follow?" + query.Encode()
+
+	//server-sent events. only data lines carry messages
+	return mgrService.STREAM(path, request_get, nil, nil, func(line string) {
+
+		if !strings.HasPrefix(line, "data:") {
+			return
+		}
+
+		message := types.RequestTrackerMessage{}
+		err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &message)
+		if err != nil {
+			return
+		}
+
+		messageHandler(message)
+
+	})
+
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"quebic-faas/types"
+)
+
+const api_runtime = "/runtimes"
+
+//RuntimeGetALL get all runtimes of the catalog
+func (mgrService *MgrService) RuntimeGetALL() ([]types.RuntimeSpec, *types.ErrorResponse) {
+
+	response, err := mgrService.GET(api_runtime, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		return nil, processErrorResponse(response)
+	}
+
+	var runtimes []types.RuntimeSpec
+	parseResponseData(response.Data, &runtimes)
+
+	return runtimes, nil
+
+}
+
+//RuntimeGetByName get runtime by name
+func (mgrService *MgrService) RuntimeGetByName(name string) (*types.RuntimeSpec, *types.ErrorResponse) {
+
+	response, err := mgrService.GET(api_runtime+"/"+name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		return nil, processErrorResponse(response)
+	}
+
+	runtimeSpec := new(types.RuntimeSpec)
+	parseResponseData(response.Data, runtimeSpec)
+
+	return runtimeSpec, nil
+
+}
+
+//RuntimeCreate create runtime
+func (mgrService *MgrService) RuntimeCreate(runtimeSpec *types.RuntimeSpec) *types.ErrorResponse {
+
+	return mgrService.runtimeSave(runtimeSpec, request_post)
+
+}
+
+//RuntimeUpdate update runtime
+func (mgrService *MgrService) RuntimeUpdate(runtimeSpec *types.RuntimeSpec) *types.ErrorResponse {
+
+	return mgrService.runtimeSave(runtimeSpec, request_put)
+
+}
+
+//RuntimeDelete delete runtime
+func (mgrService *MgrService) RuntimeDelete(name string) *types.ErrorResponse {
+
+	response, err := mgrService.DELETE(api_runtime+"/"+name, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= 300 {
+		return processErrorResponse(response)
+	}
+
+	return nil
+
+}
+
+func (mgrService *MgrService) runtimeSave(runtimeSpec *types.RuntimeSpec, requestMethod string) *types.ErrorResponse {
+
+	response, err := mgrService.makeRequest(api_runtime, requestMethod, runtimeSpec, nil)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= 300 {
+		return processErrorResponse(response)
+	}
+
+	parseResponseData(response.Data, runtimeSpec)
+
+	return nil
+}
@@ -31,29 +31,34 @@ import (
 	"quebic-faas/quebic-faas-mgr/db"
 	dep "quebic-faas/quebic-faas-mgr/deployment"
 	"quebic-faas/quebic-faas-mgr/deployment/kube_deployment"
+	"quebic-faas/quebic-faas-mgr/function/function_builder"
+	"quebic-faas/quebic-faas-mgr/function/function_runtime/runtime_catalog"
 	"quebic-faas/quebic-faas-mgr/function/function_util"
 	_gc "quebic-faas/quebic-faas-mgr/gc"
 	"quebic-faas/quebic-faas-mgr/httphandler"
+	"quebic-faas/quebic-faas-mgr/leader"
 	"quebic-faas/quebic-faas-mgr/logger"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/tracing"
 	"quebic-faas/types"
 	"time"
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	yaml "gopkg.in/yaml.v2"
-
-	bolt "github.com/coreos/bbolt"
 )
 
 //App app container
 type App struct {
 	config     mgrconfig.AppConfig
-	db         *bolt.DB
+	db         storage.Store
 	messenger  _messenger.Messenger
 	router     *mux.Router
 	loggerUtil logger.Logger
 	deployment dep.Deployment
-	gc         _gc.GC
+	builder    function_builder.Builder
+	gc         *_gc.GC
+	elector    *leader.Elector
 }
 
 //Start init app
@@ -62,19 +67,38 @@ func (app *App) Start() {
 	log.Printf("quebic-faas-manager starting")
 
 	//get db
-	db, err := db.GetDb()
-	defer db.Close()
+	db, err := db.GetDb(app.config.Storage)
 	if err != nil {
 		log.Fatalf("init failed. unable to connect db, error : %v", err)
 	}
+	defer db.Close()
 	app.db = db
 
+	//jobs which this manager was processing before restart can not finish anymore
+	app.failInterruptedJobs()
+
+	//setup metrics
+	app.setupMetrics()
+
+	//setup tracing
+	app.setupTracing()
+	defer tracing.Shutdown()
+
+	//flush forwarded logs
+	defer app.loggerUtil.Close()
+
 	//setup root admin user to loging manager
 	app.setupAdminUser()
 
+	//seed default runtimes into runtime catalog
+	runtime_catalog.Setup(app.db)
+
 	//setup deployment
 	app.setupDeployment()
 
+	//setup function image builder
+	app.setupBuilder()
+
 	//setup gc
 	app.setupGC()
 
@@ -84,8 +108,9 @@ func (app *App) Start() {
 	//setup manager components
 	app.setupManagerComponents()
 
-	//setup functions
-	app.setupFunctions()
+	//gc, compactions, function deploy reconciliation and schedulers run only on the leader
+	app.setupLeaderElection()
+	defer app.elector.Stop()
 
 	//setup router
 	app.router = mux.NewRouter()
@@ -124,6 +149,13 @@ func (app *App) setupConfiguration() {
 		app.config.APIGatewayConfig = savingConfig.APIGatewayConfig
 		app.config.IngressConfig = savingConfig.IngressConfig
 		app.config.MgrDashboardConfig = savingConfig.MgrDashboardConfig
+		app.config.RequestTracker = savingConfig.RequestTracker
+		app.config.Jobs = savingConfig.Jobs
+		app.config.LogForwarders = savingConfig.LogForwarders
+		app.config.Tracing = savingConfig.Tracing
+		app.config.Storage = savingConfig.Storage
+		app.config.LeaderElection = savingConfig.LeaderElection
+		app.config.Artifacts = savingConfig.Artifacts
 		app.config.InCluster = savingConfig.InCluster
 		app.config.Deployment = savingConfig.Deployment
 
@@ -137,6 +169,58 @@ func (app *App) setupConfigurationFromEnvVars() {
 	if ingressConfigStaticIP != "" {
 		app.config.IngressConfig.StaticIP = ingressConfigStaticIP
 	}
+
+	//shared storage of replicated managers can be given by the deployment
+	if storageBackend := os.Getenv(common.EnvKey_storage_backend); storageBackend != "" {
+		app.config.Storage.Backend = storageBackend
+	}
+
+	if storagePath := os.Getenv(common.EnvKey_storage_path); storagePath != "" {
+		app.config.Storage.Path = storagePath
+	}
+
+	if storageDataSource := os.Getenv(common.EnvKey_storage_dataSource); storageDataSource != "" {
+		app.config.Storage.DataSource = storageDataSource
+	}
+
+	//replicated managers enable leader election by the deployment
+	if leaderElectionEnabled := os.Getenv(common.EnvKey_leaderElection_enabled); leaderElectionEnabled != "" {
+		app.config.LeaderElection.Enabled = leaderElectionEnabled == "true"
+	}
+
+	if leaderElectionBackend := os.Getenv(common.EnvKey_leaderElection_backend); leaderElectionBackend != "" {
+		app.config.LeaderElection.Backend = leaderElectionBackend
+	}
+
+	//in-cluster builder is selected by the deployment when docker socket is not mounted
+	if builder := os.Getenv(common.EnvKey_dockerConfig_builder); builder != "" {
+		app.config.DockerConfig.Builder = builder
+	}
+
+	if registryAddress := os.Getenv(common.EnvKey_dockerConfig_registryAddress); registryAddress != "" {
+		app.config.DockerConfig.RegistryAddress = registryAddress
+	}
+
+	if registrySecret := os.Getenv(common.EnvKey_dockerConfig_registrySecret); registrySecret != "" {
+		app.config.DockerConfig.RegistrySecret = registrySecret
+	}
+
+	if buildVolumeClaim := os.Getenv(common.EnvKey_dockerConfig_buildVolumeClaim); buildVolumeClaim != "" {
+		app.config.DockerConfig.BuildVolumeClaim = buildVolumeClaim
+	}
+
+	//replicated managers must open registry credentials which are sealed by each other
+	if credentialsKey := os.Getenv(common.EnvKey_dockerConfig_credentialsKey); credentialsKey != "" {
+		app.config.DockerConfig.CredentialsKey = credentialsKey
+	}
+
+	if verifyImageDigest := os.Getenv(common.EnvKey_dockerConfig_verifyImageDigest); verifyImageDigest != "" {
+		app.config.DockerConfig.VerifyImageDigest = verifyImageDigest == "true"
+	}
+
+	if artifactsPath := os.Getenv(common.EnvKey_artifacts_path); artifactsPath != "" {
+		app.config.Artifacts.Path = artifactsPath
+	}
 }
 
 //SaveConfiguration saveConfiguration in .config file
@@ -156,6 +240,13 @@ func (app *App) SaveConfiguration() {
 		APIGatewayConfig:   app.config.APIGatewayConfig,
 		IngressConfig:      app.config.IngressConfig,
 		MgrDashboardConfig: app.config.MgrDashboardConfig,
+		RequestTracker:     app.config.RequestTracker,
+		Jobs:               app.config.Jobs,
+		LogForwarders:      app.config.LogForwarders,
+		Tracing:            app.config.Tracing,
+		Storage:            app.config.Storage,
+		LeaderElection:     app.config.LeaderElection,
+		Artifacts:          app.config.Artifacts,
 		KubernetesConfig:   app.config.KubernetesConfig,
 		InCluster:          app.config.InCluster,
 		Deployment:         app.config.Deployment,
@@ -178,7 +269,7 @@ func (app *App) setupAdminUser() {
 		Role:      auth.DefaultRole,
 	}
 
-	dao.AddUser(app.db, &adminUser)
+	dao.AddUser(app.db, &adminUser, dao.ActorSystem)
 
 }
 
@@ -202,8 +293,30 @@ func (app *App) setupDeployment() {
 
 }
 
+func (app *App) setupTracing() {
+
+	err := tracing.Init(app.config.AppID, app.config.Tracing)
+	if err != nil {
+		log.Printf("tracing setup failed. spans are not exported. error : %v", err)
+	}
+
+}
+
+func (app *App) setupBuilder() {
+
+	builder, err := function_builder.New(app.config.DockerConfig, app.deployment)
+	if err != nil {
+		log.Fatalf("function builder setup failed. error : %v", err)
+	}
+
+	app.builder = builder
+
+	log.Printf("function images are built by %s builder", builder.BuilderType())
+
+}
+
 func (app *App) setupGC() {
-	gc := _gc.GC{}
+	gc := &_gc.GC{}
 	gc.Init(app.config, app.db, app.deployment)
 	app.gc = gc
 }
@@ -243,6 +356,9 @@ func (app *App) setupManagerComponents() {
 	//shutDownRequest
 	app.setupShutDownRequestListener()
 
+	//function metrics
+	app.setupFunctionMetricsListener()
+
 	//setup logger
 	app.setupLogger()
 
@@ -289,6 +405,20 @@ func (app *App) setupApigatewayDataFetchListener() {
 			return
 		}
 
+		//grpc-services
+		var grpcServices []types.GRPCService
+		err = dao.GetAll(app.db, &types.GRPCService{}, func(k, v []byte) error {
+
+			grpcService := types.GRPCService{}
+			json.Unmarshal(v, &grpcService)
+			grpcServices = append(grpcServices, grpcService)
+			return nil
+		})
+		if err != nil {
+			messenger.ReplyError(event, err.Error(), 500)
+			return
+		}
+
 		//manager-components
 		allowComponents := [1]string{
 			common.ComponentEventBus,
@@ -330,8 +460,13 @@ func (app *App) setupApigatewayDataFetchListener() {
 			resources = make([]types.Resource, 0)
 		}
 
+		if grpcServices == nil {
+			grpcServices = make([]types.GRPCService, 0)
+		}
+
 		//assign data
 		apigatewayData.Resources = resources
+		apigatewayData.GRPCServices = grpcServices
 		apigatewayData.ManagerComponents = components
 		apigatewayData.CurrentDeploymentVersion = apiGateway.Version
 
@@ -411,14 +546,90 @@ func (app *App) setupShutDownRequestListener() {
 func (app *App) setupLogger() {
 
 	loggerUtil := logger.Logger{}
-	loggerUtil.Init(app.db, app.messenger)
+	loggerUtil.Init(app.db, app.messenger, app.config.RequestTracker)
+
+	err := loggerUtil.SetupForwarders(app.config.LogForwarders)
+	if err != nil {
+		log.Printf("log-forwarder setup failed. logs are not forwarded. error : %v", err)
+	}
+
 	loggerUtil.Listen()
 
 	app.loggerUtil = loggerUtil
 
 }
 
-func (app *App) setupFunctions() {
+func (app *App) setupLeaderElection() {
+
+	electionConfig := app.config.LeaderElection
+
+	//config saved before leader election settings
+	defaultConfig := mgrconfig.DefaultLeaderElectionConfig()
+	if electionConfig.LockName == "" {
+		electionConfig.LockName = defaultConfig.LockName
+	}
+	if electionConfig.LeaseDuration <= 0 {
+		electionConfig.LeaseDuration = defaultConfig.LeaseDuration
+	}
+	if electionConfig.RenewDeadline <= 0 {
+		electionConfig.RenewDeadline = defaultConfig.RenewDeadline
+	}
+	if electionConfig.RetryPeriod <= 0 {
+		electionConfig.RetryPeriod = defaultConfig.RetryPeriod
+	}
+
+	var lock leader.Lock
+
+	if electionConfig.Enabled {
+
+		//replicas which elect a leader have to share the state
+		if !app.config.Storage.Shared() {
+			storageBackend := app.config.Storage.Backend
+			if storageBackend == "" {
+				storageBackend = mgrconfig.StorageBolt
+			}
+			log.Fatalf("leader-election setup failed : %s storage is local to the manager pod. replicas require %s storage", storageBackend, mgrconfig.StoragePostgres)
+		}
+
+		switch electionConfig.Backend {
+		case mgrconfig.LeaderElectionStorage:
+			lock = leader.NewStorageLock(app.db, electionConfig.LockName)
+		case mgrconfig.LeaderElectionKubernetes, "":
+			kubeDeployment, ok := app.deployment.(kube_deployment.Deployment)
+			if !ok {
+				log.Fatalf("leader-election setup failed : %s backend requires kubernetes deployment", mgrconfig.LeaderElectionKubernetes)
+			}
+			lock = kubeDeployment.LeaderLock(electionConfig.LockName)
+		default:
+			log.Fatalf("leader-election setup failed : unknown backend %s", electionConfig.Backend)
+		}
+
+	}
+
+	//pod name is the hostname. suffix keeps restarted replicas apart
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = app.config.AppID
+	}
+	identity := hostname + "-" + common.UUIDGen()[:8]
+
+	app.elector = leader.NewElector(identity, lock, leader.Config{
+		LeaseDuration: time.Second * time.Duration(electionConfig.LeaseDuration),
+		RenewDeadline: time.Second * time.Duration(electionConfig.RenewDeadline),
+		RetryPeriod:   time.Second * time.Duration(electionConfig.RetryPeriod),
+	})
+
+	app.elector.Run(
+		app.gc.Run,
+		app.loggerUtil.RunCompactor,
+		app.compactJobs,
+		app.reconcileFunctions,
+	)
+
+}
+
+//reconcileFunctions deploy all functions. run each time this replica becomes the leader
+func (app *App) reconcileFunctions(stop <-chan struct{}) {
 
 	appConfig := app.config
 	db := app.db
@@ -432,6 +643,7 @@ func (app *App) setupFunctions() {
 
 		_, err := function_util.FunctionDeploy(
 			appConfig,
+			db,
 			deployment,
 			messenger,
 			function)
@@ -443,12 +655,12 @@ func (app *App) setupFunctions() {
 
 	})
 	if err != nil {
-		log.Fatalf("unable to setup functions %v\n", err)
+		log.Printf("unable to setup functions %v\n", err)
 	}
 }
 
 //GetDB get app db connection
-func (app *App) GetDB() *bolt.DB {
+func (app *App) GetDB() storage.Store {
 	return app.db
 }
 
@@ -459,6 +671,7 @@ func (app *App) setUpHTTPHandlers() {
 	messenger := app.messenger
 	loggerUtil := app.loggerUtil
 	deployment := app.deployment
+	builder := app.builder
 
 	httphandler.SetUpHTTPHandlers(
 		app.config,
@@ -466,7 +679,8 @@ func (app *App) setUpHTTPHandlers() {
 		db,
 		messenger,
 		loggerUtil,
-		deployment)
+		deployment,
+		builder)
 
 	address := app.config.ServerConfig.Host + ":" + common.IntToStr(app.config.ServerConfig.Port)
 
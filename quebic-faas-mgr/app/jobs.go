@@ -0,0 +1,95 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"log"
+	"os"
+	"quebic-faas/common"
+	mgrconfig "quebic-faas/quebic-faas-mgr/config"
+	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/types"
+	"time"
+)
+
+//failInterruptedJobs fail jobs which were processing when this manager went down
+func (app *App) failInterruptedJobs() {
+
+	hostname, _ := os.Hostname()
+
+	//other replicas may still be processing their jobs
+	electionEnabled := app.config.LeaderElection.Enabled
+
+	failed, err := dao.FailStaleJobs(app.db, func(job types.Job) bool {
+		return !electionEnabled || job.Manager == hostname
+	}, "job is interrupted by manager restart")
+	if err != nil {
+		log.Printf("failing interrupted jobs failed : %v", err)
+	} else if failed > 0 {
+		log.Printf("failed %v interrupted jobs", failed)
+	}
+
+}
+
+//compactJobs fail timed out jobs and remove old finished jobs periodically until stop is closed
+func (app *App) compactJobs(stop <-chan struct{}) {
+
+	jobConfig := app.config.Jobs
+
+	//config saved before job settings
+	defaultConfig := mgrconfig.DefaultJobConfig()
+	if jobConfig.TTL <= 0 {
+		jobConfig.TTL = defaultConfig.TTL
+	}
+	if jobConfig.Timeout <= 0 {
+		jobConfig.Timeout = defaultConfig.Timeout
+	}
+	if jobConfig.CompactInterval <= 0 {
+		jobConfig.CompactInterval = defaultConfig.CompactInterval
+	}
+
+	ttl := time.Hour * time.Duration(jobConfig.TTL)
+	timeout := time.Minute * time.Duration(jobConfig.Timeout)
+	interval := time.Minute * time.Duration(jobConfig.CompactInterval)
+
+	for {
+
+		updatedBefore := time.Now().Add(-timeout).Format(common.DefaultTimeLayout)
+		failed, err := dao.FailStaleJobs(app.db, func(job types.Job) bool {
+			return job.ModifiedAt < updatedBefore
+		}, "job is not updated within "+timeout.String())
+		if err != nil {
+			log.Printf("failing timed out jobs failed : %v", err)
+		} else if failed > 0 {
+			log.Printf("failed %v timed out jobs", failed)
+		}
+
+		finishedBefore := time.Now().Add(-ttl).Format(common.DefaultTimeLayout)
+		removed, err := dao.CompactJobs(app.db, finishedBefore)
+		if err != nil {
+			log.Printf("job compaction failed : %v", err)
+		} else if removed > 0 {
+			log.Printf("job compaction removed %v jobs", removed)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+
+	}
+
+}
@@ -0,0 +1,75 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"log"
+	"quebic-faas/common"
+	_messenger "quebic-faas/messenger"
+	"quebic-faas/metrics"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+)
+
+var functionInvocations = metrics.NewCounter(
+	"quebic_function_invocations_total",
+	"function invocations reported by function containers",
+	"function", "event", "status")
+
+var functionDuration = metrics.NewHistogram(
+	"quebic_function_duration_seconds",
+	"function execution time reported by function containers",
+	nil,
+	"function", "event")
+
+func (app *App) setupMetrics() {
+
+	metrics.NewGaugeFunc(
+		"quebic_mgr_db_size_bytes",
+		"size of the manager storage",
+		func() float64 {
+			var size int64
+			app.db.View(func(tx storage.Tx) error {
+				size = tx.Size()
+				return nil
+			})
+			return float64(size)
+		})
+
+}
+
+//setupFunctionMetricsListener aggregate invocation metrics reported by functions
+func (app *App) setupFunctionMetricsListener() {
+
+	err := app.messenger.Subscribe(common.EventFunctionMetrics, func(event _messenger.BaseEvent) {
+
+		message := types.FunctionMetricsMessage{}
+		err := event.ParsePayloadAsObject(&message)
+		if err != nil {
+			log.Printf("function-metrics parse failed %v", err)
+			return
+		}
+
+		for _, invocation := range message.Invocations {
+			functionInvocations.Inc(message.Function, invocation.Event, common.IntToStr(invocation.Status))
+			functionDuration.Observe(invocation.DurationMillis/1000, message.Function, invocation.Event)
+		}
+
+	}, common.ConsumerFunctionMetrics)
+	if err != nil {
+		log.Fatalf("unable to subscribe function-metrics listen %v\n", err)
+	}
+
+}
@@ -32,10 +32,24 @@ var eventBusManagementPassword string
 
 var apigatewayServerHost string
 var apigatewayServerPort int
+var apigatewayGRPCServerPort int
 
 var mgrDashboardServerHost string
 var mgrDashboardServerPort int
 
+var tracingExporter string
+var tracingOTLPEndpoint string
+var tracingFilePath string
+
+var storageBackend string
+var storagePath string
+var storageDataSource string
+
+var artifactsPath string
+
+var leaderElection bool
+var leaderElectionBackend string
+
 var inCluster bool
 
 var deployment string
@@ -63,10 +77,24 @@ func setupFlags() {
 
 	rootCmd.PersistentFlags().StringVarP(&apigatewayServerHost, "apigateway-server-host", "", "", "apigateway-server-host")
 	rootCmd.PersistentFlags().IntVarP(&apigatewayServerPort, "apigateway-server-port", "", 0, "apigateway-server-port")
+	rootCmd.PersistentFlags().IntVarP(&apigatewayGRPCServerPort, "apigateway-grpc-server-port", "", 0, "apigateway-grpc-server-port")
 
 	rootCmd.PersistentFlags().StringVarP(&mgrDashboardServerHost, "dashboard-server-host", "", "", "dashboard-server-host")
 	rootCmd.PersistentFlags().IntVarP(&mgrDashboardServerPort, "dashboard-server-port", "", 0, "dashboard-server-port")
 
+	rootCmd.PersistentFlags().StringVarP(&tracingExporter, "tracing-exporter", "", "", "tracing-exporter otlp / file")
+	rootCmd.PersistentFlags().StringVarP(&tracingOTLPEndpoint, "tracing-otlp-endpoint", "", "", "tracing-otlp-endpoint")
+	rootCmd.PersistentFlags().StringVarP(&tracingFilePath, "tracing-file", "", "", "tracing-file")
+
+	rootCmd.PersistentFlags().StringVarP(&storageBackend, "storage-backend", "", "", "storage-backend bolt / sqlite / postgres")
+	rootCmd.PersistentFlags().StringVarP(&storagePath, "storage-path", "", "", "storage-path bolt or sqlite file")
+	rootCmd.PersistentFlags().StringVarP(&storageDataSource, "storage-datasource", "", "", "storage-datasource postgres connection string")
+
+	rootCmd.PersistentFlags().StringVarP(&artifactsPath, "artifacts-path", "", "", "artifacts-path store of uploaded function artifacts")
+
+	rootCmd.PersistentFlags().BoolVarP(&leaderElection, "leader-election", "", false, "leader-election among manager replicas")
+	rootCmd.PersistentFlags().StringVarP(&leaderElectionBackend, "leader-election-backend", "", "", "leader-election-backend kubernetes / storage")
+
 	rootCmd.PersistentFlags().BoolVarP(&inCluster, "in-cluster", "", true, "in-cluster")
 
 	rootCmd.PersistentFlags().StringVarP(&deployment, "deployment", "", "", "deployment")
@@ -133,6 +161,10 @@ func setFlagsToConfig() {
 		appConfig.APIGatewayConfig.ServerConfig.Port = apigatewayServerPort
 	}
 
+	if apigatewayGRPCServerPort != 0 {
+		appConfig.APIGatewayConfig.GRPCServerConfig.Port = apigatewayGRPCServerPort
+	}
+
 	if mgrDashboardServerHost != "" {
 		appConfig.MgrDashboardConfig.ServerConfig.Host = mgrDashboardServerHost
 	}
@@ -141,6 +173,42 @@ func setFlagsToConfig() {
 		appConfig.MgrDashboardConfig.ServerConfig.Port = mgrDashboardServerPort
 	}
 
+	if tracingExporter != "" {
+		appConfig.Tracing.Exporter = tracingExporter
+	}
+
+	if tracingOTLPEndpoint != "" {
+		appConfig.Tracing.OTLPEndpoint = tracingOTLPEndpoint
+	}
+
+	if tracingFilePath != "" {
+		appConfig.Tracing.FilePath = tracingFilePath
+	}
+
+	if storageBackend != "" {
+		appConfig.Storage.Backend = storageBackend
+	}
+
+	if storagePath != "" {
+		appConfig.Storage.Path = storagePath
+	}
+
+	if storageDataSource != "" {
+		appConfig.Storage.DataSource = storageDataSource
+	}
+
+	if artifactsPath != "" {
+		appConfig.Artifacts.Path = artifactsPath
+	}
+
+	if leaderElection {
+		appConfig.LeaderElection.Enabled = leaderElection
+	}
+
+	if leaderElectionBackend != "" {
+		appConfig.LeaderElection.Backend = leaderElectionBackend
+	}
+
 	if !inCluster {
 		appConfig.InCluster = inCluster
 	}
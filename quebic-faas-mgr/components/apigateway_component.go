@@ -22,15 +22,15 @@ import (
 	"quebic-faas/quebic-faas-mgr/config"
 	"quebic-faas/quebic-faas-mgr/dao"
 	dep "quebic-faas/quebic-faas-mgr/deployment"
+	"quebic-faas/quebic-faas-mgr/storage"
 	"quebic-faas/types"
 	"time"
 
-	bolt "github.com/coreos/bbolt"
 	uuid "github.com/satori/go.uuid"
 )
 
 //ApigatewaySetup apigateway setup
-func ApigatewaySetup(appConfig *config.AppConfig, db *bolt.DB, deployment dep.Deployment, msg messenger.Messenger) error {
+func ApigatewaySetup(appConfig *config.AppConfig, db storage.Store, deployment dep.Deployment, msg messenger.Messenger) error {
 
 	componentID := common.ComponentAPIGateway
 	log.Printf("%s : starting", componentID)
@@ -46,8 +46,14 @@ func ApigatewaySetup(appConfig *config.AppConfig, db *bolt.DB, deployment dep.De
 
 	//port details always come from config settings
 	apiGatewayPort := appConfig.APIGatewayConfig.ServerConfig.Port
+	apiGatewayGRPCPort := appConfig.APIGatewayConfig.GRPCServerConfig.Port
 
-	portConfig := getAPIGatewayPortConfig(apiGatewayPort)
+	//config saved before grpc ingress
+	if apiGatewayGRPCPort == 0 {
+		apiGatewayGRPCPort = common.ApigatewayGRPCServerPort
+	}
+
+	portConfig := getAPIGatewayPortConfig(apiGatewayPort, apiGatewayGRPCPort)
 
 	//always when starting manager, component are setup
 	accessKeyUUID, err := uuid.NewV4()
@@ -70,6 +76,11 @@ func ApigatewaySetup(appConfig *config.AppConfig, db *bolt.DB, deployment dep.De
 	envkeys[common.EnvKey_rabbitmq_management_username] = eventBusConfig.ManagementUserName
 	envkeys[common.EnvKey_rabbitmq_management_password] = eventBusConfig.ManagementPassword
 
+	//tracing
+	envkeys[common.EnvKey_tracing_exporter] = appConfig.Tracing.Exporter
+	envkeys[common.EnvKey_tracing_otlpEndpoint] = appConfig.Tracing.OTLPEndpoint
+	envkeys[common.EnvKey_tracing_filePath] = appConfig.Tracing.FilePath
+
 	deploymentSpec := dep.Spec{
 		Name:           componentID,
 		Version:        apiGatewayVersion,
@@ -105,11 +116,13 @@ func prepareAPIGatewayDeplymentName(version string) string {
 	return common.ComponentAPIGateway + "-v" + version
 }
 
-func getAPIGatewayPortConfig(apigatewayPort int) []dep.PortConfig {
+func getAPIGatewayPortConfig(apigatewayPort int, apigatewayGRPCPort int) []dep.PortConfig {
 
 	targetApigatewayPort := dep.Port(common.ApigatewayServerPort)
+	targetApigatewayGRPCPort := dep.Port(common.ApigatewayGRPCServerPort)
 
 	publishApigatewayPort := dep.Port(apigatewayPort)
+	publishApigatewayGRPCPort := dep.Port(apigatewayGRPCPort)
 
 	portConfigs := []dep.PortConfig{
 		dep.PortConfig{
@@ -117,6 +130,11 @@ func getAPIGatewayPortConfig(apigatewayPort int) []dep.PortConfig {
 			Port:       publishApigatewayPort,
 			TargetPort: targetApigatewayPort,
 		},
+		dep.PortConfig{
+			Name:       "apigateway-grpc",
+			Port:       publishApigatewayGRPCPort,
+			TargetPort: targetApigatewayGRPCPort,
+		},
 	}
 
 	return portConfigs
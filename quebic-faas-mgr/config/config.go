@@ -35,6 +35,13 @@ type AppConfig struct {
 	APIGatewayConfig   APIGatewayConfig      `json:"apiGatewayConfig"`
 	IngressConfig      IngressConfig         `json:"ingressConfig" yaml:"ingressConfig"`
 	MgrDashboardConfig MgrDashboardConfig    `json:"mgrDashboardConfig"`
+	RequestTracker     RequestTrackerConfig  `json:"requestTrackerConfig"`
+	Jobs               JobConfig             `json:"jobConfig"`
+	LogForwarders      []LogForwarderConfig  `json:"logForwarders"`
+	Tracing            config.TracingConfig  `json:"tracing"`
+	Storage            StorageConfig         `json:"storage"`
+	LeaderElection     LeaderElectionConfig  `json:"leaderElection"`
+	Artifacts          ArtifactsConfig       `json:"artifacts"`
 	InCluster          bool                  `json:"inCluster"`
 	Deployment         string                `json:"deployment"`
 }
@@ -49,6 +56,13 @@ type SavingConfig struct {
 	APIGatewayConfig   APIGatewayConfig      `json:"apiGatewayConfig" yaml:"apiGatewayConfig"`
 	IngressConfig      IngressConfig         `json:"ingressConfig" yaml:"ingressConfig"`
 	MgrDashboardConfig MgrDashboardConfig    `json:"mgrDashboardConfig" yaml:"mgrDashboardConfig"`
+	RequestTracker     RequestTrackerConfig  `json:"requestTrackerConfig" yaml:"requestTrackerConfig"`
+	Jobs               JobConfig             `json:"jobConfig" yaml:"jobConfig"`
+	LogForwarders      []LogForwarderConfig  `json:"logForwarders" yaml:"logForwarders"`
+	Tracing            config.TracingConfig  `json:"tracing" yaml:"tracing"`
+	Storage            StorageConfig         `json:"storage" yaml:"storage"`
+	LeaderElection     LeaderElectionConfig  `json:"leaderElection" yaml:"leaderElection"`
+	Artifacts          ArtifactsConfig       `json:"artifacts" yaml:"artifacts"`
 	InCluster          bool                  `json:"inCluster"`
 	Deployment         string                `json:"deployment" yaml:"deployment"`
 }
@@ -79,6 +93,10 @@ func (appConfig *AppConfig) SetDefault() {
 			Host: common.HostMachineIP,
 			Port: common.ApigatewayServerPort,
 		},
+		GRPCServerConfig: config.ServerConfig{
+			Host: common.HostMachineIP,
+			Port: common.ApigatewayGRPCServerPort,
+		},
 		Replicas: common.ComponentAPIGatewayDefaultReplicas,
 	}
 
@@ -91,7 +109,19 @@ func (appConfig *AppConfig) SetDefault() {
 
 	appConfig.IngressConfig = IngressConfig{}
 
-	appConfig.DockerConfig = DockerConfig{RegistryAddress: ""}
+	appConfig.RequestTracker = DefaultRequestTrackerConfig()
+
+	appConfig.Jobs = DefaultJobConfig()
+
+	appConfig.LeaderElection = DefaultLeaderElectionConfig()
+
+	appConfig.DockerConfig = DockerConfig{
+		RegistryAddress:     "",
+		Builder:             BuilderDocker,
+		BuilderImage:        DefaultBuilderImage,
+		BuildVolumePath:     DefaultBuildVolumePath,
+		BuildTimeoutMinutes: DefaultBuildTimeoutMinutes,
+	}
 
 	appConfig.KubernetesConfig = KubeConfig{ConfigPath: filepath.Join(homedir.HomeDir(), ".kube", "config")}
 
@@ -111,8 +141,124 @@ type AuthConfig struct {
 
 //APIGatewayConfig apigateway config
 type APIGatewayConfig struct {
-	ServerConfig config.ServerConfig `json:"serverConfig" yaml:"serverConfig"`
-	Replicas     int                 `json:"replicas" yaml:"replicas"`
+	ServerConfig     config.ServerConfig `json:"serverConfig" yaml:"serverConfig"`
+	GRPCServerConfig config.ServerConfig `json:"grpcServerConfig" yaml:"grpcServerConfig"`
+	Replicas         int                 `json:"replicas" yaml:"replicas"`
+}
+
+//RequestTrackerConfig retention of request-trackers
+type RequestTrackerConfig struct {
+	TTL             int `json:"ttl" yaml:"ttl"`                         //in hours. older trackers are removed
+	MaxTrackers     int `json:"maxTrackers" yaml:"maxTrackers"`         //oldest trackers are removed beyond this count
+	MaxLogs         int `json:"maxLogs" yaml:"maxLogs"`                 //latest logs kept per tracker
+	CompactInterval int `json:"compactInterval" yaml:"compactInterval"` //in minutes
+}
+
+//DefaultRequestTrackerConfig default retention
+func DefaultRequestTrackerConfig() RequestTrackerConfig {
+	return RequestTrackerConfig{
+		TTL:             72,
+		MaxTrackers:     100000,
+		MaxLogs:         100,
+		CompactInterval: 10,
+	}
+}
+
+//JobConfig retention of function jobs
+type JobConfig struct {
+	TTL             int `json:"ttl" yaml:"ttl"`                         //in hours. finished jobs are removed after it
+	Timeout         int `json:"timeout" yaml:"timeout"`                 //in minutes. unfinished jobs which are not updated within it are failed
+	CompactInterval int `json:"compactInterval" yaml:"compactInterval"` //in minutes
+}
+
+//DefaultJobConfig default retention
+func DefaultJobConfig() JobConfig {
+	return JobConfig{
+		TTL:             72,
+		Timeout:         60,
+		CompactInterval: 10,
+	}
+}
+
+//StorageConfig backend of the manager state
+// Backend : bolt (default), sqlite or postgres
+// Path : bolt or sqlite file
+// DataSource : postgres connection string. managers sharing the database share the state
+type StorageConfig struct {
+	Backend    string `json:"backend" yaml:"backend"`
+	Path       string `json:"path" yaml:"path"`
+	DataSource string `json:"dataSource" yaml:"dataSource"`
+}
+
+//Shared storage which several manager pods can use together. bolt and sqlite files are local to the pod
+func (storageConfig StorageConfig) Shared() bool {
+	return storageConfig.Backend == StoragePostgres
+}
+
+//LeaderElectionConfig elect a leader among manager replicas.
+//only the leader runs gc, function deploy reconciliation and schedulers. every replica serves the api
+// Backend : kubernetes (lock on a configmap) or storage (lock in the shared storage)
+type LeaderElectionConfig struct {
+	Enabled       bool   `json:"enabled" yaml:"enabled"`
+	Backend       string `json:"backend" yaml:"backend"`
+	LockName      string `json:"lockName" yaml:"lockName"`
+	LeaseDuration int    `json:"leaseDuration" yaml:"leaseDuration"` //in seconds
+	RenewDeadline int    `json:"renewDeadline" yaml:"renewDeadline"` //in seconds
+	RetryPeriod   int    `json:"retryPeriod" yaml:"retryPeriod"`     //in seconds
+}
+
+//DefaultLeaderElectionConfig disabled. single manager is always the leader
+func DefaultLeaderElectionConfig() LeaderElectionConfig {
+	return LeaderElectionConfig{
+		Enabled:       false,
+		Backend:       LeaderElectionKubernetes,
+		LockName:      "quebic-faas-mgr-leader",
+		LeaseDuration: 15,
+		RenewDeadline: 10,
+		RetryPeriod:   2,
+	}
+}
+
+//ArtifactsConfig store of uploaded function artifacts. artifacts are kept by their sha256
+// Path : empty => ~/.quebic-faas-artifacts. replicated managers share it through a volume
+type ArtifactsConfig struct {
+	Path string `json:"path" yaml:"path"`
+}
+
+//LogForwarderConfig external sink which receives request-tracker logs
+// Type : file, syslog or http. only the config of the type is used
+// BufferSize : logs kept in memory while the sink is slow. logs beyond this are dropped
+type LogForwarderConfig struct {
+	Name       string                   `json:"name" yaml:"name"`
+	Type       string                   `json:"type" yaml:"type"`
+	BufferSize int                      `json:"bufferSize" yaml:"bufferSize"`
+	File       LogFileForwarderConfig   `json:"file" yaml:"file"`
+	Syslog     LogSyslogForwarderConfig `json:"syslog" yaml:"syslog"`
+	HTTP       LogHTTPForwarderConfig   `json:"http" yaml:"http"`
+}
+
+//LogFileForwarderConfig write logs as json lines. file is rotated when it reaches MaxSize
+type LogFileForwarderConfig struct {
+	Path       string `json:"path" yaml:"path"`
+	MaxSize    int    `json:"maxSize" yaml:"maxSize"`       //in MB
+	MaxBackups int    `json:"maxBackups" yaml:"maxBackups"` //rotated files kept
+}
+
+//LogSyslogForwarderConfig write logs into syslog. empty network and address use local syslog
+type LogSyslogForwarderConfig struct {
+	Network string `json:"network" yaml:"network"` //udp or tcp
+	Address string `json:"address" yaml:"address"`
+	Tag     string `json:"tag" yaml:"tag"`
+}
+
+//LogHTTPForwarderConfig post logs in batches to Loki push api or Elasticsearch bulk api
+type LogHTTPForwarderConfig struct {
+	URL           string            `json:"url" yaml:"url"`
+	Format        string            `json:"format" yaml:"format"` //loki or elasticsearch
+	Index         string            `json:"index" yaml:"index"`   //elasticsearch index
+	Headers       map[string]string `json:"headers" yaml:"headers"`
+	BatchSize     int               `json:"batchSize" yaml:"batchSize"`
+	FlushInterval int               `json:"flushInterval" yaml:"flushInterval"` //in seconds
 }
 
 //IngressConfig config for ingress controller
@@ -122,8 +268,24 @@ type IngressConfig struct {
 }
 
 //DockerConfig docker confog
+// Builder : docker (default) or kaniko. kaniko builds images inside the cluster as kubernetes jobs
+// BuildVolumeClaim : persistent volume claim shared by manager and build jobs. build contexts are handed over through it
+// BuildVolumePath : path which BuildVolumeClaim is mounted in manager and build jobs
+// RegistrySecret : docker config secret of RegistryAddress. build jobs use it to push images
+// CredentialsKey : key which seals registry passwords in the storage. replicated managers share it.
+// it is never saved into the config file. taken from dockerConfig_credentialsKey env variable
+// VerifyImageDigest : function deployments are pinned to the image digest which is recorded when the image is built.
+// image which is changed in the registry after that is not rolled out
 type DockerConfig struct {
-	RegistryAddress string `json:"registryAddress" yaml:"registryAddress"`
+	RegistryAddress     string `json:"registryAddress" yaml:"registryAddress"`
+	Builder             string `json:"builder" yaml:"builder"`
+	BuilderImage        string `json:"builderImage" yaml:"builderImage"`
+	BuildVolumeClaim    string `json:"buildVolumeClaim" yaml:"buildVolumeClaim"`
+	BuildVolumePath     string `json:"buildVolumePath" yaml:"buildVolumePath"`
+	RegistrySecret      string `json:"registrySecret" yaml:"registrySecret"`
+	BuildTimeoutMinutes int    `json:"buildTimeoutMinutes" yaml:"buildTimeoutMinutes"`
+	CredentialsKey      string `json:"-" yaml:"-"`
+	VerifyImageDigest   bool   `json:"verifyImageDigest" yaml:"verifyImageDigest"`
 }
 
 //KubeConfig kube confog
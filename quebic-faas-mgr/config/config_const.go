@@ -19,3 +19,48 @@ const ConfigFile = "manager-config.yml"
 
 const Deployment_Docker = "docker"
 const Deployment_Kubernetes = "kubernetes"
+
+//LogForwarderFile forward logs into json lines file
+const LogForwarderFile = "file"
+
+//LogForwarderSyslog forward logs into syslog
+const LogForwarderSyslog = "syslog"
+
+//LogForwarderHTTP forward logs into http bulk endpoint
+const LogForwarderHTTP = "http"
+
+//LogFormatLoki loki push api
+const LogFormatLoki = "loki"
+
+//LogFormatElasticsearch elasticsearch bulk api
+const LogFormatElasticsearch = "elasticsearch"
+
+//StorageBolt local bolt file
+const StorageBolt = "bolt"
+
+//StorageSQLite embedded sqlite file
+const StorageSQLite = "sqlite"
+
+//StoragePostgres postgres compatible database
+const StoragePostgres = "postgres"
+
+//LeaderElectionKubernetes leader lock on a configmap
+const LeaderElectionKubernetes = "kubernetes"
+
+//LeaderElectionStorage leader lock in the shared storage
+const LeaderElectionStorage = "storage"
+
+//BuilderDocker builds images with the docker daemon of the host
+const BuilderDocker = "docker"
+
+//BuilderKaniko builds images inside the cluster as kubernetes jobs. no docker daemon is required
+const BuilderKaniko = "kaniko"
+
+//DefaultBuilderImage daemonless builder image
+const DefaultBuilderImage = "gcr.io/kaniko-project/executor:latest"
+
+//DefaultBuildVolumePath path which build volume is mounted in manager and build jobs
+const DefaultBuildVolumePath = "/quebic-faas-builds"
+
+//DefaultBuildTimeoutMinutes build job is stopped after this
+const DefaultBuildTimeoutMinutes = 30
@@ -0,0 +1,367 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const auditBucket = "Audit"
+
+const redactedValue = `"******"`
+
+//audit actions
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+//fields which are changed on every write. not recorded as changes
+var auditIgnoredFields = map[string]bool{
+	"revision":   true,
+	"modifiedAt": true,
+}
+
+//AuditFilter empty fields are not filtered
+type AuditFilter struct {
+	Entity string
+	ID     string
+	Actor  string
+	From   time.Time
+	To     time.Time
+}
+
+//GetAuditRecords newest first
+// cursor : NextCursor of the previous page. empty for first page
+func GetAuditRecords(db storage.Store, filter AuditFilter, cursor string, limit int) (types.AuditPage, error) {
+
+	page := types.AuditPage{Records: []types.AuditRecord{}}
+
+	err := db.View(func(tx storage.Tx) error {
+
+		bucket := tx.Bucket([]byte(auditBucket))
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+
+		var k, v []byte
+
+		if cursor != "" {
+			k, v = seekBefore(c, []byte(cursor))
+		} else if !filter.To.IsZero() {
+			k, v = seekBefore(c, []byte(auditKeyPrefix(filter.To.UnixNano()+1)))
+		} else {
+			k, v = c.Last()
+		}
+
+		for ; k != nil; k, v = c.Prev() {
+
+			if !filter.From.IsZero() && auditKeyTime(k) < filter.From.UnixNano() {
+				break
+			}
+
+			record := types.AuditRecord{}
+			err := json.Unmarshal(v, &record)
+			if err != nil {
+				return fmt.Errorf("unable to parse audit record %s, error : %v", k, err)
+			}
+
+			if !matchAuditFilter(record, filter) {
+				continue
+			}
+
+			page.Records = append(page.Records, record)
+
+			if limit > 0 && len(page.Records) == limit {
+				page.NextCursor = string(k)
+				break
+			}
+
+		}
+
+		return nil
+
+	})
+
+	return page, err
+
+}
+
+func addAuditRecord(tx storage.Tx, actor string, action string, entity types.Entity, revision int64, before []byte, after []byte) error {
+
+	bucket, err := tx.CreateBucketIfNotExists([]byte(auditBucket))
+	if err != nil {
+		return fmt.Errorf("unable to create bucket for %s, error : %v", auditBucket, err)
+	}
+
+	now := time.Now()
+	entityName := auditEntityName(entity)
+	key := fmt.Sprintf("%s|%s|%s", auditKeyPrefix(now.UnixNano()), entityName, entity.GetID())
+
+	if actor == "" {
+		actor = ActorSystem
+	}
+
+	record := types.AuditRecord{
+		ID:       key,
+		Time:     now.Format(time.RFC3339Nano),
+		Actor:    actor,
+		Action:   action,
+		Entity:   entityName,
+		EntityID: entity.GetID(),
+		Revision: revision,
+	}
+
+	switch action {
+	case AuditActionCreate:
+		record.After, err = redactJSON(after)
+	case AuditActionDelete:
+		record.Before, err = redactJSON(before)
+	default:
+		record.Changes, err = diffJSON(before, after)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to prepare audit record for %s, error : %v", key, err)
+	}
+
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed json parse, error : %v", err)
+	}
+
+	return bucket.Put([]byte(key), recordJSON)
+
+}
+
+func matchAuditFilter(record types.AuditRecord, filter AuditFilter) bool {
+
+	if filter.Entity != "" && record.Entity != filter.Entity {
+		return false
+	}
+
+	if filter.ID != "" && record.EntityID != filter.ID {
+		return false
+	}
+
+	if filter.Actor != "" && record.Actor != filter.Actor {
+		return false
+	}
+
+	return true
+
+}
+
+//seekBefore position cursor at the last key which is less than key
+func seekBefore(c storage.Cursor, key []byte) ([]byte, []byte) {
+
+	k, _ := c.Seek(key)
+	if k == nil {
+		return c.Last()
+	}
+
+	return c.Prev()
+
+}
+
+func auditKeyPrefix(unixNano int64) string {
+	return fmt.Sprintf("%020d", unixNano)
+}
+
+func auditKeyTime(key []byte) int64 {
+	t, _ := strconv.ParseInt(strings.SplitN(string(key), "|", 2)[0], 10, 64)
+	return t
+}
+
+//auditEntityName entity name which is used in backups. eg: functions
+func auditEntityName(entity types.Entity) string {
+
+	bucketName := entityBucketName(entity)
+
+	for name, entityType := range backupEntityTypes {
+		if entityBucketName(entityType) == bucketName {
+			return name
+		}
+	}
+
+	return bucketName
+
+}
+
+//diffJSON changed fields between two json objects. nested objects are compared field by field
+func diffJSON(before []byte, after []byte) ([]types.AuditChange, error) {
+
+	var beforeObj, afterObj interface{}
+
+	if err := json.Unmarshal(before, &beforeObj); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(after, &afterObj); err != nil {
+		return nil, err
+	}
+
+	var changes []types.AuditChange
+	err := diffValue("", beforeObj, afterObj, &changes)
+
+	return changes, err
+
+}
+
+func diffValue(path string, before interface{}, after interface{}, changes *[]types.AuditChange) error {
+
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+
+	if beforeIsMap && afterIsMap {
+
+		var fields []string
+		for field := range beforeMap {
+			fields = append(fields, field)
+		}
+		for field := range afterMap {
+			if _, ok := beforeMap[field]; !ok {
+				fields = append(fields, field)
+			}
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+
+			if path == "" && auditIgnoredFields[field] {
+				continue
+			}
+
+			err := diffValue(joinAuditPath(path, field), beforeMap[field], afterMap[field], changes)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	}
+
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	if string(beforeJSON) == string(afterJSON) {
+		return nil
+	}
+
+	//secret values are not recorded. only the fact that they have been changed
+	change := types.AuditChange{Field: path}
+
+	change.Before, err = marshalAuditValue(path, before)
+	if err != nil {
+		return err
+	}
+
+	change.After, err = marshalAuditValue(path, after)
+	if err != nil {
+		return err
+	}
+
+	*changes = append(*changes, change)
+
+	return nil
+
+}
+
+func marshalAuditValue(path string, v interface{}) (json.RawMessage, error) {
+
+	if v == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(redactValue(path, v))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(b), nil
+
+}
+
+//redactJSON replace secret fields
+func redactJSON(data []byte) (json.RawMessage, error) {
+
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(redactValue("", obj))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(b), nil
+
+}
+
+func redactValue(path string, v interface{}) interface{} {
+
+	if isSecretField(path) {
+		return json.RawMessage(redactedValue)
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{})
+		for field, fieldValue := range val {
+			redacted[field] = redactValue(joinAuditPath(path, field), fieldValue)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(val))
+		for i, item := range val {
+			redacted[i] = redactValue(path, item)
+		}
+		return redacted
+	}
+
+	return v
+
+}
+
+func isSecretField(path string) bool {
+	field := strings.ToLower(path[strings.LastIndex(path, ".")+1:])
+	return field != "" && (strings.Contains(field, "password") || strings.Contains(field, "secret"))
+}
+
+func joinAuditPath(path string, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
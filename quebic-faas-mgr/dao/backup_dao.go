@@ -0,0 +1,327 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package dao
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+	"sort"
+)
+
+//BackupVersion format version of the backup stream
+const BackupVersion = 1
+
+//BackupEntities entities which can be backed up, in restore order
+var BackupEntities = []string{
+	"users",
+	"events",
+	"runtimes",
+	"functions",
+	"routes",
+	"grpc-services",
+	"components",
+	"request-trackers",
+}
+
+var backupEntityTypes = map[string]types.Entity{
+	"users":            &types.User{},
+	"events":           &types.Event{},
+	"functions":        &types.Function{},
+	"routes":           &types.Resource{},
+	"grpc-services":    &types.GRPCService{},
+	"runtimes":         &types.RuntimeSpec{},
+	"components":       &types.ManagerComponent{},
+	"request-trackers": &types.RequestTracker{},
+}
+
+//ValidateBackupEntities empty entities => all entities
+func ValidateBackupEntities(entities []string) ([]string, error) {
+
+	if len(entities) == 0 {
+		return BackupEntities, nil
+	}
+
+	for _, entity := range entities {
+		if _, ok := backupEntityTypes[entity]; !ok {
+			return nil, fmt.Errorf("unknown entity %s. entities : %v", entity, BackupEntities)
+		}
+	}
+
+	return entities, nil
+
+}
+
+//WriteBackup write consistent snapshot of the entities as json stream. header is followed by records
+func WriteBackup(db storage.Store, entities []string, w io.Writer) error {
+
+	entities, err := ValidateBackupEntities(entities)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+
+	//single read transaction. all buckets are read at the same point
+	return db.View(func(tx storage.Tx) error {
+
+		err := encoder.Encode(types.BackupHeader{
+			Version:   BackupVersion,
+			CreatedAt: common.CurrentTime(),
+			Entities:  entities,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, entity := range entities {
+
+			bucket := tx.Bucket([]byte(entityBucketName(backupEntityTypes[entity])))
+			if bucket == nil {
+				continue
+			}
+
+			err := bucket.ForEach(func(k, v []byte) error {
+				return encoder.Encode(types.BackupRecord{Entity: entity, Key: string(k), Value: v})
+			})
+			if err != nil {
+				return fmt.Errorf("unable to backup %s, error : %v", entity, err)
+			}
+
+		}
+
+		return nil
+
+	})
+
+}
+
+//RestoreBackup replace the entities with records of the backup. entities not in the list are skipped
+//dryRun only reports the differences
+func RestoreBackup(db storage.Store, entities []string, r io.Reader, dryRun bool) (*types.RestoreReport, error) {
+
+	entities, err := ValidateBackupEntities(entities)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := readBackup(r, entities)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &types.RestoreReport{DryRun: dryRun}
+
+	err = db.View(func(tx storage.Tx) error {
+		for _, entity := range entities {
+			bucket := tx.Bucket([]byte(entityBucketName(backupEntityTypes[entity])))
+			report.Entities = append(report.Entities, prepareRestoreDiff(entity, bucket, records[entity]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	err = db.Update(func(tx storage.Tx) error {
+
+		for _, entity := range entities {
+
+			bucketName := []byte(entityBucketName(backupEntityTypes[entity]))
+
+			if tx.Bucket(bucketName) != nil {
+				err := tx.DeleteBucket(bucketName)
+				if err != nil {
+					return fmt.Errorf("unable to clear %s, error : %v", entity, err)
+				}
+			}
+
+			bucket, err := tx.CreateBucket(bucketName)
+			if err != nil {
+				return fmt.Errorf("unable to create bucket for %s, error : %v", entity, err)
+			}
+
+			for key, value := range records[entity] {
+				err := bucket.Put([]byte(key), value)
+				if err != nil {
+					return fmt.Errorf("unable to restore %s %s, error : %v", entity, key, err)
+				}
+			}
+
+		}
+
+		//indexes are rebuilt from restored trackers
+		if containsEntity(entities, "request-trackers") {
+			for _, index := range []string{requestTrackerIndexBucket, requestTrackerLogIndexBucket} {
+				if tx.Bucket([]byte(index)) != nil {
+					err := tx.DeleteBucket([]byte(index))
+					if err != nil {
+						return fmt.Errorf("unable to clear %s, error : %v", index, err)
+					}
+				}
+			}
+		}
+
+		return nil
+
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if containsEntity(entities, "request-trackers") {
+
+		err = RebuildRequestTrackerIndex(db)
+		if err != nil {
+			return nil, err
+		}
+
+		err = RebuildRequestTrackerLogIndex(db)
+		if err != nil {
+			return nil, err
+		}
+
+	}
+
+	return report, nil
+
+}
+
+//readBackup entity => key => value
+func readBackup(r io.Reader, entities []string) (map[string]map[string][]byte, error) {
+
+	decoder := json.NewDecoder(r)
+
+	header := types.BackupHeader{}
+	err := decoder.Decode(&header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup header : %v", err)
+	}
+
+	if header.Version != BackupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", header.Version)
+	}
+
+	for _, entity := range entities {
+		if !containsEntity(header.Entities, entity) {
+			return nil, fmt.Errorf("backup does not contain %s", entity)
+		}
+	}
+
+	records := make(map[string]map[string][]byte)
+	for _, entity := range entities {
+		records[entity] = make(map[string][]byte)
+	}
+
+	for {
+
+		record := types.BackupRecord{}
+		err := decoder.Decode(&record)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup record : %v", err)
+		}
+
+		entityRecords, ok := records[record.Entity]
+		if !ok {
+			continue
+		}
+
+		entityRecords[record.Key] = []byte(record.Value)
+
+	}
+
+	return records, nil
+
+}
+
+func prepareRestoreDiff(entity string, bucket storage.Bucket, records map[string][]byte) types.RestoreEntityDiff {
+
+	diff := types.RestoreEntityDiff{
+		Entity:  entity,
+		Added:   []string{},
+		Updated: []string{},
+		Removed: []string{},
+	}
+
+	for key, value := range records {
+
+		var saved []byte
+		if bucket != nil {
+			saved = bucket.Get([]byte(key))
+		}
+
+		switch {
+		case saved == nil:
+			diff.Added = append(diff.Added, key)
+		case jsonEqual(saved, value):
+			diff.Unchanged++
+		default:
+			diff.Updated = append(diff.Updated, key)
+		}
+
+	}
+
+	if bucket != nil {
+		bucket.ForEach(func(k, v []byte) error {
+			if _, ok := records[string(k)]; !ok {
+				diff.Removed = append(diff.Removed, string(k))
+			}
+			return nil
+		})
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Updated)
+	sort.Strings(diff.Removed)
+
+	return diff
+
+}
+
+func jsonEqual(a []byte, b []byte) bool {
+
+	var compactA, compactB bytes.Buffer
+	if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+		return bytes.Equal(a, b)
+	}
+
+	return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+
+}
+
+//entityBucketName entities are saved under bucket of their type name
+func entityBucketName(entity types.Entity) string {
+	return entity.GetReflectObject().Elem().Type().Name()
+}
+
+func containsEntity(entities []string, entity string) bool {
+	for _, e := range entities {
+		if e == entity {
+			return true
+		}
+	}
+	return false
+}
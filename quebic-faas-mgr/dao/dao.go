@@ -17,146 +17,247 @@ package dao
 import (
 	"encoding/json"
 	"fmt"
+	"quebic-faas/quebic-faas-mgr/storage"
 	"quebic-faas/types"
-
-	bolt "github.com/coreos/bbolt"
+	"reflect"
 )
 
 //GetAll entity
-func GetAll(db *bolt.DB, entity types.Entity, fn func(k, v []byte) error) error {
+func GetAll(db storage.Store, entity types.Entity, fn func(k, v []byte) error) error {
 	return getAll(db, entity, fn)
 }
 
 //GetByID get by ID
-func GetByID(db *bolt.DB, entity types.Entity, fn func(v []byte) error) error {
+func GetByID(db storage.Store, entity types.Entity, fn func(v []byte) error) error {
 	return getByID(db, entity, fn)
 }
 
+//ActorSystem actor of the changes which are not made by a user
+const ActorSystem = "system"
+
+//RevisionConflictError entity has been modified after the revision which caller has
+type RevisionConflictError struct {
+	Entity   string
+	ID       string
+	Revision int64
+	Current  int64
+}
+
+func (e RevisionConflictError) Error() string {
+	return fmt.Sprintf("%s %s has been modified. revision %d, current revision %d", e.Entity, e.ID, e.Revision, e.Current)
+}
+
+//IsRevisionConflict check error is a RevisionConflictError
+func IsRevisionConflict(err error) bool {
+	_, ok := err.(RevisionConflictError)
+	return ok
+}
+
 //Add entity.
 // Check before save.
 // If already exists a object under id. throw error
-func Add(db *bolt.DB, entity types.Entity) error {
+func Add(db storage.Store, entity types.Entity, actor string) error {
 
-	//check allready exists
-	err := getByID(db, entity, func(savedObj []byte) error {
+	entity.SetModifiedAt()
 
+	return write(db, entity, func(savedObj []byte) error {
+
+		//check allready exists
 		if savedObj != nil {
 			return fmt.Errorf("object already exists")
 		}
 
-		return nil
-	})
+		entity.SetRevision(1)
 
-	if err != nil {
-		return err
-	}
+		return nil
 
-	entity.SetModifiedAt()
+	}, actor, AuditActionCreate)
 
-	return Save(db, entity)
 }
 
 //Update entity
 // Check before save.
 // If unable to found a object under id. Throw object not found error
-func Update(db *bolt.DB, entity types.Entity) error {
+// If entity has a revision and it is not the saved revision. Throw RevisionConflictError
+// Zero revision is not checked
+func Update(db storage.Store, entity types.Entity, actor string) error {
+
+	entity.SetModifiedAt()
 
-	//check for id
-	err := getByID(db, entity, func(savedObj []byte) error {
+	return write(db, entity, func(savedObj []byte) error {
 
+		//check for id
 		if savedObj == nil {
 			return fmt.Errorf("unable to found object")
 		}
 
-		return nil
-	})
+		current := savedRevision(savedObj)
 
-	if err != nil {
-		return err
-	}
+		if entity.GetRevision() != 0 && entity.GetRevision() != current {
+			return RevisionConflictError{
+				Entity:   entityBucketName(entity),
+				ID:       entity.GetID(),
+				Revision: entity.GetRevision(),
+				Current:  current,
+			}
+		}
 
-	entity.SetModifiedAt()
+		entity.SetRevision(current + 1)
+
+		return nil
+
+	}, actor, AuditActionUpdate)
 
-	return Save(db, entity)
 }
 
 //Save entity
 // If there is no any entity under id add new.
 // Otherwise save new entity under previous entity
-func Save(db *bolt.DB, entity types.Entity) error {
+// Save keeps the saved revision and it is not audited. used for the state changes made by manager
+func Save(db storage.Store, entity types.Entity) error {
 
-	objVal := entity.GetReflectObject().Elem()
-	typeName := objVal.Type().Name()
+	return write(db, entity, func(savedObj []byte) error {
+
+		if savedObj != nil {
+			entity.SetRevision(savedRevision(savedObj))
+		}
+
+		return nil
+
+	}, "", "")
+
+}
+
+//modify change the saved entity in a single transaction
+// entity is reloaded from the saved entity, then change sets the fields which are modified.
+// concurrent updates are not reverted. keeps the saved revision and it is not audited
+func modify(db storage.Store, entity types.Entity, change func()) error {
+
+	return write(db, entity, func(savedObj []byte) error {
+
+		if savedObj == nil {
+			return fmt.Errorf("unable to found %s %s", entityBucketName(entity), entity.GetID())
+		}
+
+		//fields which are not in the saved entity should not be kept from the caller's copy
+		objVal := entity.GetReflectObject().Elem()
+		objVal.Set(reflect.Zero(objVal.Type()))
+
+		err := json.Unmarshal(savedObj, entity)
+		if err != nil {
+			return fmt.Errorf("failed json parse, error : %v", err)
+		}
+
+		change()
+
+		return nil
+
+	}, "", "")
+
+}
+
+// Delete entity
+func Delete(db storage.Store, entity types.Entity, actor string) error {
+
+	typeName := entityBucketName(entity)
 	id := entity.GetID()
 
 	typeNameInBytes := []byte(typeName)
 	idInBytes := []byte(id)
 
-	entityJSON, err := json.Marshal(entity)
-	if err != nil {
-		return fmt.Errorf("failed json parse, error : %v", err)
-	}
-
-	//log.Printf("saving %s ,id %s, data %s\n", typeName, id, entityJSON)
-
-	return db.Update(func(tx *bolt.Tx) error {
+	return db.Update(func(tx storage.Tx) error {
 
 		bucket, err := tx.CreateBucketIfNotExists(typeNameInBytes)
 		if err != nil {
 			return fmt.Errorf("unable to create bucket for %s, error : %v", typeName, err)
 		}
 
-		err = bucket.Put(idInBytes, entityJSON)
+		savedObj := copyBytes(bucket.Get(idInBytes))
+
+		err = bucket.Delete(idInBytes)
 		if err != nil {
-			return fmt.Errorf("unable to put data for %s, error : %v", typeName, err)
+			return fmt.Errorf("unable to delete for %s, error : %v", typeName, err)
 		}
 
-		//log.Printf("saved %s ,id %s\n", typeName, id)
+		if savedObj == nil {
+			return nil
+		}
 
-		return nil
+		return addAuditRecord(tx, actor, AuditActionDelete, entity, savedRevision(savedObj), savedObj, nil)
 	})
 
 }
 
-// Delete entity
-func Delete(db *bolt.DB, entity types.Entity) error {
+//write entity in a single transaction
+// check : called with saved entity. nil if there is no saved entity
+// action : audit action. empty action is not audited
+func write(db storage.Store, entity types.Entity, check func(savedObj []byte) error, actor string, action string) error {
 
-	objVal := entity.GetReflectObject().Elem()
-	typeName := objVal.Type().Name()
+	typeName := entityBucketName(entity)
 	id := entity.GetID()
 
 	typeNameInBytes := []byte(typeName)
 	idInBytes := []byte(id)
 
-	//log.Printf("saving %s ,id %s, data %s\n", typeName, id, entityJSON)
-
-	return db.Update(func(tx *bolt.Tx) error {
+	return db.Update(func(tx storage.Tx) error {
 
 		bucket, err := tx.CreateBucketIfNotExists(typeNameInBytes)
 		if err != nil {
 			return fmt.Errorf("unable to create bucket for %s, error : %v", typeName, err)
 		}
 
-		err = bucket.Delete(idInBytes)
+		savedObj := copyBytes(bucket.Get(idInBytes))
+
+		err = check(savedObj)
 		if err != nil {
-			return fmt.Errorf("unable to delete for %s, error : %v", typeName, err)
+			return err
 		}
 
-		//log.Printf("saved %s ,id %s\n", typeName, id)
+		entityJSON, err := json.Marshal(entity)
+		if err != nil {
+			return fmt.Errorf("failed json parse, error : %v", err)
+		}
 
-		return nil
+		err = bucket.Put(idInBytes, entityJSON)
+		if err != nil {
+			return fmt.Errorf("unable to put data for %s, error : %v", typeName, err)
+		}
+
+		if action == "" {
+			return nil
+		}
+
+		return addAuditRecord(tx, actor, action, entity, entity.GetRevision(), savedObj, entityJSON)
 	})
 
 }
 
-func getAll(db *bolt.DB, entity types.Entity, fn func(k, v []byte) error) error {
+func savedRevision(savedObj []byte) int64 {
+	saved := struct {
+		Revision int64 `json:"revision"`
+	}{}
+	json.Unmarshal(savedObj, &saved)
+	return saved.Revision
+}
+
+//copyBytes values returned by bucket are only valid until the transaction modifies the bucket
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
+
+func getAll(db storage.Store, entity types.Entity, fn func(k, v []byte) error) error {
 
 	objVal := entity.GetReflectObject().Elem()
 	typeName := objVal.Type().Name()
 
 	typeNameInBytes := []byte(typeName)
 
-	return db.View(func(tx *bolt.Tx) error {
+	return db.View(func(tx storage.Tx) error {
 
 		bucket := tx.Bucket([]byte(typeNameInBytes))
 
@@ -170,7 +271,7 @@ func getAll(db *bolt.DB, entity types.Entity, fn func(k, v []byte) error) error
 
 }
 
-func getByID(db *bolt.DB, entity types.Entity, fn func(v []byte) error) error {
+func getByID(db storage.Store, entity types.Entity, fn func(v []byte) error) error {
 
 	objVal := entity.GetReflectObject().Elem()
 	typeName := objVal.Type().Name()
@@ -179,7 +280,7 @@ func getByID(db *bolt.DB, entity types.Entity, fn func(v []byte) error) error {
 	typeNameInBytes := []byte(typeName)
 	idInBytes := []byte(id)
 
-	return db.View(func(tx *bolt.Tx) error {
+	return db.View(func(tx storage.Tx) error {
 
 		bucket := tx.Bucket([]byte(typeNameInBytes))
 
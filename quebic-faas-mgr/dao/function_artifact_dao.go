@@ -0,0 +1,97 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package dao
+
+import (
+	"encoding/json"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+)
+
+//SaveFunctionArtifact record artifact of the function version. artifact of a re-uploaded version is replaced
+func SaveFunctionArtifact(db storage.Store, artifact *types.FunctionArtifact, actor string) error {
+
+	artifact.ID = types.GetFunctionArtifactID(artifact.Function, artifact.Version)
+
+	saved, err := GetFunctionArtifact(db, artifact.Function, artifact.Version)
+	if err != nil {
+		return err
+	}
+
+	if saved == nil {
+		return Add(db, artifact, actor)
+	}
+
+	artifact.Revision = 0
+
+	return Update(db, artifact, actor)
+
+}
+
+//GetFunctionArtifact get artifact of the function version. nil when there is no artifact
+func GetFunctionArtifact(db storage.Store, function string, version string) (*types.FunctionArtifact, error) {
+
+	var artifact *types.FunctionArtifact
+
+	err := getByID(db, &types.FunctionArtifact{ID: types.GetFunctionArtifactID(function, version)}, func(savedObj []byte) error {
+
+		if savedObj == nil {
+			return nil
+		}
+
+		artifact = &types.FunctionArtifact{}
+		return json.Unmarshal(savedObj, artifact)
+
+	})
+
+	return artifact, err
+
+}
+
+//GetFunctionArtifacts get artifacts of all versions of the function
+func GetFunctionArtifacts(db storage.Store, function string) ([]types.FunctionArtifact, error) {
+
+	var artifacts []types.FunctionArtifact
+
+	err := getAll(db, &types.FunctionArtifact{}, func(k, v []byte) error {
+
+		artifact := types.FunctionArtifact{}
+		json.Unmarshal(v, &artifact)
+
+		if artifact.Function == function {
+			artifacts = append(artifacts, artifact)
+		}
+
+		return nil
+	})
+
+	return artifacts, err
+
+}
+
+//SetFunctionArtifactImage set image which is built from the artifact of the function version
+func SetFunctionArtifactImage(db storage.Store, function *types.Function, image string, imageDigest string) error {
+
+	artifact, err := GetFunctionArtifact(db, function.GetID(), function.Version)
+	if err != nil || artifact == nil {
+		return err
+	}
+
+	return modify(db, artifact, func() {
+		artifact.Image = image
+		artifact.ImageDigest = imageDigest
+	})
+
+}
@@ -15,57 +15,57 @@
 package dao
 
 import (
-	"encoding/json"
-	"fmt"
+	"quebic-faas/quebic-faas-mgr/storage"
 	"quebic-faas/types"
 	"time"
-
-	bolt "github.com/coreos/bbolt"
 )
 
 //AddFunctionDockerImageID set DockerImageID
-func AddFunctionDockerImageID(db *bolt.DB, function *types.Function, dockerImageID string) error {
-
-	err := getByID(db, function, func(savedObj []byte) error {
-
-		if savedObj == nil {
-			return fmt.Errorf("unable to found function")
-		}
+func AddFunctionDockerImageID(db storage.Store, function *types.Function, dockerImageID string) error {
+	return modify(db, function, func() {
+		function.DockerImageID = dockerImageID
+	})
+}
 
-		json.Unmarshal(savedObj, function)
+//SetFunctionImageDigest set digest of the function image
+func SetFunctionImageDigest(db storage.Store, function *types.Function, imageDigest string) error {
 
-		return nil
+	saved := &types.Function{Name: function.Name}
+	err := modify(db, saved, func() {
+		saved.ImageDigest = imageDigest
 	})
-
 	if err != nil {
 		return err
 	}
 
-	function.DockerImageID = dockerImageID
-	return Save(db, function)
+	function.ImageDigest = imageDigest
+	return nil
 }
 
-//AddFunctionLog add function log
-func AddFunctionLog(db *bolt.DB, function *types.Function, log types.EntityLog, status string) error {
+//SetFunctionSource set artifact and git commit of the fetched source of the function
+func SetFunctionSource(db storage.Store, function *types.Function) error {
 
-	log.Time = time.Now().String()
-
-	err := getByID(db, function, func(savedObj []byte) error {
+	saved := &types.Function{Name: function.Name}
+	return modify(db, saved, func() {
+		saved.ArtifactDigest = function.ArtifactDigest
+		saved.SourceCommit = function.SourceCommit
+	})
+}
 
-		if savedObj == nil {
-			return fmt.Errorf("unable to found function")
-		}
+//AddFunctionLog add function log
+func AddFunctionLog(db storage.Store, function *types.Function, log types.EntityLog, status string) error {
 
-		json.Unmarshal(savedObj, function)
+	log.Time = time.Now().String()
 
-		return nil
+	return modify(db, function, func() {
+		function.Log = log
+		function.Status = status
 	})
+}
 
-	if err != nil {
-		return err
-	}
-
-	function.Log = log
-	function.Status = status
-	return Save(db, function)
+//SetFunctionBuild set last image build of the function
+func SetFunctionBuild(db storage.Store, function *types.Function, build types.FunctionBuild) error {
+	return modify(db, function, func() {
+		function.Build = build
+	})
 }
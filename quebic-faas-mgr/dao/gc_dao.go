@@ -0,0 +1,90 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package dao
+
+import (
+	"fmt"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"strconv"
+)
+
+//completed workers of deployments which are waiting for gc. shared by manager replicas
+const gcWorkersBucket = "GCCompletedWorkers"
+
+//GCAssignWorker count a completed worker of the deployment. returns completed workers count
+func GCAssignWorker(db storage.Store, deploymentID string) (int, error) {
+
+	count := 0
+
+	err := db.Update(func(tx storage.Tx) error {
+
+		bucket, err := tx.CreateBucketIfNotExists([]byte(gcWorkersBucket))
+		if err != nil {
+			return fmt.Errorf("unable to create bucket for %s, error : %v", gcWorkersBucket, err)
+		}
+
+		if v := bucket.Get([]byte(deploymentID)); v != nil {
+			count, _ = strconv.Atoi(string(v))
+		}
+
+		count++
+
+		return bucket.Put([]byte(deploymentID), []byte(strconv.Itoa(count)))
+
+	})
+
+	return count, err
+
+}
+
+//GCGetCompletedWorkers deployment-id : completed workers count
+func GCGetCompletedWorkers(db storage.Store) (map[string]int, error) {
+
+	completedWorkers := make(map[string]int)
+
+	err := db.View(func(tx storage.Tx) error {
+
+		bucket := tx.Bucket([]byte(gcWorkersBucket))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			count, _ := strconv.Atoi(string(v))
+			completedWorkers[string(k)] = count
+			return nil
+		})
+
+	})
+
+	return completedWorkers, err
+
+}
+
+//GCReleaseDeployment remove completed workers count of the deployment
+func GCReleaseDeployment(db storage.Store, deploymentID string) error {
+
+	return db.Update(func(tx storage.Tx) error {
+
+		bucket := tx.Bucket([]byte(gcWorkersBucket))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.Delete([]byte(deploymentID))
+
+	})
+
+}
@@ -0,0 +1,134 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"quebic-faas/quebic-faas-mgr/gitsource"
+	"quebic-faas/quebic-faas-mgr/registry"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+)
+
+//AddGitCredential add git credential. password and private key are sealed with the credentials key
+func AddGitCredential(db storage.Store, credential *types.GitCredential, credentialsKey string, actor string) error {
+
+	err := sealGitCredential(credential, credentialsKey)
+	if err != nil {
+		return err
+	}
+
+	credential.SetCreatedAt()
+
+	return Add(db, credential, actor)
+
+}
+
+//UpdateGitCredential update git credential. empty password or private key keeps the saved one
+func UpdateGitCredential(db storage.Store, credential *types.GitCredential, credentialsKey string, actor string) error {
+
+	saved, err := GetGitCredential(db, credential.Host)
+	if err != nil {
+		return err
+	}
+
+	if saved == nil {
+		return fmt.Errorf("unable to found object")
+	}
+
+	credential.CreatedAt = saved.CreatedAt
+
+	err = sealGitCredential(credential, credentialsKey)
+	if err != nil {
+		return err
+	}
+
+	if credential.Password == "" {
+		credential.Password = saved.Password
+	}
+
+	if credential.PrivateKey == "" {
+		credential.PrivateKey = saved.PrivateKey
+	}
+
+	return Update(db, credential, actor)
+
+}
+
+//GetGitCredential get git credential by host. nil when there is no credential. secrets are sealed
+func GetGitCredential(db storage.Store, host string) (*types.GitCredential, error) {
+
+	var credential *types.GitCredential
+
+	err := getByID(db, &types.GitCredential{Host: host}, func(savedObj []byte) error {
+
+		if savedObj == nil {
+			return nil
+		}
+
+		credential = &types.GitCredential{}
+		return json.Unmarshal(savedObj, credential)
+
+	})
+
+	return credential, err
+
+}
+
+//GetGitCredentials opened credentials of the git host. empty credentials when there is no credential
+func GetGitCredentials(db storage.Store, host string, credentialsKey string) (gitsource.Credentials, error) {
+
+	credential, err := GetGitCredential(db, host)
+	if err != nil || credential == nil {
+		return gitsource.Credentials{}, err
+	}
+
+	password, err := registry.Open(credentialsKey, credential.Password)
+	if err != nil {
+		return gitsource.Credentials{}, fmt.Errorf("git credential %s : %v", credential.Host, err)
+	}
+
+	privateKey, err := registry.Open(credentialsKey, credential.PrivateKey)
+	if err != nil {
+		return gitsource.Credentials{}, fmt.Errorf("git credential %s : %v", credential.Host, err)
+	}
+
+	return gitsource.Credentials{Username: credential.Username, Password: password, PrivateKey: privateKey}, nil
+
+}
+
+//sealGitCredential seal the secrets which are given
+func sealGitCredential(credential *types.GitCredential, credentialsKey string) error {
+
+	if credential.Password != "" {
+		sealed, err := registry.Seal(credentialsKey, credential.Password)
+		if err != nil {
+			return err
+		}
+		credential.Password = sealed
+	}
+
+	if credential.PrivateKey != "" {
+		sealed, err := registry.Seal(credentialsKey, credential.PrivateKey)
+		if err != nil {
+			return err
+		}
+		credential.PrivateKey = sealed
+	}
+
+	return nil
+
+}
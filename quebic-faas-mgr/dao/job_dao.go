@@ -0,0 +1,166 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+)
+
+//SaveJob save a job which is processed by this manager. keeps the saved revision and it is not audited
+//job which is already finished in db (eg: failed as stale) is not overwritten.
+//then finished is true and job is reloaded from db
+func SaveJob(db storage.Store, job *types.Job) (bool, error) {
+
+	finished := false
+	saved := types.Job{}
+
+	err := write(db, job, func(savedObj []byte) error {
+
+		if savedObj == nil {
+			return nil
+		}
+
+		err := json.Unmarshal(savedObj, &saved)
+		if err != nil {
+			return fmt.Errorf("failed json parse, error : %v", err)
+		}
+
+		if saved.IsFinished() {
+			finished = true
+			return fmt.Errorf("job %s is already %s", saved.ID, saved.State)
+		}
+
+		job.SetRevision(saved.Revision)
+
+		return nil
+
+	}, "", "")
+
+	if finished {
+		*job = saved
+		return true, nil
+	}
+
+	return false, err
+
+}
+
+//FailStaleJobs fail unfinished jobs which are selected by stale. eg: manager which processed them is gone
+func FailStaleJobs(db storage.Store, stale func(job types.Job) bool, cause string) (int, error) {
+
+	failed := 0
+
+	err := db.Update(func(tx storage.Tx) error {
+
+		bucketName := entityBucketName(&types.Job{})
+
+		bucket := tx.Bucket([]byte(bucketName))
+		if bucket == nil {
+			return nil
+		}
+
+		//collect first. updating while iterating bolt cursor skips keys
+		var jobs []types.Job
+		err := bucket.ForEach(func(k, v []byte) error {
+
+			job := types.Job{}
+			json.Unmarshal(v, &job)
+
+			if !job.IsFinished() && stale(job) {
+				jobs = append(jobs, job)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, job := range jobs {
+
+			job.Error = cause
+			job.State = common.JobStateFailed
+			job.SetModifiedAt()
+
+			jobJSON, err := json.Marshal(job)
+			if err != nil {
+				return fmt.Errorf("failed json marshal, error : %v", err)
+			}
+
+			err = bucket.Put([]byte(job.GetID()), jobJSON)
+			if err != nil {
+				return fmt.Errorf("unable to put data for %s, error : %v", bucketName, err)
+			}
+
+		}
+
+		failed = len(jobs)
+
+		return nil
+	})
+
+	return failed, err
+
+}
+
+//CompactJobs remove finished jobs which are not updated after finishedBefore
+func CompactJobs(db storage.Store, finishedBefore string) (int, error) {
+
+	removed := 0
+
+	err := db.Update(func(tx storage.Tx) error {
+
+		bucketName := entityBucketName(&types.Job{})
+
+		bucket := tx.Bucket([]byte(bucketName))
+		if bucket == nil {
+			return nil
+		}
+
+		var jobIDs [][]byte
+		err := bucket.ForEach(func(k, v []byte) error {
+
+			job := types.Job{}
+			json.Unmarshal(v, &job)
+
+			if job.IsFinished() && job.ModifiedAt < finishedBefore {
+				jobIDs = append(jobIDs, copyBytes(k))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, jobID := range jobIDs {
+			err := bucket.Delete(jobID)
+			if err != nil {
+				return fmt.Errorf("unable to delete for %s, error : %v", bucketName, err)
+			}
+		}
+
+		removed = len(jobIDs)
+
+		return nil
+	})
+
+	return removed, err
+
+}
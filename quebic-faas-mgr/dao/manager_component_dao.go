@@ -18,13 +18,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/storage"
 	"quebic-faas/types"
-
-	bolt "github.com/coreos/bbolt"
 )
 
 //ManagerComponentSetupAPIGateway setupAPIGateway
-func ManagerComponentSetupAPIGateway(db *bolt.DB) (*types.ManagerComponent, error) {
+func ManagerComponentSetupAPIGateway(db storage.Store) (*types.ManagerComponent, error) {
 
 	apiGateway := &types.ManagerComponent{ID: common.ComponentAPIGateway}
 	var version int
@@ -52,7 +51,7 @@ func ManagerComponentSetupAPIGateway(db *bolt.DB) (*types.ManagerComponent, erro
 }
 
 //ManagerComponentGetAPIGateway getAPIGateway
-func ManagerComponentGetAPIGateway(db *bolt.DB) (*types.ManagerComponent, error) {
+func ManagerComponentGetAPIGateway(db storage.Store) (*types.ManagerComponent, error) {
 
 	apiGateway := &types.ManagerComponent{ID: common.ComponentAPIGateway}
 
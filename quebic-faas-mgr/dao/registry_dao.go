@@ -0,0 +1,105 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"quebic-faas/quebic-faas-mgr/registry"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+)
+
+//AddRegistry add registry. password is sealed with the credentials key
+func AddRegistry(db storage.Store, reg *types.Registry, credentialsKey string, actor string) error {
+
+	sealed, err := registry.Seal(credentialsKey, reg.Password)
+	if err != nil {
+		return err
+	}
+
+	reg.Password = sealed
+	reg.SetCreatedAt()
+
+	return Add(db, reg, actor)
+
+}
+
+//UpdateRegistry update registry. empty password keeps the saved password
+func UpdateRegistry(db storage.Store, reg *types.Registry, credentialsKey string, actor string) error {
+
+	saved, err := GetRegistry(db, reg.Server)
+	if err != nil {
+		return err
+	}
+
+	if saved == nil {
+		return fmt.Errorf("unable to found object")
+	}
+
+	reg.CreatedAt = saved.CreatedAt
+
+	if reg.Password == "" {
+		reg.Password = saved.Password
+		return Update(db, reg, actor)
+	}
+
+	sealed, err := registry.Seal(credentialsKey, reg.Password)
+	if err != nil {
+		return err
+	}
+
+	reg.Password = sealed
+
+	return Update(db, reg, actor)
+
+}
+
+//GetRegistry get registry by server. nil when there is no registry. password is sealed
+func GetRegistry(db storage.Store, server string) (*types.Registry, error) {
+
+	var reg *types.Registry
+
+	err := getByID(db, &types.Registry{Server: server}, func(savedObj []byte) error {
+
+		if savedObj == nil {
+			return nil
+		}
+
+		reg = &types.Registry{}
+		return json.Unmarshal(savedObj, reg)
+
+	})
+
+	return reg, err
+
+}
+
+//GetRegistryCredentials registry and its opened credentials. nil registry when there is no registry
+func GetRegistryCredentials(db storage.Store, server string, credentialsKey string) (*types.Registry, registry.Credentials, error) {
+
+	reg, err := GetRegistry(db, registry.NormalizeServer(server))
+	if err != nil || reg == nil {
+		return nil, registry.Credentials{}, err
+	}
+
+	password, err := registry.Open(credentialsKey, reg.Password)
+	if err != nil {
+		return nil, registry.Credentials{}, fmt.Errorf("registry %s : %v", reg.Server, err)
+	}
+
+	return reg, registry.Credentials{Username: reg.Username, Password: password}, nil
+
+}
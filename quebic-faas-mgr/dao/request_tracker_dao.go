@@ -0,0 +1,233 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+	"strings"
+)
+
+const requestTrackerBucket = "RequestTracker"
+
+//requestTrackerIndexBucket key => <createdAt>|<requestID>, value => requestID
+const requestTrackerIndexBucket = "RequestTrackerIndex"
+
+const requestTrackerIndexJOIN = "|"
+
+//compaction removes trackers in batches to keep write transactions small
+const requestTrackerCompactBatch = 1000
+
+//SaveRequestTracker save request-tracker and keep it in created time index
+//addedLogs and removedLogs are applied into log index
+func SaveRequestTracker(db storage.Store, requestTracker *types.RequestTracker, addedLogs []types.Log, removedLogs []types.Log) error {
+
+	requestTrackerJSON, err := json.Marshal(requestTracker)
+	if err != nil {
+		return fmt.Errorf("failed json parse, error : %v", err)
+	}
+
+	return db.Update(func(tx storage.Tx) error {
+
+		bucket, err := tx.CreateBucketIfNotExists([]byte(requestTrackerBucket))
+		if err != nil {
+			return fmt.Errorf("unable to create bucket for %s, error : %v", requestTrackerBucket, err)
+		}
+
+		index, err := tx.CreateBucketIfNotExists([]byte(requestTrackerIndexBucket))
+		if err != nil {
+			return fmt.Errorf("unable to create bucket for %s, error : %v", requestTrackerIndexBucket, err)
+		}
+
+		err = bucket.Put([]byte(requestTracker.RequestID), requestTrackerJSON)
+		if err != nil {
+			return fmt.Errorf("unable to put data for %s, error : %v", requestTrackerBucket, err)
+		}
+
+		err = index.Put(prepareRequestTrackerIndexKey(requestTracker), []byte(requestTracker.RequestID))
+		if err != nil {
+			return fmt.Errorf("unable to put data for %s, error : %v", requestTrackerIndexBucket, err)
+		}
+
+		return updateLogIndex(tx, requestTracker, addedLogs, removedLogs)
+	})
+
+}
+
+//ScanRequestTrackers walk through request-trackers newest first, starting after the cursor
+//fn returns false to stop
+func ScanRequestTrackers(db storage.Store, cursor string, fn func(indexKey string, requestTracker types.RequestTracker) bool) error {
+
+	return db.View(func(tx storage.Tx) error {
+
+		bucket := tx.Bucket([]byte(requestTrackerBucket))
+		index := tx.Bucket([]byte(requestTrackerIndexBucket))
+		if bucket == nil || index == nil {
+			return nil
+		}
+
+		c := index.Cursor()
+
+		var k, v []byte
+		if cursor == "" {
+			k, v = c.Last()
+		} else {
+			//cursor key itself might be already compacted
+			k, _ = c.Seek([]byte(cursor))
+			if k == nil {
+				k, v = c.Last()
+			} else {
+				k, v = c.Prev()
+			}
+		}
+
+		for ; k != nil; k, v = c.Prev() {
+
+			savedObj := bucket.Get(v)
+			if savedObj == nil {
+				continue
+			}
+
+			requestTracker := types.RequestTracker{}
+			json.Unmarshal(savedObj, &requestTracker)
+
+			if !fn(string(k), requestTracker) {
+				break
+			}
+
+		}
+
+		return nil
+	})
+
+}
+
+//CompactRequestTrackers remove trackers created before createdBefore and oldest trackers beyond maxTrackers
+//empty createdBefore or zero maxTrackers skip that rule. return removed count
+func CompactRequestTrackers(db storage.Store, createdBefore string, maxTrackers int) (int, error) {
+
+	removed := 0
+
+	for {
+
+		batchRemoved := 0
+
+		err := db.Update(func(tx storage.Tx) error {
+
+			bucket := tx.Bucket([]byte(requestTrackerBucket))
+			index := tx.Bucket([]byte(requestTrackerIndexBucket))
+			if bucket == nil || index == nil {
+				return nil
+			}
+
+			excess := 0
+			if maxTrackers > 0 {
+				excess = index.Count() - maxTrackers
+			}
+
+			//collect first. deleting while iterating bolt cursor skips keys
+			var indexKeys [][]byte
+			var requestIDs [][]byte
+
+			c := index.Cursor()
+			for k, v := c.First(); k != nil && len(indexKeys) < requestTrackerCompactBatch; k, v = c.Next() {
+
+				createdAt := strings.SplitN(string(k), requestTrackerIndexJOIN, 2)[0]
+				expired := createdBefore != "" && createdAt < createdBefore
+
+				if !expired && len(indexKeys) >= excess {
+					break
+				}
+
+				indexKeys = append(indexKeys, append([]byte(nil), k...))
+				requestIDs = append(requestIDs, append([]byte(nil), v...))
+
+			}
+
+			for i := range indexKeys {
+
+				err := index.Delete(indexKeys[i])
+				if err != nil {
+					return fmt.Errorf("unable to delete for %s, error : %v", requestTrackerIndexBucket, err)
+				}
+
+				if savedObj := bucket.Get(requestIDs[i]); savedObj != nil {
+					requestTracker := types.RequestTracker{}
+					json.Unmarshal(savedObj, &requestTracker)
+					err = deleteLogIndex(tx, string(requestIDs[i]), requestTracker.Logs)
+					if err != nil {
+						return err
+					}
+				}
+
+				err = bucket.Delete(requestIDs[i])
+				if err != nil {
+					return fmt.Errorf("unable to delete for %s, error : %v", requestTrackerBucket, err)
+				}
+
+			}
+
+			batchRemoved = len(indexKeys)
+
+			return nil
+		})
+		if err != nil {
+			return removed, err
+		}
+
+		removed += batchRemoved
+
+		if batchRemoved < requestTrackerCompactBatch {
+			return removed, nil
+		}
+
+	}
+
+}
+
+//RebuildRequestTrackerIndex index trackers which were saved before the index was introduced
+func RebuildRequestTrackerIndex(db storage.Store) error {
+
+	return db.Update(func(tx storage.Tx) error {
+
+		bucket := tx.Bucket([]byte(requestTrackerBucket))
+		if bucket == nil || tx.Bucket([]byte(requestTrackerIndexBucket)) != nil {
+			return nil
+		}
+
+		index, err := tx.CreateBucket([]byte(requestTrackerIndexBucket))
+		if err != nil {
+			return fmt.Errorf("unable to create bucket for %s, error : %v", requestTrackerIndexBucket, err)
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+
+			requestTracker := types.RequestTracker{}
+			json.Unmarshal(v, &requestTracker)
+			requestTracker.RequestID = string(k)
+
+			return index.Put(prepareRequestTrackerIndexKey(&requestTracker), k)
+		})
+
+	})
+
+}
+
+//DefaultTimeLayout is sortable. so index keys are in created order
+func prepareRequestTrackerIndexKey(requestTracker *types.RequestTracker) []byte {
+	return []byte(requestTracker.CreatedAt + requestTrackerIndexJOIN + requestTracker.RequestID)
+}
@@ -0,0 +1,353 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+	"strings"
+	"unicode/utf8"
+)
+
+//requestTrackerLogIndexBucket index of request-tracker logs. value is always empty
+// t|<time>|<requestID>
+// y|<type>|<time>|<requestID>
+// s|<source>|<time>|<requestID>
+// g|<trigram of message>|<requestID>
+const requestTrackerLogIndexBucket = "RequestTrackerLogIndex"
+
+const logIndexTime = "t"
+const logIndexType = "y"
+const logIndexSource = "s"
+const logIndexTrigram = "g"
+
+//only beginning of long messages are indexed into trigrams
+const logIndexMaxMessageLength = 2048
+
+//LogIndexQuery narrow request-trackers using log index. empty fields are ignored
+// Trigrams : lowercased trigrams which all should be found in a message of the tracker
+type LogIndexQuery struct {
+	Type     string
+	Source   string
+	From     string
+	To       string
+	Trigrams []string
+}
+
+//PrepareTrigrams lowercased distinct trigrams of the text
+func PrepareTrigrams(text string) []string {
+
+	runes := []rune(strings.ToLower(text))
+
+	found := make(map[string]bool)
+	var trigrams []string
+	for i := 0; i+3 <= len(runes); i++ {
+		trigram := string(runes[i : i+3])
+		if !found[trigram] {
+			found[trigram] = true
+			trigrams = append(trigrams, trigram)
+		}
+	}
+
+	return trigrams
+
+}
+
+//FindRequestTrackerCandidates requestIDs which may match to the query, newest log first
+//candidates should be verified against the saved logs. max limits the count
+func FindRequestTrackerCandidates(db storage.Store, query LogIndexQuery, max int) ([]string, error) {
+
+	var requestIDs []string
+
+	err := db.View(func(tx storage.Tx) error {
+
+		index := tx.Bucket([]byte(requestTrackerLogIndexBucket))
+		if index == nil {
+			return nil
+		}
+
+		if len(query.Trigrams) > 0 {
+			requestIDs = findByTrigrams(index, query.Trigrams, max)
+			return nil
+		}
+
+		var prefix string
+		switch {
+		case query.Type != "":
+			prefix = joinLogIndexKey(logIndexType, strings.ToLower(query.Type)) + requestTrackerIndexJOIN
+		case query.Source != "":
+			prefix = joinLogIndexKey(logIndexSource, query.Source) + requestTrackerIndexJOIN
+		default:
+			prefix = logIndexTime + requestTrackerIndexJOIN
+		}
+
+		requestIDs = findByTimeRange(index, prefix, query.From, query.To, max, nil)
+
+		return nil
+	})
+
+	return requestIDs, err
+
+}
+
+//findByTimeRange walk keys of the prefix newest first. keys => <prefix><time>|<requestID>
+//only requestIDs which are accepted are taken when accept is given
+func findByTimeRange(index storage.Bucket, prefix string, from string, to string, max int, accept func(requestID string) bool) []string {
+
+	upper := prefix + "\xff"
+	if to != "" {
+		upper = prefix + to + requestTrackerIndexJOIN + "\xff"
+	}
+
+	c := index.Cursor()
+
+	k, _ := c.Seek([]byte(upper))
+	if k == nil {
+		k, _ = c.Last()
+	} else {
+		k, _ = c.Prev()
+	}
+
+	found := make(map[string]bool)
+	var requestIDs []string
+
+	for ; k != nil && strings.HasPrefix(string(k), prefix); k, _ = c.Prev() {
+
+		rest := strings.TrimPrefix(string(k), prefix)
+		sep := strings.LastIndex(rest, requestTrackerIndexJOIN)
+		if sep < 0 {
+			continue
+		}
+
+		logTime := rest[:sep]
+		requestID := rest[sep+1:]
+
+		if from != "" && logTime < from {
+			break
+		}
+
+		if accept != nil && !accept(requestID) {
+			continue
+		}
+
+		if !found[requestID] {
+			found[requestID] = true
+			requestIDs = append(requestIDs, requestID)
+			if len(requestIDs) >= max {
+				break
+			}
+		}
+
+	}
+
+	return requestIDs
+
+}
+
+//findByTrigrams intersection of trackers which contain every trigram
+func findByTrigrams(index storage.Bucket, trigrams []string, max int) []string {
+
+	var candidates map[string]bool
+
+	for _, trigram := range trigrams {
+
+		prefix := joinLogIndexKey(logIndexTrigram, trigram) + requestTrackerIndexJOIN
+		matched := make(map[string]bool)
+
+		c := index.Cursor()
+		for k, _ := c.Seek([]byte(prefix)); k != nil && strings.HasPrefix(string(k), prefix); k, _ = c.Next() {
+			requestID := strings.TrimPrefix(string(k), prefix)
+			if candidates == nil || candidates[requestID] {
+				matched[requestID] = true
+			}
+		}
+
+		candidates = matched
+		if len(candidates) == 0 {
+			return nil
+		}
+
+	}
+
+	if len(candidates) < max {
+		max = len(candidates)
+	}
+
+	//newest log first, same as the other queries
+	accept := func(requestID string) bool {
+		return candidates[requestID]
+	}
+
+	return findByTimeRange(index, logIndexTime+requestTrackerIndexJOIN, "", "", max, accept)
+
+}
+
+//GetRequestTrackersByIDs get saved request-trackers. missing ones are skipped
+func GetRequestTrackersByIDs(db storage.Store, requestIDs []string) ([]types.RequestTracker, error) {
+
+	var requestTrackers []types.RequestTracker
+
+	err := db.View(func(tx storage.Tx) error {
+
+		bucket := tx.Bucket([]byte(requestTrackerBucket))
+		if bucket == nil {
+			return nil
+		}
+
+		for _, requestID := range requestIDs {
+
+			savedObj := bucket.Get([]byte(requestID))
+			if savedObj == nil {
+				continue
+			}
+
+			requestTracker := types.RequestTracker{}
+			err := json.Unmarshal(savedObj, &requestTracker)
+			if err != nil {
+				return fmt.Errorf("failed json parse, error : %v", err)
+			}
+
+			requestTrackers = append(requestTrackers, requestTracker)
+
+		}
+
+		return nil
+	})
+
+	return requestTrackers, err
+
+}
+
+//RebuildRequestTrackerLogIndex index logs of trackers which were saved before the log index was introduced
+func RebuildRequestTrackerLogIndex(db storage.Store) error {
+
+	return db.Update(func(tx storage.Tx) error {
+
+		bucket := tx.Bucket([]byte(requestTrackerBucket))
+		if bucket == nil || tx.Bucket([]byte(requestTrackerLogIndexBucket)) != nil {
+			return nil
+		}
+
+		index, err := tx.CreateBucket([]byte(requestTrackerLogIndexBucket))
+		if err != nil {
+			return fmt.Errorf("unable to create bucket for %s, error : %v", requestTrackerLogIndexBucket, err)
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+
+			requestTracker := types.RequestTracker{}
+			json.Unmarshal(v, &requestTracker)
+
+			return putLogIndexKeys(index, prepareLogIndexKeys(string(k), requestTracker.Logs))
+		})
+
+	})
+
+}
+
+//updateLogIndex index added logs and remove keys of removed logs which are not shared with remaining logs
+func updateLogIndex(tx storage.Tx, requestTracker *types.RequestTracker, addedLogs []types.Log, removedLogs []types.Log) error {
+
+	index, err := tx.CreateBucketIfNotExists([]byte(requestTrackerLogIndexBucket))
+	if err != nil {
+		return fmt.Errorf("unable to create bucket for %s, error : %v", requestTrackerLogIndexBucket, err)
+	}
+
+	if len(removedLogs) > 0 {
+
+		remaining := prepareLogIndexKeys(requestTracker.RequestID, requestTracker.Logs)
+		for key := range prepareLogIndexKeys(requestTracker.RequestID, removedLogs) {
+			if remaining[key] {
+				continue
+			}
+			err := index.Delete([]byte(key))
+			if err != nil {
+				return fmt.Errorf("unable to delete for %s, error : %v", requestTrackerLogIndexBucket, err)
+			}
+		}
+
+	}
+
+	return putLogIndexKeys(index, prepareLogIndexKeys(requestTracker.RequestID, addedLogs))
+
+}
+
+//deleteLogIndex remove all index keys of the tracker
+func deleteLogIndex(tx storage.Tx, requestID string, logs []types.Log) error {
+
+	index := tx.Bucket([]byte(requestTrackerLogIndexBucket))
+	if index == nil {
+		return nil
+	}
+
+	for key := range prepareLogIndexKeys(requestID, logs) {
+		err := index.Delete([]byte(key))
+		if err != nil {
+			return fmt.Errorf("unable to delete for %s, error : %v", requestTrackerLogIndexBucket, err)
+		}
+	}
+
+	return nil
+
+}
+
+func putLogIndexKeys(index storage.Bucket, keys map[string]bool) error {
+
+	for key := range keys {
+		err := index.Put([]byte(key), []byte{})
+		if err != nil {
+			return fmt.Errorf("unable to put data for %s, error : %v", requestTrackerLogIndexBucket, err)
+		}
+	}
+
+	return nil
+
+}
+
+func prepareLogIndexKeys(requestID string, logs []types.Log) map[string]bool {
+
+	keys := make(map[string]bool)
+
+	for _, l := range logs {
+
+		keys[joinLogIndexKey(logIndexTime, l.Time, requestID)] = true
+		keys[joinLogIndexKey(logIndexType, strings.ToLower(l.Type), l.Time, requestID)] = true
+		keys[joinLogIndexKey(logIndexSource, l.Source, l.Time, requestID)] = true
+
+		message := l.Message
+		if len(message) > logIndexMaxMessageLength {
+			//cut on a rune boundary
+			cut := logIndexMaxMessageLength
+			for cut > 0 && !utf8.RuneStart(message[cut]) {
+				cut--
+			}
+			message = message[:cut]
+		}
+
+		for _, trigram := range PrepareTrigrams(message) {
+			keys[joinLogIndexKey(logIndexTrigram, trigram, requestID)] = true
+		}
+
+	}
+
+	return keys
+
+}
+
+func joinLogIndexKey(parts ...string) string {
+	return strings.Join(parts, requestTrackerIndexJOIN)
+}
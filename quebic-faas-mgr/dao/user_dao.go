@@ -16,20 +16,19 @@ package dao
 
 import (
 	"quebic-faas/auth"
+	"quebic-faas/quebic-faas-mgr/storage"
 	"quebic-faas/types"
-
-	bolt "github.com/coreos/bbolt"
 )
 
 //AddUser add user
-func AddUser(db *bolt.DB, user *types.User) error {
+func AddUser(db storage.Store, user *types.User, actor string) error {
 	user.Password = auth.PasswordEncode(user.Password)
 	user.SetCreatedAt()
-	return Add(db, user)
+	return Add(db, user, actor)
 }
 
-//SaveUser save user
-func SaveUser(db *bolt.DB, user *types.User) error {
+//UpdateUser update user
+func UpdateUser(db storage.Store, user *types.User, actor string) error {
 	user.Password = auth.PasswordEncode(user.Password)
-	return Save(db, user)
+	return Update(db, user, actor)
 }
@@ -15,12 +15,11 @@
 package db
 
 import (
-	bolt "github.com/coreos/bbolt"
+	"quebic-faas/quebic-faas-mgr/config"
+	"quebic-faas/quebic-faas-mgr/storage"
 )
 
-const defaultDBPath string = ".quebic-faas-mgr.db"
-
-//GetDb get database
-func GetDb() (*bolt.DB, error) {
-	return bolt.Open(defaultDBPath, 0600, nil)
+//GetDb get database of the configured storage backend
+func GetDb(storageConfig config.StorageConfig) (storage.Store, error) {
+	return storage.Open(storageConfig)
 }
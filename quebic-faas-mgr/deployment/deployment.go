@@ -15,6 +15,7 @@
 package deployment
 
 import (
+	"io"
 	"quebic-faas/types"
 
 	"k8s.io/api/core/v1"
@@ -38,9 +39,11 @@ type Deployment interface {
 	GetStatus(name string) (string, error)
 	LogsByName(name string, options types.FunctionContainerLogOptions) error
 	ListContainersByName(name string) ([]Container, error)
-	LogsByContainerID(id string, options types.FunctionContainerLogOptions) error
+	LogsByContainerID(id string, options types.FunctionContainerLogOptions) (io.ReadCloser, error)
 	IngressCreateOrUpdate(spec IngressSpec) error
 	IngressDescribe(waitForAvailable bool) (IngressDetails, error)
+	RegistrySecretCreateOrUpdate(name string, dockerConfigJSON []byte) error
+	RegistrySecretDelete(name string) error
 	DeploymentType() string
 }
 
@@ -58,17 +61,27 @@ type Details struct {
 }
 
 //Spec deployment spec
+//ImagePullSecrets registry secrets which are used to pull Dockerimage
+//SecretEnvkeys env variables which are taken from secrets. values are not kept in the deployment
 type Spec struct {
-	Name            string
-	DeploymentName  string
-	Version         string
-	Dockerimage     string
-	Replicas        Replicas
-	Envkeys         map[string]string
-	PortConfigs     []PortConfig
-	Volumes         []Volume
-	Command         []string
-	ImagePullPolicy string
+	Name             string
+	DeploymentName   string
+	Version          string
+	Dockerimage      string
+	Replicas         Replicas
+	Envkeys          map[string]string
+	PortConfigs      []PortConfig
+	Volumes          []Volume
+	Command          []string
+	ImagePullPolicy  string
+	ImagePullSecrets []string
+	SecretEnvkeys    map[string]SecretKeyRef
+}
+
+//SecretKeyRef key of a secret
+type SecretKeyRef struct {
+	Secret string
+	Key    string
 }
 
 //PortConfig portConfig
@@ -97,10 +110,12 @@ type Replicas int
 type ListFilters map[string]string
 
 //Volume volume
+//ClaimName persistent volume claim. when it is set HostPath is not used
 type Volume struct {
 	HostPath      string
 	ContainerPath string
 	HostPathType  v1.HostPathType
+	ClaimName     string
 }
 
 //IngressSpec ingress create/update spec
@@ -0,0 +1,249 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package kube_deployment
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const kubeSelecterKeyBuild = "quebic-faas-build"
+const kubeVolumeBuildContext = "quebic-faas-build-context"
+const kubeVolumeBuildSecret = "quebic-faas-build-secret"
+const kubeBuildJobPollInterval = time.Second * 2
+
+//kubeBuildPodFailureReasons builder container waits with these reasons forever. job is failed without waiting for the timeout
+var kubeBuildPodFailureReasons = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"ErrImageNeverPull":          true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+//BuildJobSpec image build which runs as a kubernetes job
+// VolumeClaim : persistent volume claim which carries the build context. mounted into VolumeMountPath
+// Secret : secret mounted into SecretMountPath. eg: registry credentials
+type BuildJobSpec struct {
+	Name            string
+	Image           string
+	Args            []string
+	VolumeClaim     string
+	VolumeMountPath string
+	Secret          string
+	SecretMountPath string
+	Timeout         time.Duration
+}
+
+//RunBuildJob run build job in quebic-faas namespace and wait until it is finished
+//output of the builder is passed into logger. job is removed after it is finished
+func (kubeDeployment Deployment) RunBuildJob(spec BuildJobSpec, logger func(line string)) error {
+
+	clientset, err := kubeDeployment.getClient()
+	if err != nil {
+		return err
+	}
+
+	jobsClient := clientset.BatchV1().Jobs(kubeNamespace)
+
+	_, err = jobsClient.Create(getBuildJobSpec(spec))
+	if err != nil {
+		return fmt.Errorf("kube-build-job create failed %v", err)
+	}
+
+	log.Printf("kube-build-job created : %s", spec.Name)
+
+	defer func() {
+		propagationPolicy := metav1.DeletePropagationBackground
+		err := jobsClient.Delete(spec.Name, &metav1.DeleteOptions{PropagationPolicy: &propagationPolicy})
+		if err != nil {
+			log.Printf("kube-build-job delete failed : %v", err)
+		}
+	}()
+
+	deadline := time.Now().Add(spec.Timeout)
+
+	podName, err := waitForBuildPod(clientset, spec.Name, deadline)
+	if err != nil {
+		return err
+	}
+
+	//logs are followed until builder is exited
+	req := clientset.Core().Pods(kubeNamespace).GetLogs(podName, &v1.PodLogOptions{Follow: true})
+	readCloser, err := req.Stream()
+	if err != nil {
+		logger(fmt.Sprintf("unable to follow build logs %v", err))
+	} else {
+		scanner := bufio.NewScanner(readCloser)
+		for scanner.Scan() {
+			logger(scanner.Text())
+		}
+		readCloser.Close()
+	}
+
+	return waitForBuildJob(clientset, spec.Name, deadline)
+
+}
+
+//waitForBuildPod wait until the builder is started. returns pod name
+func waitForBuildPod(clientset *kubernetes.Clientset, jobName string, deadline time.Time) (string, error) {
+
+	for time.Now().Before(deadline) {
+
+		pods, err := clientset.Core().Pods(kubeNamespace).List(metav1.ListOptions{
+			LabelSelector: kubeSelecterKeyBuild + "=" + jobName,
+		})
+		if err != nil {
+			return "", fmt.Errorf("kube-build-job pod get failed %v", err)
+		}
+
+		for _, pod := range pods.Items {
+
+			if pod.Status.Phase != v1.PodPending {
+				return pod.Name, nil
+			}
+
+			for _, containerStatus := range pod.Status.ContainerStatuses {
+				waiting := containerStatus.State.Waiting
+				if waiting != nil && kubeBuildPodFailureReasons[waiting.Reason] {
+					return "", fmt.Errorf("kube-build-job %s is not able to start %s %s", jobName, waiting.Reason, waiting.Message)
+				}
+			}
+
+		}
+
+		time.Sleep(kubeBuildJobPollInterval)
+
+	}
+
+	return "", fmt.Errorf("kube-build-job %s is not started within timeout", jobName)
+
+}
+
+//waitForBuildJob wait until the job is succeeded or failed
+func waitForBuildJob(clientset *kubernetes.Clientset, jobName string, deadline time.Time) error {
+
+	for time.Now().Before(deadline) {
+
+		job, err := clientset.BatchV1().Jobs(kubeNamespace).Get(jobName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("kube-build-job get failed %v", err)
+		}
+
+		if job.Status.Succeeded > 0 {
+			return nil
+		}
+
+		for _, condition := range job.Status.Conditions {
+			if condition.Type == batchv1.JobFailed && condition.Status == v1.ConditionTrue {
+				return fmt.Errorf("kube-build-job %s failed %s", jobName, condition.Message)
+			}
+		}
+
+		time.Sleep(kubeBuildJobPollInterval)
+
+	}
+
+	return fmt.Errorf("kube-build-job %s is not finished within timeout", jobName)
+
+}
+
+func getBuildJobSpec(spec BuildJobSpec) *batchv1.Job {
+
+	backoffLimit := int32(0)
+	activeDeadlineSeconds := int64(spec.Timeout.Seconds())
+
+	volumeMounts := []v1.VolumeMount{
+		{
+			Name:      kubeVolumeBuildContext,
+			MountPath: spec.VolumeMountPath,
+		},
+	}
+
+	volumes := []v1.Volume{
+		{
+			Name: kubeVolumeBuildContext,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+					ClaimName: spec.VolumeClaim,
+					ReadOnly:  true,
+				},
+			},
+		},
+	}
+
+	if spec.Secret != "" {
+
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      kubeVolumeBuildSecret,
+			MountPath: spec.SecretMountPath,
+			ReadOnly:  true,
+		})
+
+		volumes = append(volumes, v1.Volume{
+			Name: kubeVolumeBuildSecret,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: spec.Secret,
+					Items: []v1.KeyToPath{
+						{Key: v1.DockerConfigJsonKey, Path: "config.json"},
+					},
+				},
+			},
+		})
+
+	}
+
+	labels := map[string]string{
+		kubeSelecterKeyBuild: spec.Name,
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   spec.Name,
+			Labels: labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name:         "builder",
+							Image:        spec.Image,
+							Args:         spec.Args,
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Volumes:       volumes,
+					RestartPolicy: v1.RestartPolicyNever,
+				},
+			},
+		},
+	}
+
+}
@@ -20,6 +20,7 @@ import (
 	"log"
 	"os"
 	"quebic-faas/quebic-faas-mgr/deployment"
+	"strconv"
 	"time"
 
 	"quebic-faas/quebic-faas-mgr/config"
@@ -391,9 +392,19 @@ func (kubeDeployment Deployment) LogsByName(name string, options quebictypes.Fun
 		return err
 	}
 
+	if replicaIndex < 0 || replicaIndex >= len(containers) {
+		return fmt.Errorf("replica %d not found for %s", replicaIndex, name)
+	}
+
 	containerID := containers[replicaIndex].ID
 
-	err = kubeDeployment.LogsByContainerID(containerID, options)
+	readCloser, err := kubeDeployment.LogsByContainerID(containerID, options)
+	if err != nil {
+		return err
+	}
+
+	defer readCloser.Close()
+	_, err = io.Copy(os.Stdout, readCloser)
 	if err != nil {
 		return err
 	}
@@ -435,29 +446,53 @@ func (kubeDeployment Deployment) ListContainersByName(name string) ([]deployment
 }
 
 //LogsByContainerID implementation for deployment.LogsByContainerID()
-func (kubeDeployment Deployment) LogsByContainerID(id string, options quebictypes.FunctionContainerLogOptions) error {
+func (kubeDeployment Deployment) LogsByContainerID(id string, options quebictypes.FunctionContainerLogOptions) (io.ReadCloser, error) {
 
-	logOptions := v1.PodLogOptions{}
+	logOptions, err := preparePodLogOptions(options)
+	if err != nil {
+		return nil, err
+	}
 
 	clientset, err := kubeDeployment.getClient()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req := clientset.Core().Pods(kubeNamespace).GetLogs(id, &logOptions)
 
-	readCloser, err := req.Stream()
-	if err != nil {
-		return err
+	return req.Stream()
+
+}
+
+//preparePodLogOptions since => duration (eg: 10m) or RFC3339 time. tail => number of lines
+func preparePodLogOptions(options quebictypes.FunctionContainerLogOptions) (v1.PodLogOptions, error) {
+
+	logOptions := v1.PodLogOptions{
+		Follow:     options.Follow,
+		Timestamps: options.Timestamps,
 	}
 
-	defer readCloser.Close()
-	_, err = io.Copy(os.Stdout, readCloser)
-	if err != nil {
-		return err
+	if options.Since != "" {
+		if since, err := time.ParseDuration(options.Since); err == nil {
+			sinceSeconds := int64(since.Seconds())
+			logOptions.SinceSeconds = &sinceSeconds
+		} else if sinceTime, err := time.Parse(time.RFC3339, options.Since); err == nil {
+			t := metav1.NewTime(sinceTime)
+			logOptions.SinceTime = &t
+		} else {
+			return logOptions, fmt.Errorf("since should be a duration or RFC3339 time")
+		}
 	}
 
-	return nil
+	if options.Tail != "" && options.Tail != "all" {
+		tailLines, err := strconv.ParseInt(options.Tail, 10, 64)
+		if err != nil {
+			return logOptions, fmt.Errorf("tail should be a number")
+		}
+		logOptions.TailLines = &tailLines
+	}
+
+	return logOptions, nil
 
 }
 
@@ -737,6 +772,17 @@ func getDeploymentSpec(
 	for k, v := range spec.Envkeys {
 		envVar = append(envVar, v1.EnvVar{Name: k, Value: v})
 	}
+	for k, ref := range spec.SecretEnvkeys {
+		envVar = append(envVar, v1.EnvVar{
+			Name: k,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: ref.Secret},
+					Key:                  ref.Key,
+				},
+			},
+		})
+	}
 
 	//volumes
 	var volumeMounts []v1.VolumeMount
@@ -762,6 +808,14 @@ func getDeploymentSpec(
 			},
 		}
 
+		if volume.ClaimName != "" {
+			volumeSource = v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+					ClaimName: volume.ClaimName,
+				},
+			}
+		}
+
 		volumes = append(
 			volumes, v1.Volume{
 				Name:         kubeVolumeFunctionDir,
@@ -770,6 +824,12 @@ func getDeploymentSpec(
 
 	}
 
+	//image pull secrets
+	var imagePullSecrets []v1.LocalObjectReference
+	for _, secret := range spec.ImagePullSecrets {
+		imagePullSecrets = append(imagePullSecrets, v1.LocalObjectReference{Name: secret})
+	}
+
 	terminationGracePeriodSeconds := int64(0)
 
 	return &appsv1beta1.Deployment{
@@ -802,6 +862,7 @@ func getDeploymentSpec(
 						},
 					},
 					Volumes:                       volumes,
+					ImagePullSecrets:              imagePullSecrets,
 					TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
 				},
 			},
@@ -0,0 +1,144 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package kube_deployment
+
+import (
+	"encoding/json"
+	"fmt"
+	"quebic-faas/quebic-faas-mgr/leader"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const kubeLeaderAnnotation = "quebic-faas/leader"
+
+//LeaderLock leader record as an annotation of a configmap.
+//update is rejected by the api-server when the configmap has been changed after get
+type LeaderLock struct {
+	deployment Deployment
+	name       string
+	configMap  *v1.ConfigMap
+}
+
+//LeaderLock configmap lock in quebic-faas namespace
+func (kubeDeployment Deployment) LeaderLock(name string) *LeaderLock {
+	return &LeaderLock{deployment: kubeDeployment, name: name}
+}
+
+//Get implementation for leader.Lock.Get()
+func (lock *LeaderLock) Get() (*leader.Record, error) {
+
+	clientset, err := lock.deployment.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	configMap, err := clientset.Core().ConfigMaps(kubeNamespace).Get(lock.name, metav1.GetOptions{})
+	if err != nil {
+		lock.configMap = nil
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("kube-leader-lock get failed : %v", err)
+	}
+
+	lock.configMap = configMap
+
+	recordJSON, ok := configMap.Annotations[kubeLeaderAnnotation]
+	if !ok || recordJSON == "" {
+		return &leader.Record{}, nil
+	}
+
+	record := &leader.Record{}
+	err = json.Unmarshal([]byte(recordJSON), record)
+	if err != nil {
+		return nil, fmt.Errorf("kube-leader-lock unable to parse leader record : %v", err)
+	}
+
+	return record, nil
+
+}
+
+//Create implementation for leader.Lock.Create()
+func (lock *LeaderLock) Create(record leader.Record) error {
+
+	clientset, err := lock.deployment.getClient()
+	if err != nil {
+		return err
+	}
+
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	configMap, err := clientset.Core().ConfigMaps(kubeNamespace).Create(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        lock.name,
+			Namespace:   kubeNamespace,
+			Annotations: map[string]string{kubeLeaderAnnotation: string(recordJSON)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("kube-leader-lock create failed : %v", err)
+	}
+
+	lock.configMap = configMap
+
+	return nil
+
+}
+
+//Update implementation for leader.Lock.Update()
+func (lock *LeaderLock) Update(record leader.Record) error {
+
+	if lock.configMap == nil {
+		return fmt.Errorf("kube-leader-lock update failed : leader record is not loaded")
+	}
+
+	clientset, err := lock.deployment.getClient()
+	if err != nil {
+		return err
+	}
+
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	//resourceVersion of the loaded configmap is kept. conflicts are rejected
+	configMap := lock.configMap.DeepCopy()
+	if configMap.Annotations == nil {
+		configMap.Annotations = make(map[string]string)
+	}
+	configMap.Annotations[kubeLeaderAnnotation] = string(recordJSON)
+
+	configMap, err = clientset.Core().ConfigMaps(kubeNamespace).Update(configMap)
+	if err != nil {
+		return fmt.Errorf("kube-leader-lock update failed : %v", err)
+	}
+
+	lock.configMap = configMap
+
+	return nil
+
+}
+
+//Describe implementation for leader.Lock.Describe()
+func (lock *LeaderLock) Describe() string {
+	return "configmap/" + kubeNamespace + "/" + lock.name
+}
@@ -0,0 +1,93 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package kube_deployment
+
+import (
+	"fmt"
+	"log"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const kubeSelecterKeyRegistry = "quebic-faas-registry"
+
+//RegistrySecretCreateOrUpdate implementation for deployment.RegistrySecretCreateOrUpdate()
+//kubernetes.io/dockerconfigjson secret. function deployments and build jobs refer it
+func (kubeDeployment Deployment) RegistrySecretCreateOrUpdate(name string, dockerConfigJSON []byte) error {
+
+	clientset, err := kubeDeployment.getClient()
+	if err != nil {
+		return err
+	}
+
+	secretsClient := clientset.Core().Secrets(kubeNamespace)
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				kubeSelecterKeyRegistry: "true",
+			},
+		},
+		Type: v1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			v1.DockerConfigJsonKey: dockerConfigJSON,
+		},
+	}
+
+	_, err = secretsClient.Update(secret)
+	if err != nil {
+
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("kube-registry-secret update failed %v", err)
+		}
+
+		_, err = secretsClient.Create(secret)
+		if err != nil {
+			return fmt.Errorf("kube-registry-secret create failed %v", err)
+		}
+
+		log.Printf("kube-registry-secret created : %s", name)
+
+		return nil
+
+	}
+
+	log.Printf("kube-registry-secret updated : %s", name)
+
+	return nil
+
+}
+
+//RegistrySecretDelete implementation for deployment.RegistrySecretDelete()
+func (kubeDeployment Deployment) RegistrySecretDelete(name string) error {
+
+	clientset, err := kubeDeployment.getClient()
+	if err != nil {
+		return err
+	}
+
+	err = clientset.Core().Secrets(kubeNamespace).Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("kube-registry-secret delete failed %v", err)
+	}
+
+	log.Printf("kube-registry-secret deleted : %s", name)
+
+	return nil
+
+}
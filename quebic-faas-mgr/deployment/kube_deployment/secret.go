@@ -0,0 +1,67 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package kube_deployment
+
+import (
+	"fmt"
+	"log"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//SecretCreateOrUpdate create or update opaque secret in quebic-faas namespace
+//deployments refer its keys through deployment.Spec.SecretEnvkeys
+func (kubeDeployment Deployment) SecretCreateOrUpdate(name string, data map[string][]byte) error {
+
+	clientset, err := kubeDeployment.getClient()
+	if err != nil {
+		return err
+	}
+
+	secretsClient := clientset.Core().Secrets(kubeNamespace)
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: data,
+	}
+
+	_, err = secretsClient.Update(secret)
+	if err != nil {
+
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("kube-secret update failed %v", err)
+		}
+
+		_, err = secretsClient.Create(secret)
+		if err != nil {
+			return fmt.Errorf("kube-secret create failed %v", err)
+		}
+
+		log.Printf("kube-secret created : %s", name)
+
+		return nil
+
+	}
+
+	log.Printf("kube-secret updated : %s", name)
+
+	return nil
+
+}
@@ -0,0 +1,103 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package deployment
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"quebic-faas/types"
+	"sync"
+)
+
+//LogLine single log line of a container
+type LogLine struct {
+	Container string `json:"container"`
+	Line      string `json:"line"`
+}
+
+//AggregateLogs interleave logs of all replicas of the deployment into one stream
+//stream is closed when all container logs are ended or done is closed
+func AggregateLogs(
+	deployment Deployment,
+	name string,
+	options types.FunctionContainerLogOptions,
+	done <-chan struct{}) (<-chan LogLine, error) {
+
+	containers, err := deployment.ListContainersByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no running replicas found for %s", name)
+	}
+
+	var readers []io.ReadCloser
+	for _, container := range containers {
+
+		reader, err := deployment.LogsByContainerID(container.ID, options)
+		if err != nil {
+			for _, r := range readers {
+				r.Close()
+			}
+			return nil, fmt.Errorf("unable to get logs of %s : %v", container.ID, err)
+		}
+
+		readers = append(readers, reader)
+
+	}
+
+	lines := make(chan LogLine)
+	wg := sync.WaitGroup{}
+
+	for i, container := range containers {
+
+		wg.Add(1)
+
+		go func(containerID string, reader io.ReadCloser) {
+
+			defer wg.Done()
+			defer reader.Close()
+
+			scanner := bufio.NewScanner(reader)
+			for scanner.Scan() {
+				select {
+				case lines <- LogLine{Container: containerID, Line: scanner.Text()}:
+				case <-done:
+					return
+				}
+			}
+
+		}(container.ID, readers[i])
+
+	}
+
+	//closing readers unblock the scanners which are waiting for new logs
+	go func() {
+		<-done
+		for _, r := range readers {
+			r.Close()
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	return lines, nil
+
+}
@@ -0,0 +1,156 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package function_artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"quebic-faas/common"
+	"strings"
+)
+
+const artifactsStoredDir string = ".quebic-faas-artifacts"
+const digestAlgorithm string = "sha256"
+
+//Store keeps uploaded artifacts by their sha256. an artifact is written once and never overwritten
+//Dir : empty => ~/.quebic-faas-artifacts. replicated managers share it through a volume
+type Store struct {
+	Dir string
+}
+
+//Save copy artifact into the store. returns digest eg: sha256:<hex> and size
+func (store Store) Save(source io.Reader) (string, int64, error) {
+
+	dir := store.digestDir()
+
+	err := os.MkdirAll(dir, os.FileMode.Perm(0755))
+	if err != nil {
+		return "", 0, fmt.Errorf("artifact store creation failed %v", err)
+	}
+
+	tempFile, err := ioutil.TempFile(dir, "upload-")
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to store artifact %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tempFile, hash), source)
+	tempFile.Close()
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to store artifact %v", err)
+	}
+
+	digest := digestAlgorithm + ":" + hex.EncodeToString(hash.Sum(nil))
+
+	//same content is already stored
+	if _, err := os.Stat(store.path(digest)); err == nil {
+		return digest, size, nil
+	}
+
+	err = os.Rename(tempFile.Name(), store.path(digest))
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to store artifact %v", err)
+	}
+
+	os.Chmod(store.path(digest), os.FileMode.Perm(0444))
+
+	return digest, size, nil
+
+}
+
+//Open open stored artifact
+func (store Store) Open(digest string) (*os.File, error) {
+
+	if !ValidDigest(digest) {
+		return nil, fmt.Errorf("invalid artifact digest %s", digest)
+	}
+
+	file, err := os.Open(store.path(digest))
+	if err != nil {
+		return nil, fmt.Errorf("artifact %s is not found in the store", digest)
+	}
+
+	return file, nil
+
+}
+
+//Verify stored content still has the digest
+func (store Store) Verify(digest string) error {
+
+	file, err := store.Open(digest)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return fmt.Errorf("unable to read artifact %v", err)
+	}
+
+	if digestAlgorithm+":"+hex.EncodeToString(hash.Sum(nil)) != digest {
+		return fmt.Errorf("artifact %s content is not match to its digest", digest)
+	}
+
+	return nil
+
+}
+
+//ValidDigest eg: sha256:<64 hex>
+func ValidDigest(digest string) bool {
+
+	if !strings.HasPrefix(digest, digestAlgorithm+":") {
+		return false
+	}
+
+	hexPart := strings.TrimPrefix(digest, digestAlgorithm+":")
+	_, err := hex.DecodeString(hexPart)
+
+	return err == nil && len(hexPart) == sha256.Size*2
+
+}
+
+//ImageTag docker tag of the image which is built from the artifact. eg: sha256-<hex>
+func ImageTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1)
+}
+
+func (store Store) path(digest string) string {
+	return store.digestDir() + common.FilepathSeparator + strings.TrimPrefix(digest, digestAlgorithm+":")
+}
+
+func (store Store) digestDir() string {
+	return store.dir() + common.FilepathSeparator + digestAlgorithm
+}
+
+func (store Store) dir() string {
+
+	if store.Dir != "" {
+		return store.Dir
+	}
+
+	if (common.GetUserHomeDir() == "") || (common.GetUserHomeDir() == common.FilepathSeparator) {
+		return common.FilepathSeparator + artifactsStoredDir
+	}
+
+	return common.GetUserHomeDir() + common.FilepathSeparator + artifactsStoredDir
+
+}
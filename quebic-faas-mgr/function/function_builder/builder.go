@@ -0,0 +1,93 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package function_builder
+
+import (
+	"fmt"
+	"quebic-faas/quebic-faas-mgr/config"
+	"quebic-faas/quebic-faas-mgr/deployment"
+	"quebic-faas/quebic-faas-mgr/deployment/kube_deployment"
+	"quebic-faas/quebic-faas-mgr/function/function_image"
+	quebicTypes "quebic-faas/types"
+
+	"github.com/docker/docker/api/types"
+)
+
+//Builder builds function image from the build context. returns image
+type Builder interface {
+	Build(
+		authConfig types.AuthConfig,
+		buildContextLocation string,
+		function quebicTypes.Function,
+		buildArgs map[string]string,
+		publish bool,
+		observer function_image.BuildObserver) (string, error)
+	BuilderType() string
+}
+
+//New builder which is selected by DockerConfig.Builder
+func New(dockerConfig config.DockerConfig, dep deployment.Deployment) (Builder, error) {
+
+	//digest is taken from the registry which built images are pushed into
+	if dockerConfig.VerifyImageDigest && dockerConfig.RegistryAddress == "" {
+		return nil, fmt.Errorf("verifyImageDigest requires registryAddress. images are not pushed without it")
+	}
+
+	switch dockerConfig.Builder {
+	case "", config.BuilderDocker:
+		return DockerBuilder{registryAddress: dockerConfig.RegistryAddress}, nil
+	case config.BuilderKaniko:
+		kubeDeployment, ok := dep.(kube_deployment.Deployment)
+		if !ok {
+			return nil, fmt.Errorf("%s builder requires %s deployment", config.BuilderKaniko, config.Deployment_Kubernetes)
+		}
+		return newKanikoBuilder(dockerConfig, kubeDeployment)
+	default:
+		return nil, fmt.Errorf("unknown builder %s", dockerConfig.Builder)
+	}
+
+}
+
+//DockerBuilder builds with the docker daemon of the host
+//images are tagged into the registry and pushed when registryAddress is set
+type DockerBuilder struct {
+	registryAddress string
+}
+
+//Build implementation for Builder.Build()
+func (builder DockerBuilder) Build(
+	authConfig types.AuthConfig,
+	buildContextLocation string,
+	function quebicTypes.Function,
+	buildArgs map[string]string,
+	publish bool,
+	observer function_image.BuildObserver) (string, error) {
+
+	image := function_image.GetImage(authConfig, function)
+	if builder.registryAddress != "" {
+		image = function_image.GetRegistryImage(builder.registryAddress, function)
+		publish = true
+	}
+
+	labels := function_image.GetImageLabels(function)
+
+	return function_image.FunctionImageBuild(authConfig, image, buildContextLocation, buildArgs, labels, publish, observer)
+
+}
+
+//BuilderType implementation for Builder.BuilderType()
+func (builder DockerBuilder) BuilderType() string {
+	return config.BuilderDocker
+}
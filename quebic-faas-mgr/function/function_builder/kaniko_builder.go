@@ -0,0 +1,189 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package function_builder
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/config"
+	"quebic-faas/quebic-faas-mgr/deployment/kube_deployment"
+	"quebic-faas/quebic-faas-mgr/function/function_image"
+	"quebic-faas/quebic-faas-mgr/registry"
+	quebicTypes "quebic-faas/types"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	uuid "github.com/satori/go.uuid"
+)
+
+const kanikoBuildJobPrefix = "quebic-faas-build-"
+const kanikoBuildContextFile = "context.tar.gz"
+const kanikoDockerConfigPath = "/kaniko/.docker"
+
+//kanikoPushingLog kaniko logs it once the image is built and push is started
+const kanikoPushingLog = "Pushing image to"
+
+//KanikoBuilder builds inside the cluster as a kubernetes job. no docker daemon is required
+//build context is handed over through the volume which is shared by manager and build jobs
+//built image is pushed into the registry, kubernetes pulls it from there
+type KanikoBuilder struct {
+	dockerConfig   config.DockerConfig
+	kubeDeployment kube_deployment.Deployment
+}
+
+func newKanikoBuilder(dockerConfig config.DockerConfig, kubeDeployment kube_deployment.Deployment) (KanikoBuilder, error) {
+
+	if dockerConfig.RegistryAddress == "" {
+		return KanikoBuilder{}, fmt.Errorf("%s builder requires registryAddress. built images are pulled from the registry", config.BuilderKaniko)
+	}
+
+	if dockerConfig.BuildVolumeClaim == "" {
+		return KanikoBuilder{}, fmt.Errorf("%s builder requires buildVolumeClaim. build contexts are handed over through it", config.BuilderKaniko)
+	}
+
+	if dockerConfig.BuilderImage == "" {
+		dockerConfig.BuilderImage = config.DefaultBuilderImage
+	}
+
+	if dockerConfig.BuildVolumePath == "" {
+		dockerConfig.BuildVolumePath = config.DefaultBuildVolumePath
+	}
+
+	if dockerConfig.BuildTimeoutMinutes <= 0 {
+		dockerConfig.BuildTimeoutMinutes = config.DefaultBuildTimeoutMinutes
+	}
+
+	return KanikoBuilder{dockerConfig: dockerConfig, kubeDeployment: kubeDeployment}, nil
+
+}
+
+//Build implementation for Builder.Build()
+//image is always pushed, because there is no local docker daemon to keep it
+func (builder KanikoBuilder) Build(
+	authConfig types.AuthConfig,
+	buildContextLocation string,
+	function quebicTypes.Function,
+	buildArgs map[string]string,
+	publish bool,
+	observer function_image.BuildObserver) (string, error) {
+
+	dockerConfig := builder.dockerConfig
+
+	buildUUID, err := uuid.NewV4()
+	if err != nil {
+		return "", fmt.Errorf("unable to assign build job name %v", err)
+	}
+	jobName := kanikoBuildJobPrefix + strings.Replace(buildUUID.String(), "-", "", -1)[:16]
+
+	buildDir := dockerConfig.BuildVolumePath + common.FilepathSeparator + jobName
+	err = copyBuildContext(buildContextLocation, buildDir)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(buildDir)
+
+	image := function_image.GetRegistryImage(dockerConfig.RegistryAddress, function)
+
+	args := []string{
+		"--context=tar://" + buildDir + "/" + kanikoBuildContextFile,
+		"--dockerfile=Dockerfile",
+		"--destination=" + image,
+	}
+
+	for k, v := range buildArgs {
+		args = append(args, "--build-arg="+k+"="+v)
+	}
+
+	for k, v := range function_image.GetImageLabels(function) {
+		args = append(args, "--label="+k+"="+v)
+	}
+
+	//credentials of the registry are used when RegistrySecret is not given
+	registrySecret := dockerConfig.RegistrySecret
+	if registrySecret == "" && authConfig.ServerAddress != "" {
+		registrySecret = registry.PullSecretName(authConfig.ServerAddress)
+	}
+
+	spec := kube_deployment.BuildJobSpec{
+		Name:            jobName,
+		Image:           dockerConfig.BuilderImage,
+		Args:            args,
+		VolumeClaim:     dockerConfig.BuildVolumeClaim,
+		VolumeMountPath: dockerConfig.BuildVolumePath,
+		Secret:          registrySecret,
+		SecretMountPath: kanikoDockerConfigPath,
+		Timeout:         time.Duration(dockerConfig.BuildTimeoutMinutes) * time.Minute,
+	}
+
+	observer.Log("build job " + jobName + " is started")
+
+	publishing := false
+	err = builder.kubeDeployment.RunBuildJob(spec, func(line string) {
+		if !publishing && strings.Contains(line, kanikoPushingLog) {
+			publishing = true
+			observer.Publishing()
+		}
+		observer.Log(line)
+	})
+	if err != nil {
+		return "", fmt.Errorf("image-build failed %v", err)
+	}
+
+	log.Printf("image %s is built by %s", image, jobName)
+
+	return image, nil
+
+}
+
+//BuilderType implementation for Builder.BuilderType()
+func (builder KanikoBuilder) BuilderType() string {
+	return config.BuilderKaniko
+}
+
+//copyBuildContext copy build context tar into the build volume as tar.gz
+func copyBuildContext(buildContextLocation string, buildDir string) error {
+
+	err := os.MkdirAll(buildDir, os.FileMode.Perm(0755))
+	if err != nil {
+		return fmt.Errorf("build dir creation failed %v", err)
+	}
+
+	buildContext, err := os.Open(buildContextLocation)
+	if err != nil {
+		return fmt.Errorf("unable to open buildContextLocation %v", err)
+	}
+	defer buildContext.Close()
+
+	target, err := os.Create(buildDir + common.FilepathSeparator + kanikoBuildContextFile)
+	if err != nil {
+		return fmt.Errorf("unable to create build context in build volume %v", err)
+	}
+	defer target.Close()
+
+	gw := gzip.NewWriter(target)
+
+	_, err = io.Copy(gw, buildContext)
+	if err != nil {
+		return fmt.Errorf("unable to copy build context into build volume %v", err)
+	}
+
+	return gw.Close()
+
+}
@@ -65,7 +65,7 @@ func CopyArtifactSourceToTarget(
 
 func PrepareBuildContextLocation(functionID string) (string, error) {
 
-	buildContextTar := getBuildContextTar(functionID)
+	buildContextTar := GetBuildContextTar(functionID)
 	functionDirPath := GetFunctionDir(functionID)
 
 	//removing previously created buildContextTar
@@ -180,7 +180,8 @@ func CreateHandlerTar(artifactPath string, targetArtifactPath string) error {
 	return nil
 }
 
-func getBuildContextTar(functionID string) string {
+//GetBuildContextTar docker build context of the function
+func GetBuildContextTar(functionID string) string {
 	return GetFunctionDir(functionID) + common.FilepathSeparator + buildContextTar
 }
 
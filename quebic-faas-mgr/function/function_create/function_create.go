@@ -20,6 +20,7 @@ import (
 	"log"
 	"os"
 	"quebic-faas/quebic-faas-mgr/function/function_common"
+	"quebic-faas/quebic-faas-mgr/function/function_dependencies"
 	"quebic-faas/quebic-faas-mgr/function/function_runtime"
 	quebicFaasTypes "quebic-faas/types"
 )
@@ -44,16 +45,30 @@ func CreateFunction(
 		return "", err
 	}
 
-	err = createFunctionDockerFile(functionID, functionRunTime)
+	//runtime without Dockerfile content brings its own Dockerfile. eg: custom
+	if functionRunTime.GetFunctionDockerFileContent() != "" {
+		err = createFunctionDockerFile(functionID, functionRunTime)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = functionRunTime.CopyFunctionIntoBuildContextLocation(functionID, functionSource)
 	if err != nil {
 		return "", err
 	}
 
-	err = functionRunTime.CopyFunctionIntoBuildContextLocation(functionID, functionSource)
+	err = function_dependencies.Prepare(functionID, functionRunTime.RuntimeType())
 	if err != nil {
 		return "", err
 	}
 
+	//uploaded build context is used as it is
+	buildContextTar := function_common.GetBuildContextTar(functionID)
+	if _, err := os.Stat(buildContextTar); err == nil {
+		return buildContextTar, nil
+	}
+
 	return function_common.PrepareBuildContextLocation(functionID)
 
 }
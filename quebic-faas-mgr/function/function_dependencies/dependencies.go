@@ -0,0 +1,261 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package function_dependencies
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/function/function_common"
+	"strings"
+)
+
+//handler tar of nodejs and python runtimes
+const buildContextHandlerTar string = "function_handler.tar"
+
+//dependencyStage build stage name
+const dependencyStage string = "dependencies"
+
+//dependencyManager install dependencies of a package
+type dependencyManager struct {
+	//manifests copied into the dependency stage. first one must exist in the package
+	manifests []string
+	//lockfiles copied when they exist in the package
+	lockfiles []string
+	//copyPackage whole package is copied into the dependency stage. eg: pyproject.toml
+	copyPackage bool
+	install     func(lockfiles []string) string
+	finalStage  string
+}
+
+var npm = dependencyManager{
+	manifests: []string{"package.json"},
+	lockfiles: []string{"package-lock.json", "npm-shrinkwrap.json"},
+	install: func(lockfiles []string) string {
+		if len(lockfiles) > 0 {
+			return "npm ci --production"
+		}
+		return "npm install --production"
+	},
+	finalStage: "COPY --from=" + dependencyStage + " /dependencies/node_modules /app/function_handler/node_modules\n",
+}
+
+var pipRequirements = dependencyManager{
+	manifests: []string{"requirements.txt"},
+	install: func(lockfiles []string) string {
+		return "pip install --no-cache-dir --target /dependencies/site-packages -r requirements.txt"
+	},
+	finalStage: pythonFinalStage,
+}
+
+var pipProject = dependencyManager{
+	manifests:   []string{"pyproject.toml"},
+	copyPackage: true,
+	install: func(lockfiles []string) string {
+		return "pip install --no-cache-dir --target /dependencies/site-packages /dependencies/src"
+	},
+	finalStage: pythonFinalStage,
+}
+
+const pythonFinalStage = "COPY --from=" + dependencyStage + " /dependencies/site-packages /app/dependencies\nENV PYTHONPATH /app/dependencies\n"
+
+//Prepare detect dependency manifests in the uploaded package and install dependencies in a build stage
+// manifests are copied into build context, so the install layer is rebuilt only when they are changed
+// dependencies_hash build arg keeps the layer per lockfile hash
+func Prepare(functionID string, runtimeType string) error {
+
+	var managers []dependencyManager
+	if runtimeType == common.RuntimeNodeJS {
+		managers = []dependencyManager{npm}
+	} else if runtimeType == common.RuntimePython_2_7 || runtimeType == common.RuntimePython_3_6 {
+		//requirements.txt is preferred. it can be cached without the package sources
+		managers = []dependencyManager{pipRequirements, pipProject}
+	} else {
+		return nil
+	}
+
+	functionDir := function_common.GetFunctionDir(functionID)
+	handlerTar := functionDir + common.FilepathSeparator + buildContextHandlerTar
+
+	files, err := readPackageRootFiles(handlerTar)
+	if err != nil {
+		return fmt.Errorf("unable to read package %v", err)
+	}
+
+	for _, manager := range managers {
+
+		if _, ok := files[manager.manifests[0]]; !ok {
+			continue
+		}
+
+		return manager.prepare(functionID, handlerTar, files)
+
+	}
+
+	return nil
+
+}
+
+func (manager dependencyManager) prepare(functionID string, handlerTar string, files map[string][]byte) error {
+
+	functionDir := function_common.GetFunctionDir(functionID)
+
+	var copied []string
+	var lockfiles []string
+	hash := sha256.New()
+
+	for _, name := range manager.manifests {
+		if content, ok := files[name]; ok {
+			copied = append(copied, name)
+			hash.Write(content)
+		}
+	}
+
+	for _, name := range manager.lockfiles {
+		if content, ok := files[name]; ok {
+			copied = append(copied, name)
+			lockfiles = append(lockfiles, name)
+			hash.Write(content)
+		}
+	}
+
+	//manifests become files of the build context
+	for _, name := range copied {
+		err := ioutil.WriteFile(functionDir+common.FilepathSeparator+name, files[name], os.FileMode.Perm(0644))
+		if err != nil {
+			return fmt.Errorf("unable to copy %s into build context %v", name, err)
+		}
+	}
+
+	if manager.copyPackage {
+		content, err := ioutil.ReadFile(handlerTar)
+		if err != nil {
+			return fmt.Errorf("unable to read package %v", err)
+		}
+		hash.Write(content)
+	}
+
+	dockerfilePath := function_common.GetDockerFilePath(functionID)
+	dockerfile, err := ioutil.ReadFile(dockerfilePath)
+	if err != nil {
+		return fmt.Errorf("unable to read Dockerfile %v", err)
+	}
+
+	image := finalStageImage(string(dockerfile))
+	if image == "" {
+		return fmt.Errorf("unable to found base image in Dockerfile")
+	}
+
+	dependenciesHash := hex.EncodeToString(hash.Sum(nil))
+
+	stage := "FROM " + image + " AS " + dependencyStage + "\n"
+	stage += "WORKDIR /dependencies\n"
+	if manager.copyPackage {
+		stage += "ADD " + buildContextHandlerTar + " /dependencies/src/\n"
+	} else {
+		stage += "COPY " + strings.Join(copied, " ") + " /dependencies/\n"
+	}
+	stage += "ARG dependencies_hash=" + dependenciesHash + "\n"
+	stage += "RUN " + manager.install(lockfiles) + "\n\n"
+
+	content := stage + string(dockerfile) + manager.finalStage
+
+	err = ioutil.WriteFile(dockerfilePath, []byte(content), os.FileMode.Perm(0777))
+	if err != nil {
+		return fmt.Errorf("unable to write Dockerfile %v", err)
+	}
+
+	log.Printf("dependencies of %s are installed in a build stage. manifests : %v, hash : %s", functionDir, copied, dependenciesHash)
+
+	return nil
+
+}
+
+//readPackageRootFiles files in the root of the package. package can be gzip compressed
+func readPackageRootFiles(packagePath string) (map[string][]byte, error) {
+
+	file, err := os.Open(packagePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+
+	var packageReader io.Reader = reader
+	magic, err := reader.Peek(2)
+	if err == nil && bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gr, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		packageReader = gr
+	}
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(packageReader)
+
+	for {
+
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimPrefix(header.Name, "./")
+		if header.Typeflag != tar.TypeReg || strings.Contains(name, "/") {
+			continue
+		}
+
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+
+		files[name] = content
+
+	}
+
+	return files, nil
+
+}
+
+//finalStageImage image of the last FROM instruction
+func finalStageImage(dockerfile string) string {
+
+	image := ""
+	for _, line := range strings.Split(dockerfile, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && strings.ToUpper(fields[0]) == "FROM" {
+			image = fields[1]
+		}
+	}
+
+	return image
+
+}
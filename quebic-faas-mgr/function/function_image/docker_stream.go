@@ -0,0 +1,89 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package function_image
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//BuildObserver observes function image build
+type BuildObserver interface {
+	//Log docker output line by line
+	Log(line string)
+	//Publishing image is built and going to be pushed
+	Publishing()
+}
+
+//dockerStreamMessage message of docker build / push json stream
+type dockerStreamMessage struct {
+	Stream      string `json:"stream"`
+	Status      string `json:"status"`
+	ID          string `json:"id"`
+	Progress    string `json:"progress"`
+	Error       string `json:"error"`
+	ErrorDetail struct {
+		Message string `json:"message"`
+	} `json:"errorDetail"`
+}
+
+//readDockerStream parse docker json stream. returns error when docker reports an error
+//docker replies 200 even for failed builds, failure is only found in the stream
+func readDockerStream(reader io.Reader, logger func(line string)) error {
+
+	decoder := json.NewDecoder(reader)
+
+	for {
+
+		message := dockerStreamMessage{}
+		err := decoder.Decode(&message)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("unable to parse docker response %v", err)
+		}
+
+		if message.ErrorDetail.Message != "" {
+			logger(message.ErrorDetail.Message)
+			return fmt.Errorf("%s", message.ErrorDetail.Message)
+		}
+
+		if message.Error != "" {
+			logger(message.Error)
+			return fmt.Errorf("%s", message.Error)
+		}
+
+		if message.Stream != "" {
+			for _, line := range strings.Split(strings.TrimRight(message.Stream, "\n"), "\n") {
+				logger(line)
+			}
+			continue
+		}
+
+		//progress updates of pull / push are skipped
+		if message.Status != "" && message.Progress == "" {
+			if message.ID != "" {
+				logger(message.ID + ": " + message.Status)
+			} else {
+				logger(message.Status)
+			}
+		}
+
+	}
+
+}
@@ -22,6 +22,8 @@ import (
 	"log"
 	"os"
 	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/function/function_artifact"
+	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -34,14 +36,18 @@ import (
 //const defaultTag string = "1.0.0"
 const imageTagPrefix string = "quebic-faas-function-"
 
-//FunctionImageBuild function image build
+const imageLabelArtifactDigest = "quebic-faas.artifact-digest"
+const imageLabelBuildID = "quebic-faas.build-id"
+
+//FunctionImageBuild function image build. build output is passed into observer
 func FunctionImageBuild(
 	authConfig types.AuthConfig,
+	image string,
 	buildContextLocation string,
-	function quebicTypes.Function,
-	publish bool) (string, error) {
-
-	image := GetImage(authConfig, function)
+	buildArgs map[string]string,
+	labels map[string]string,
+	publish bool,
+	observer BuildObserver) (string, error) {
 
 	cli, err := client.NewEnvClient()
 	if err != nil {
@@ -54,28 +60,32 @@ func FunctionImageBuild(
 		return "", fmt.Errorf("unable to open buildContextLocation %v", err)
 	}
 
-	//set accessKey into function container
-	buildArgs := make(map[string]*string)
-	buildArgs[common.EnvKeyAPIGateWayAccessKey] = &function.SecretKey
+	buildArgValues := make(map[string]*string)
+	for k, v := range buildArgs {
+		value := v
+		buildArgValues[k] = &value
+	}
 
 	options := types.ImageBuildOptions{
 		Tags:      []string{image},
-		BuildArgs: buildArgs,
+		BuildArgs: buildArgValues,
+		Labels:    labels,
 	}
 
 	imageBuildResponse, err := cli.ImageBuild(context.Background(), functionImageBuildContext, options)
-	defer imageBuildResponse.Body.Close()
 	if err != nil {
 		return "", fmt.Errorf("image-build failed %v", err)
 	}
+	defer imageBuildResponse.Body.Close()
 
-	_, err = io.Copy(os.Stdout, imageBuildResponse.Body)
+	err = readDockerStream(imageBuildResponse.Body, observer.Log)
 	if err != nil {
-		return "", fmt.Errorf("image-build-response-read failed %v", err)
+		return "", fmt.Errorf("image-build failed %v", err)
 	}
 
 	if publish {
-		err = functionImagePublish(authConfig, function)
+		observer.Publishing()
+		err = functionImagePublish(authConfig, image, observer.Log)
 		if err != nil {
 			return "", err
 		}
@@ -86,14 +96,15 @@ func FunctionImageBuild(
 }
 
 //FunctionImagePublish function image publish
-func functionImagePublish(authConfig types.AuthConfig, function quebicTypes.Function) error {
+func functionImagePublish(authConfig types.AuthConfig, image string, logger func(line string)) error {
 
-	if authConfig.Username == "" {
+	//images of a registry which has no credentials are pushed anonymously. eg: local registry
+	if authConfig.Username == "" && !strings.Contains(image, "/") {
 		log.Printf("docker auth configuration not found. not going to publish")
+		logger("docker auth configuration not found. image is not pushed")
 		return nil
 	}
 
-	image := GetImage(authConfig, function)
 	authStr := getAuthStr(authConfig)
 
 	cli, err := client.NewEnvClient()
@@ -106,14 +117,14 @@ func functionImagePublish(authConfig types.AuthConfig, function quebicTypes.Func
 	}
 
 	imagePushResponse, err := cli.ImagePush(context.Background(), image, options)
-	defer imagePushResponse.Close()
 	if err != nil {
 		return fmt.Errorf("image-push failed %v", err)
 	}
+	defer imagePushResponse.Close()
 
-	_, err = io.Copy(os.Stdout, imagePushResponse)
+	err = readDockerStream(imagePushResponse, logger)
 	if err != nil {
-		return fmt.Errorf("image-push-response-read failed %v", err)
+		return fmt.Errorf("image-push failed %v", err)
 	}
 
 	return nil
@@ -172,11 +183,83 @@ func FunctionContainerStart(authConfig types.AuthConfig, image string) error {
 
 }
 
+//GetBuildArgs build args of the function Dockerfile
+func GetBuildArgs(function quebicTypes.Function, dockerFile string) map[string]string {
+
+	buildArgs := make(map[string]string)
+
+	//set accessKey into function container
+	buildArgs[common.EnvKeyAPIGateWayAccessKey] = function.SecretKey
+
+	//go builder stage needs to know which package to compile
+	//catalog runtime can be based on go under any name, so it is decided by the Dockerfile
+	if declaresBuildArg(dockerFile, common.DockerBuildArgGoHandlerPackage) {
+		buildArgs[common.DockerBuildArgGoHandlerPackage] = function.HandlerFile
+	}
+
+	return buildArgs
+
+}
+
+//declaresBuildArg Dockerfile has ARG instruction of the name. eg: ARG name, ARG name=default
+func declaresBuildArg(dockerFile string, name string) bool {
+
+	for _, line := range strings.Split(dockerFile, "\n") {
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "ARG") {
+			continue
+		}
+
+		if strings.SplitN(fields[1], "=", 2)[0] == name {
+			return true
+		}
+
+	}
+
+	return false
+
+}
+
+//GetRegistryImage docker image in the registry
+func GetRegistryImage(registryAddress string, function quebicTypes.Function) string {
+	return registryAddress + "/" + imageTagPrefix + function.Name + ":" + getImageTag(function)
+}
+
+//getImageTag image is tagged with the artifact digest. same source produces same tag
+//build id is kept in the image labels and in the function build, not in the tag
+func getImageTag(function quebicTypes.Function) string {
+
+	if function.ArtifactDigest == "" {
+		return function.Version
+	}
+
+	return function_artifact.ImageTag(function.ArtifactDigest)
+
+}
+
+//GetImageLabels labels of the function image. artifact digest tells the source which the image is built from
+func GetImageLabels(function quebicTypes.Function) map[string]string {
+
+	labels := make(map[string]string)
+
+	if function.ArtifactDigest != "" {
+		labels[imageLabelArtifactDigest] = function.ArtifactDigest
+	}
+
+	if function.Build.ID != "" {
+		labels[imageLabelBuildID] = function.Build.ID
+	}
+
+	return labels
+
+}
+
 //GetImage get docker image
 func GetImage(authConfig types.AuthConfig, function quebicTypes.Function) string {
 
 	functionID := function.Name
-	functionVersion := function.Version
+	functionVersion := getImageTag(function)
 
 	if authConfig.Username == "" {
 		return imageTagPrefix + functionID + ":" + functionVersion
@@ -0,0 +1,91 @@
+package function_custom_runtime
+
+import (
+	"fmt"
+	"path/filepath"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/function/function_common"
+	"quebic-faas/types"
+)
+
+//BuildContextDockerfile file name of single Dockerfile artifact
+const BuildContextDockerfile string = "Dockerfile"
+
+//FunctionRunTime function runtime
+//function is deployed from an existing image, or built from uploaded Dockerfile / build context
+//image only has to honour the env-key contract. it is not templated by the manager
+type FunctionRunTime struct {
+}
+
+func (functionRunTime FunctionRunTime) RuntimeType() string {
+	return common.RuntimeCustom
+}
+
+func (functionRunTime FunctionRunTime) SetFunctionHandler(
+	function *types.Function,
+	functionSourceFile types.FunctionSourceFile,
+) error {
+
+	//handler is passed into image as it is
+	function.HandlerFile = ""
+	function.HandlerPath = function.Handler
+
+	//image is deployed without building
+	if function.Image != "" {
+		return nil
+	}
+
+	if !isBuildContext(functionSourceFile) && !isDockerfile(functionSourceFile) {
+		return fmt.Errorf("invalide artifact file type %s. upload Dockerfile or build context .tar", functionSourceFile.FileHeader.Filename)
+	}
+
+	return nil
+
+}
+
+//GetFunctionDockerFileContent Dockerfile comes from the uploaded artifact
+func (functionRunTime FunctionRunTime) GetFunctionDockerFileContent() string {
+	return ""
+}
+
+func (functionRunTime FunctionRunTime) GetTargetFunctionArtifactPath(functionID string) string {
+	return function_common.GetBuildContextTar(functionID)
+}
+
+func (functionRunTime FunctionRunTime) CopyFunctionIntoBuildContextLocation(
+	functionID string,
+	functionSource types.FunctionSourceFile,
+) error {
+
+	functionArtifactFile := functionSource.File
+
+	//single Dockerfile. function dir become the build context
+	if isDockerfile(functionSource) {
+
+		err := function_common.CopyArtifactSourceToTarget(functionArtifactFile, function_common.GetDockerFilePath(functionID))
+		if err != nil {
+			return fmt.Errorf("unable to copy Dockerfile into build-context location %v", err)
+		}
+
+		return nil
+
+	}
+
+	//build context is used as it is
+	err := function_common.CopyArtifactSourceToTarget(functionArtifactFile, functionRunTime.GetTargetFunctionArtifactPath(functionID))
+	if err != nil {
+		return fmt.Errorf("unable to copy build context into build-context location %v", err)
+	}
+
+	return nil
+
+}
+
+func isBuildContext(functionSource types.FunctionSourceFile) bool {
+	fileExt := filepath.Ext(functionSource.FileHeader.Filename)
+	return fileExt == ".tar" || fileExt == ".gz"
+}
+
+func isDockerfile(functionSource types.FunctionSourceFile) bool {
+	return filepath.Base(functionSource.FileHeader.Filename) == BuildContextDockerfile
+}
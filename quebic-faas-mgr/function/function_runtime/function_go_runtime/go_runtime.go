@@ -0,0 +1,100 @@
+package function_go_runtime
+
+import (
+	"fmt"
+	"path/filepath"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/function/function_common"
+	"quebic-faas/types"
+	"strings"
+)
+
+//BuildContextModuleRoot package of module root
+const BuildContextModuleRoot string = "."
+
+//BuildContext target location tar
+const buildContextHandlerTar string = "function_handler.tar"
+
+//FunctionRunTime function runtime
+type FunctionRunTime struct {
+}
+
+func (functionRunTime FunctionRunTime) RuntimeType() string {
+	return common.RuntimeGo
+}
+
+func (functionRunTime FunctionRunTime) SetFunctionHandler(
+	function *types.Function,
+	functionSourceFile types.FunctionSourceFile,
+) error {
+
+	functionArtifactFilename := functionSourceFile.FileHeader.Filename
+	fileExt := filepath.Ext(functionArtifactFilename)
+
+	//go module has to be packaged
+	if fileExt != ".tar" && fileExt != ".gz" {
+		return fmt.Errorf("invalide artifact file type %s. go module should be packaged as .tar", fileExt)
+	}
+
+	// Ex : handler = cmd/hello.HelloHandler
+	//handler = {package}.{handlerName}
+	//package can have dots, so handlerName is taken after the last dot
+	//handler without package refers main package of module root. eg: HelloHandler
+	handlerPackage := ""
+	handlerName := function.Handler
+
+	i := strings.LastIndex(function.Handler, ".")
+	if i >= 0 {
+		handlerPackage = function.Handler[:i]
+		handlerName = function.Handler[i+1:]
+	}
+
+	if handlerName == "" {
+		return fmt.Errorf("handler is invalide. handler name cannot be empty")
+	}
+
+	function.HandlerFile = getHandlerPackage(handlerPackage)
+	function.HandlerPath = handlerName
+
+	return nil
+
+}
+
+func (functionRunTime FunctionRunTime) GetFunctionDockerFileContent() string {
+	return common.DockerFileContent_Go
+}
+
+func (functionRunTime FunctionRunTime) GetTargetFunctionArtifactPath(functionID string) string {
+	return getBuildContextHandlerTar(functionID)
+}
+
+func (functionRunTime FunctionRunTime) CopyFunctionIntoBuildContextLocation(
+	functionID string,
+	functionSource types.FunctionSourceFile,
+) error {
+
+	functionArtifactFile := functionSource.File
+
+	err := function_common.CopyArtifactSourceToTarget(functionArtifactFile, getBuildContextHandlerTar(functionID))
+	if err != nil {
+		return fmt.Errorf("unable to copy module into build-context location %v", err)
+	}
+
+	return nil
+
+}
+
+//package which go build compiles. relative to module root
+func getHandlerPackage(handlerPackage string) string {
+
+	handlerPackage = strings.TrimSuffix(strings.TrimPrefix(handlerPackage, "./"), "/")
+	if handlerPackage == "" || handlerPackage == BuildContextModuleRoot {
+		return BuildContextModuleRoot
+	}
+
+	return BuildContextModuleRoot + "/" + handlerPackage
+}
+
+func getBuildContextHandlerTar(functionID string) string {
+	return function_common.GetFunctionDir(functionID) + common.FilepathSeparator + buildContextHandlerTar
+}
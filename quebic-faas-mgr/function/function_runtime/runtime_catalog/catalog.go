@@ -0,0 +1,219 @@
+package runtime_catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/function/function_runtime"
+	"quebic-faas/quebic-faas-mgr/function/function_runtime/function_custom_runtime"
+	"quebic-faas/quebic-faas-mgr/function/function_runtime/function_go_runtime"
+	"quebic-faas/quebic-faas-mgr/function/function_runtime/function_java_8_runtime"
+	"quebic-faas/quebic-faas-mgr/function/function_runtime/function_nodejs_runtime"
+	"quebic-faas/quebic-faas-mgr/function/function_runtime/function_python_2_7_runtime"
+	"quebic-faas/quebic-faas-mgr/function/function_runtime/function_python_3_6_runtime"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//DefaultRuntimes runtimes shipped with quebic. seeded into the catalog when manager starts
+func DefaultRuntimes() []types.RuntimeSpec {
+
+	return []types.RuntimeSpec{
+		{
+			Name:               common.RuntimeJava,
+			Version:            "8",
+			Base:               common.RuntimeJava,
+			Image:              common.DockerImage_Java,
+			DockerfileTemplate: common.DockerFileTemplate_Java,
+			Extensions:         []string{".jar"},
+		},
+		{
+			Name:               common.RuntimeNodeJS,
+			Base:               common.RuntimeNodeJS,
+			Image:              common.DockerImage_NodeJS,
+			DockerfileTemplate: common.DockerFileTemplate_Handler,
+			Extensions:         []string{".js", ".tar", ".gz"},
+		},
+		{
+			Name:               common.RuntimePython_2_7,
+			Version:            "2.7",
+			Base:               common.RuntimePython_2_7,
+			Image:              common.DockerImage_Python_2_7,
+			DockerfileTemplate: common.DockerFileTemplate_Handler,
+			Extensions:         []string{".py", ".tar", ".gz"},
+		},
+		{
+			Name:               common.RuntimePython_3_6,
+			Version:            "3.6",
+			Base:               common.RuntimePython_3_6,
+			Image:              common.DockerImage_Python_3_6,
+			DockerfileTemplate: common.DockerFileTemplate_Handler,
+			Extensions:         []string{".py", ".tar", ".gz"},
+		},
+		{
+			Name:               common.RuntimeGo,
+			Base:               common.RuntimeGo,
+			Image:              common.DockerImage_Go,
+			BuildImage:         common.DockerImage_GoBuilder,
+			DockerfileTemplate: common.DockerFileTemplate_Go,
+			Extensions:         []string{".tar", ".gz"},
+		},
+		{
+			Name: common.RuntimeCustom,
+			Base: common.RuntimeCustom,
+		},
+	}
+
+}
+
+//Setup add default runtimes which are not in the catalog. saved runtimes are not overridden
+func Setup(db storage.Store) {
+
+	for _, runtimeSpec := range DefaultRuntimes() {
+
+		spec := runtimeSpec
+		if getRuntimeSpec(db, spec.Name) != nil {
+			continue
+		}
+
+		err := dao.Add(db, &spec, dao.ActorSystem)
+		if err != nil {
+			log.Printf("unable to add %s runtime into catalog : %v", spec.Name, err)
+		}
+
+	}
+
+}
+
+//GetFunctionRunTime runtime of the catalog. nil when runtime is not found
+func GetFunctionRunTime(db storage.Store, runtime string) function_runtime.FunctionRunTime {
+
+	spec := getRuntimeSpec(db, runtime)
+	if spec == nil {
+		return nil
+	}
+
+	base := BaseFunctionRunTime(common.Runtime(spec.Base))
+	if base == nil {
+		return nil
+	}
+
+	return FunctionRunTime{Spec: *spec, Base: base}
+
+}
+
+//BaseFunctionRunTime built-in runtime
+func BaseFunctionRunTime(runtime common.Runtime) function_runtime.FunctionRunTime {
+
+	if runtime == common.RuntimeJava {
+		return function_java_8_runtime.FunctionRunTime{}
+	} else if runtime == common.RuntimeNodeJS {
+		return function_nodejs_runtime.FunctionRunTime{}
+	} else if runtime == common.RuntimePython_2_7 {
+		return function_python_2_7_runtime.FunctionRunTime{}
+	} else if runtime == common.RuntimePython_3_6 {
+		return function_python_3_6_runtime.FunctionRunTime{}
+	} else if runtime == common.RuntimeGo {
+		return function_go_runtime.FunctionRunTime{}
+	} else if runtime == common.RuntimeCustom {
+		return function_custom_runtime.FunctionRunTime{}
+	}
+
+	return nil
+}
+
+//ValidateRuntimeSpec validate catalog entry
+func ValidateRuntimeSpec(spec *types.RuntimeSpec) []string {
+
+	var errors []string
+
+	if spec.Name == "" {
+		errors = append(errors, "name field should not be empty")
+	}
+
+	if strings.Contains(spec.Name, " ") {
+		errors = append(errors, "name field not allow to contain spaces")
+	}
+
+	if !common.RuntimeValidate(common.Runtime(spec.Base)) {
+		errors = append(errors, fmt.Sprintf("base %s is not a built-in runtime", spec.Base))
+	}
+
+	if spec.Base == common.RuntimeCustom && spec.DockerfileTemplate != "" {
+		errors = append(errors, "custom runtime can not have dockerfileTemplate. Dockerfile comes with the function")
+	}
+
+	if spec.DockerfileTemplate != "" {
+		_, err := RenderDockerfile(*spec)
+		if err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	for _, extension := range spec.Extensions {
+		if !strings.HasPrefix(extension, ".") {
+			errors = append(errors, fmt.Sprintf("extension %s should start with .", extension))
+		}
+	}
+
+	if spec.HandlerPattern != "" {
+		_, err := regexp.Compile(spec.HandlerPattern)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("handlerPattern is invalide : %v", err))
+		}
+	}
+
+	return errors
+
+}
+
+//RenderDockerfile replace images in dockerfileTemplate
+func RenderDockerfile(spec types.RuntimeSpec) (string, error) {
+
+	t, err := template.New(spec.Name).Option("missingkey=error").Parse(spec.DockerfileTemplate)
+	if err != nil {
+		return "", fmt.Errorf("dockerfileTemplate is invalide : %v", err)
+	}
+
+	var dockerfile bytes.Buffer
+	err = t.Execute(&dockerfile, spec)
+	if err != nil {
+		return "", fmt.Errorf("dockerfileTemplate is invalide : %v", err)
+	}
+
+	return dockerfile.String(), nil
+
+}
+
+func getRuntimeSpec(db storage.Store, runtime string) *types.RuntimeSpec {
+
+	if runtime == "" {
+		return nil
+	}
+
+	spec := &types.RuntimeSpec{Name: runtime}
+	found := false
+
+	err := dao.GetByID(db, spec, func(savedObj []byte) error {
+
+		if savedObj == nil {
+			return nil
+		}
+
+		found = true
+		return json.Unmarshal(savedObj, spec)
+	})
+
+	if err != nil || !found {
+		return nil
+	}
+
+	return spec
+
+}
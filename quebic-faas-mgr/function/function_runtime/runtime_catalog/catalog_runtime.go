@@ -0,0 +1,99 @@
+package runtime_catalog
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"quebic-faas/quebic-faas-mgr/function/function_runtime"
+	"quebic-faas/types"
+	"regexp"
+)
+
+//FunctionRunTime runtime of the catalog
+//applies the rules of the catalog entry, then delegate into the base runtime
+type FunctionRunTime struct {
+	Spec types.RuntimeSpec
+	Base function_runtime.FunctionRunTime
+}
+
+//AcceptsArtifact artifact file type is accepted by the runtime. runtimes which are not from the catalog accept any
+func AcceptsArtifact(functionRunTime function_runtime.FunctionRunTime, filename string) bool {
+
+	catalogRunTime, ok := functionRunTime.(FunctionRunTime)
+	if !ok || len(catalogRunTime.Spec.Extensions) == 0 {
+		return true
+	}
+
+	return containsExtension(catalogRunTime.Spec.Extensions, filepath.Ext(filename))
+
+}
+
+//RuntimeType type of the base runtime. catalog entry name is the function runtime
+func (functionRunTime FunctionRunTime) RuntimeType() string {
+	return functionRunTime.Base.RuntimeType()
+}
+
+func (functionRunTime FunctionRunTime) SetFunctionHandler(
+	function *types.Function,
+	functionSourceFile types.FunctionSourceFile,
+) error {
+
+	spec := functionRunTime.Spec
+
+	if spec.HandlerPattern != "" {
+		matched, err := regexp.MatchString(spec.HandlerPattern, function.Handler)
+		if err != nil || !matched {
+			return fmt.Errorf("handler %s does not match %s runtime handler pattern %s", function.Handler, spec.Name, spec.HandlerPattern)
+		}
+	}
+
+	//function which deployed from an image does not have an artifact
+	if len(spec.Extensions) > 0 && functionSourceFile.FileHeader != nil {
+
+		fileExt := filepath.Ext(functionSourceFile.FileHeader.Filename)
+		if !containsExtension(spec.Extensions, fileExt) {
+			return fmt.Errorf("invalide artifact file type %s. %s runtime accepts %v", fileExt, spec.Name, spec.Extensions)
+		}
+
+	}
+
+	return functionRunTime.Base.SetFunctionHandler(function, functionSourceFile)
+
+}
+
+func (functionRunTime FunctionRunTime) GetFunctionDockerFileContent() string {
+
+	spec := functionRunTime.Spec
+	if spec.DockerfileTemplate == "" {
+		return functionRunTime.Base.GetFunctionDockerFileContent()
+	}
+
+	dockerfile, err := RenderDockerfile(spec)
+	if err != nil {
+		log.Printf("%s runtime : %v", spec.Name, err)
+		return ""
+	}
+
+	return dockerfile
+
+}
+
+func (functionRunTime FunctionRunTime) GetTargetFunctionArtifactPath(functionID string) string {
+	return functionRunTime.Base.GetTargetFunctionArtifactPath(functionID)
+}
+
+func (functionRunTime FunctionRunTime) CopyFunctionIntoBuildContextLocation(
+	functionID string,
+	functionSource types.FunctionSourceFile,
+) error {
+	return functionRunTime.Base.CopyFunctionIntoBuildContextLocation(functionID, functionSource)
+}
+
+func containsExtension(extensions []string, extension string) bool {
+	for _, e := range extensions {
+		if e == extension {
+			return true
+		}
+	}
+	return false
+}
@@ -21,12 +21,17 @@ import (
 	"quebic-faas/common"
 	"quebic-faas/messenger"
 	quebic_messenger "quebic-faas/messenger"
+	"quebic-faas/metrics"
 	mgrconfig "quebic-faas/quebic-faas-mgr/config"
+	"quebic-faas/quebic-faas-mgr/dao"
 	dep "quebic-faas/quebic-faas-mgr/deployment"
+	"quebic-faas/quebic-faas-mgr/function/function_builder"
 	"quebic-faas/quebic-faas-mgr/function/function_common"
 	"quebic-faas/quebic-faas-mgr/function/function_create"
 	"quebic-faas/quebic-faas-mgr/function/function_image"
 	"quebic-faas/quebic-faas-mgr/function/function_runtime"
+	"quebic-faas/quebic-faas-mgr/registry"
+	"quebic-faas/quebic-faas-mgr/storage"
 	quebicFaasTypes "quebic-faas/types"
 	"time"
 
@@ -35,15 +40,30 @@ import (
 
 const functionServicePrefix string = "quebic-faas-function-"
 
-//FunctionCreate create function
+var deployDuration = metrics.NewHistogram(
+	"quebic_mgr_function_deploy_duration_seconds",
+	"time taken to create-or-update function deployment",
+	[]float64{1, 2.5, 5, 10, 30, 60, 120, 300},
+	"function", "result")
+
+//FunctionCreate create function. build is reported into observer
 func FunctionCreate(
 	authConfig types.AuthConfig,
 	functionDTO quebicFaasTypes.FunctionDTO,
-	functionRunTime function_runtime.FunctionRunTime) (string, error) {
+	functionRunTime function_runtime.FunctionRunTime,
+	builder function_builder.Builder,
+	observer function_image.BuildObserver) (string, error) {
 
 	function := functionDTO.Function
 	options := functionDTO.Options
 
+	//existing image is deployed without building
+	if function.Image != "" {
+		log.Printf("%s uses image %s. skipping image build", function.GetID(), function.Image)
+		observer.Log("using image " + function.Image + ". skipping image build")
+		return function.Image, nil
+	}
+
 	buildContextLocation, err := function_create.CreateFunction(
 		function.Name,
 		functionDTO.SourceFile,
@@ -53,11 +73,15 @@ func FunctionCreate(
 		return "", err
 	}
 
-	imageID, err := function_image.FunctionImageBuild(
+	buildArgs := function_image.GetBuildArgs(function, functionRunTime.GetFunctionDockerFileContent())
+
+	imageID, err := builder.Build(
 		authConfig,
 		buildContextLocation,
 		function,
-		options.Publish)
+		buildArgs,
+		options.Publish,
+		observer)
 
 	//remove function dir
 	os.RemoveAll(function_common.GetFunctionDir(function.GetID()))
@@ -69,12 +93,13 @@ func FunctionCreate(
 //FunctionDeploy create-or-update function
 func FunctionDeploy(
 	appConfig mgrconfig.AppConfig,
+	db storage.Store,
 	deployment dep.Deployment,
 	messenger quebic_messenger.Messenger,
 	function *quebicFaasTypes.Function) (string, error) {
 
 	if function.Life.Awake != common.FunctionLifeAwakeTypeRequest {
-		return functionDeploy(appConfig, deployment, messenger, function)
+		return functionDeploy(appConfig, db, deployment, messenger, function)
 	}
 
 	//when awake type request
@@ -96,7 +121,7 @@ func FunctionDeploy(
 			}
 
 			//function is not running, deploy it
-			_, err = functionDeploy(appConfig, deployment, messenger, function)
+			_, err = functionDeploy(appConfig, db, deployment, messenger, function)
 			if err != nil {
 				log.Printf("function deploy failed : %s", err.Error())
 				return
@@ -229,18 +254,38 @@ func GetNewVersionEvent(function quebicFaasTypes.Function) string {
 	return common.EventNewVersionFunctionPrefix + function.GetID()
 }
 
+//FunctionImageDigest digest of the image in its registry. stored credentials of the registry are used
+func FunctionImageDigest(appConfig mgrconfig.AppConfig, db storage.Store, image string) (string, error) {
+
+	ref := registry.ParseImage(image)
+	if ref.IsDigest() {
+		return ref.Reference, nil
+	}
+
+	_, credentials, err := dao.GetRegistryCredentials(db, ref.Server, appConfig.DockerConfig.CredentialsKey)
+	if err != nil {
+		return "", err
+	}
+
+	return registry.ResolveDigest(image, credentials)
+
+}
+
 //functionDeploy callback
 func functionDeploy(
 	appConfig mgrconfig.AppConfig,
+	db storage.Store,
 	deployment dep.Deployment,
 	msg messenger.Messenger,
 	function *quebicFaasTypes.Function) (string, error) {
 
-	//validate runtime
-	if !common.RuntimeValidate(common.Runtime(function.Runtime)) {
+	//runtime is validated against runtime catalog when function is saved
+	if function.Runtime == "" {
 		return "", fmt.Errorf("runtime not match")
 	}
 
+	start := time.Now()
+
 	functionID := GetID(*function)
 	functionDeploymentID := GetDeploymentID(*function)
 	functionVersion := function.Version
@@ -248,6 +293,25 @@ func functionDeploy(
 	functionImage := function.DockerImageID
 	functionReplicas := function.Replicas
 
+	if appConfig.DockerConfig.VerifyImageDigest {
+		image, err := verifyImageDigest(appConfig, db, function)
+		if err != nil {
+			deployDuration.Observe(time.Since(start).Seconds(), functionID, "failed")
+			return "", err
+		}
+		functionImage = image
+	}
+
+	//image is pulled with the credentials of its registry
+	var imagePullSecrets []string
+	reg, err := dao.GetRegistry(db, registry.ParseImage(functionImage).Server)
+	if err != nil {
+		log.Printf("%s : unable to get registry of the image %v", functionDeploymentID, err)
+	}
+	if reg != nil {
+		imagePullSecrets = append(imagePullSecrets, reg.PullSecret)
+	}
+
 	//set accesskey
 	envkeys := prepareEnvKeys(appConfig, deployment, function)
 
@@ -265,26 +329,31 @@ func functionDeploy(
 	}
 
 	deploymentSpec := dep.Spec{
-		Name:            functionID,
-		DeploymentName:  functionDeploymentID,
-		Version:         functionVersion,
-		Dockerimage:     functionImage,
-		PortConfigs:     portConfigs,
-		Envkeys:         envkeys,
-		Replicas:        dep.Replicas(functionReplicas),
-		ImagePullPolicy: "IfNotPresent",
+		Name:             functionID,
+		DeploymentName:   functionDeploymentID,
+		Version:          functionVersion,
+		Dockerimage:      functionImage,
+		PortConfigs:      portConfigs,
+		Envkeys:          envkeys,
+		Replicas:         dep.Replicas(functionReplicas),
+		ImagePullPolicy:  "IfNotPresent",
+		ImagePullSecrets: imagePullSecrets,
 	}
 
-	err := deployment.CreateOrUpdateDeployment(deploymentSpec)
+	err = deployment.CreateOrUpdateDeployment(deploymentSpec)
 	if err != nil {
+		deployDuration.Observe(time.Since(start).Seconds(), functionID, "failed")
 		return "", err
 	}
 
 	_, err = deployment.CreateService(deploymentSpec)
 	if err != nil {
+		deployDuration.Observe(time.Since(start).Seconds(), functionID, "failed")
 		return "", err
 	}
 
+	deployDuration.Observe(time.Since(start).Seconds(), functionID, "success")
+
 	publishFunctionNewVersion(deployment, msg, *function)
 
 	log.Printf("%s : function is deployed", functionDeploymentID)
@@ -293,6 +362,38 @@ func functionDeploy(
 
 }
 
+//verifyImageDigest image is rolled out by the digest which is recorded when it is built.
+//image which is changed in the registry after that is refused
+func verifyImageDigest(
+	appConfig mgrconfig.AppConfig,
+	db storage.Store,
+	function *quebicFaasTypes.Function) (string, error) {
+
+	image := function.DockerImageID
+
+	digest, err := FunctionImageDigest(appConfig, db, image)
+	if err != nil {
+		return "", fmt.Errorf("image digest verification failed %v", err)
+	}
+
+	if function.ImageDigest == "" {
+
+		//functions which are built before verification is enabled
+		log.Printf("%s has no recorded image digest. %s is recorded", function.GetID(), digest)
+
+		err = dao.SetFunctionImageDigest(db, function, digest)
+		if err != nil {
+			return "", err
+		}
+
+	} else if function.ImageDigest != digest {
+		return "", fmt.Errorf("image digest verification failed. %s is %s in the registry, but %s is recorded when it is built", image, digest, function.ImageDigest)
+	}
+
+	return registry.WithDigest(image, digest), nil
+
+}
+
 func prepareEnvKeys(
 	appConfig mgrconfig.AppConfig,
 	deployment dep.Deployment,
@@ -319,12 +420,18 @@ func prepareEnvKeys(
 	envkeys[common.EnvKey_rabbitmq_management_username] = eventBusConfig.ManagementUserName
 	envkeys[common.EnvKey_rabbitmq_management_password] = eventBusConfig.ManagementPassword
 
+	//runtimes export their own spans using same exporter
+	envkeys[common.EnvKey_tracing_exporter] = appConfig.Tracing.Exporter
+	envkeys[common.EnvKey_tracing_otlpEndpoint] = appConfig.Tracing.OTLPEndpoint
+	envkeys[common.EnvKey_tracing_filePath] = appConfig.Tracing.FilePath
+
 	envkeys[common.EnvKey_eventConst_eventPrefixUserDefined] = common.EventPrefixUserDefined
 	envkeys[common.EnvKey_eventConst_eventLog] = common.EventRequestTracker
 	envkeys[common.EnvKey_eventConst_eventFunctionAwake] = GetFunctionAwakeEvent(*function)
 	envkeys[common.EnvKey_eventConst_eventDataFetch] = common.EventFunctionDataFetch
 	envkeys[common.EnvKey_eventConst_eventNewVersion] = GetNewVersionEvent(*function)
 	envkeys[common.EnvKey_eventConst_eventShutDownRequest] = common.EventShutDownRequest
+	envkeys[common.EnvKey_eventConst_eventFunctionMetrics] = common.EventFunctionMetrics
 
 	//events eg: e1,e2,e3,
 	eventsStr := ""
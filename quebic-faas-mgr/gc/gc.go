@@ -18,48 +18,111 @@ package gc
 import (
 	"fmt"
 	"log"
+	"quebic-faas/metrics"
 	"quebic-faas/quebic-faas-mgr/config"
+	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"sync"
+	"time"
 
 	dep "quebic-faas/quebic-faas-mgr/deployment"
-
-	bolt "github.com/coreos/bbolt"
 )
 
-//GC gargage-collector
+//interval between collections of the submitted jobs
+const gcInterval = time.Second * 10
+
+var gcJobs = metrics.NewCounter(
+	"quebic_mgr_gc_jobs_total",
+	"deployment shutdown jobs submitted into gc")
+
+var gcDeletions = metrics.NewCounter(
+	"quebic_mgr_gc_deployment_deletions_total",
+	"deployments deleted by gc",
+	"result")
+
+//GC gargage-collector.
+//jobs can be submitted to any manager replica. completed workers are counted in the storage.
+//deployments are deleted only by the replica which runs the gc (leader)
 type GC struct {
-	config               config.AppConfig
-	db                   *bolt.DB
-	deployment           dep.Deployment
-	completedWorkersPool map[string]int // deployment-id : completed workes count
+	config     config.AppConfig
+	db         storage.Store
+	deployment dep.Deployment
+	mutex      sync.Mutex
+	running    bool
 }
 
 //Init init
-func (gc *GC) Init(appConfig config.AppConfig, db *bolt.DB, deployment dep.Deployment) {
+func (gc *GC) Init(appConfig config.AppConfig, db storage.Store, deployment dep.Deployment) {
 	gc.config = appConfig
 	gc.db = db
 	gc.deployment = deployment
-	gc.completedWorkersPool = make(map[string]int)
 }
 
 //SubmitJob submit deployment shutdown job
 func (gc *GC) SubmitJob(deploymentID string) {
 
-	gc.assignWorker(deploymentID)
-	completedWorkersCount := gc.getCompletedWorkersCount(deploymentID)
+	gcJobs.Inc()
+
+	completedWorkersCount, err := dao.GCAssignWorker(gc.db, deploymentID)
+	if err != nil {
+		log.Printf("%v - unable to assign worker %v", deploymentID, err)
+		return
+	}
+
+	log.Printf("%v - %v workers completed task", deploymentID, completedWorkersCount)
+
+	//leader collects immediately. others leave it for the next collection of the leader
+	if gc.isRunning() {
+		gc.collect(deploymentID, completedWorkersCount)
+	}
+
+}
+
+//Run collect deployments of the submitted jobs until stop is closed
+func (gc *GC) Run(stop <-chan struct{}) {
+
+	gc.setRunning(true)
+	defer gc.setRunning(false)
+
+	log.Printf("gc started")
+
+	for {
+
+		completedWorkers, err := dao.GCGetCompletedWorkers(gc.db)
+		if err != nil {
+			log.Printf("gc unable to load submitted jobs %v", err)
+		}
+
+		for deploymentID, completedWorkersCount := range completedWorkers {
+			gc.collect(deploymentID, completedWorkersCount)
+		}
+
+		select {
+		case <-stop:
+			log.Printf("gc stopped")
+			return
+		case <-time.After(gcInterval):
+		}
+
+	}
+
+}
+
+func (gc *GC) collect(deploymentID string, completedWorkersCount int) {
+
 	replicasCount, err := gc.getReplicasCount(deploymentID)
 	if err != nil {
 		log.Printf("%v", err.Error())
 		return
 	}
 
-	log.Printf(
-		"%v - gc job submitted completedWorkersCount(%v), replicasCount(%v)",
-		deploymentID, completedWorkersCount,
-		replicasCount,
-	)
-
 	if completedWorkersCount >= replicasCount {
-		log.Printf("%v - deployment deleting", deploymentID)
+		log.Printf(
+			"%v - deployment deleting completedWorkersCount(%v), replicasCount(%v)",
+			deploymentID, completedWorkersCount,
+			replicasCount,
+		)
+		gc.releaseDeployment(deploymentID)
 		gc.deleteDeployment(deploymentID)
 	}
 
@@ -73,43 +136,35 @@ func (gc *GC) getReplicasCount(deploymentID string) (int, error) {
 	return int(details.Replicas), nil
 }
 
-func (gc *GC) assignWorker(deploymentID string) {
-	var workersCount int
-	if gc.isDeploymentAllreadyAssigned(deploymentID) {
-		workersCount = gc.getCompletedWorkersCount(deploymentID) + 1
-	} else {
-		workersCount = 1
-	}
-	gc.setCompletedWorkersCount(deploymentID, workersCount)
-	log.Printf("%v - %v workers completed task", deploymentID, workersCount)
-}
-
-func (gc *GC) isDeploymentAllreadyAssigned(deploymentID string) bool {
-	if gc.completedWorkersPool[deploymentID] == 0 {
-		return false
-	}
-	return true
-}
-
-func (gc *GC) getCompletedWorkersCount(deploymentID string) int {
-	return gc.completedWorkersPool[deploymentID]
+func (gc *GC) isRunning() bool {
+	gc.mutex.Lock()
+	defer gc.mutex.Unlock()
+	return gc.running
 }
 
-func (gc *GC) setCompletedWorkersCount(deploymentID string, cnt int) {
-	gc.completedWorkersPool[deploymentID] = cnt
+func (gc *GC) setRunning(running bool) {
+	gc.mutex.Lock()
+	defer gc.mutex.Unlock()
+	gc.running = running
 }
 
 func (gc *GC) deleteDeployment(deploymentID string) {
 	go func() {
 		err := gc.deployment.DeleteDeployment(deploymentID)
 		if err != nil {
+			gcDeletions.Inc("failed")
 			log.Printf("%v - unable to deleting deployment %v", deploymentID, err)
 			return
 		}
+		gcDeletions.Inc("success")
 		log.Printf("%v - deployment successfully deleted", deploymentID)
 	}()
 }
 
+//releaseDeployment before deleting. so an other collection does not delete it again
 func (gc *GC) releaseDeployment(deploymentID string) {
-	delete(gc.completedWorkersPool, deploymentID)
+	err := dao.GCReleaseDeployment(gc.db, deploymentID)
+	if err != nil {
+		log.Printf("%v - unable to release deployment %v", deploymentID, err)
+	}
 }
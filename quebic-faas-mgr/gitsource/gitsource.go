@@ -0,0 +1,288 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+//Package gitsource function sources which are fetched from git repositories.
+//git of the manager is used. local and bare repositories work the same way
+package gitsource
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"quebic-faas/types"
+	"regexp"
+	"strings"
+)
+
+//DefaultRef ref which is fetched when the source does not have a ref
+const DefaultRef = "HEAD"
+
+var commitPattern = regexp.MustCompile("^[0-9a-f]{7,40}$")
+
+//askPassScript answers username and password prompts of git from env
+const askPassScript = `#!/bin/sh
+case "$1" in
+Username*) printf '%s\n' "$QUEBIC_GIT_USERNAME" ;;
+*) printf '%s\n' "$QUEBIC_GIT_PASSWORD" ;;
+esac
+`
+
+//Credentials opened credentials of a git host
+type Credentials struct {
+	Username   string
+	Password   string
+	PrivateKey string
+}
+
+//Host git server host of the repository. empty for local repositories
+// eg: https://github.com/org/functions.git => github.com, git@github.com:org/functions.git => github.com
+func Host(repositoryURL string) string {
+
+	if strings.Contains(repositoryURL, "://") {
+
+		u, err := url.Parse(repositoryURL)
+		if err != nil || u.Scheme == "file" {
+			return ""
+		}
+
+		return strings.ToLower(u.Host)
+
+	}
+
+	//scp like ssh url. [user@]host:path
+	if i := strings.Index(repositoryURL, ":"); i > 0 && !strings.Contains(repositoryURL[:i], "/") {
+		host := repositoryURL[:i]
+		if j := strings.LastIndex(host, "@"); j >= 0 {
+			host = host[j+1:]
+		}
+		return strings.ToLower(host)
+	}
+
+	return ""
+
+}
+
+//isSSH ssh url or scp like url
+func isSSH(repositoryURL string) bool {
+	return strings.HasPrefix(repositoryURL, "ssh://") ||
+		(!strings.Contains(repositoryURL, "://") && Host(repositoryURL) != "")
+}
+
+//Validate git source of the function
+func Validate(source types.FunctionGitSource) []string {
+
+	var errors []string
+
+	if source.URL == "" {
+		errors = append(errors, "git url should not be empty")
+	}
+
+	if strings.HasPrefix(source.URL, "-") {
+		errors = append(errors, "git url is invalide")
+	}
+
+	if strings.HasPrefix(source.Ref, "-") || strings.ContainsAny(source.Ref, " ~^:?*[\\") {
+		errors = append(errors, "git ref is invalide")
+	}
+
+	if source.Path != "" {
+		cleaned := path.Clean("/" + source.Path)
+		if filepath.IsAbs(source.Path) || cleaned != "/"+strings.TrimSuffix(source.Path, "/") {
+			errors = append(errors, "git path should be a relative path inside the repository")
+		}
+	}
+
+	return errors
+
+}
+
+//PackageName file name of the package of the function source
+func PackageName(function string) string {
+	return function + ".tar"
+}
+
+//Fetch checkout ref of the repository into dir. returns commit of the checkout
+//git output is passed into logger
+func Fetch(source types.FunctionGitSource, credentials Credentials, dir string, logger func(line string)) (string, error) {
+
+	ref := source.Ref
+	if ref == "" {
+		ref = DefaultRef
+	}
+
+	env, release, err := prepareEnv(source.URL, credentials)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	err = runGit(env, "", logger, "init", "-q", dir)
+	if err != nil {
+		return "", err
+	}
+
+	//fetch only the ref. servers which do not allow fetching a commit are fetched fully
+	err = runGit(env, dir, logger, "fetch", "--depth", "1", source.URL, ref)
+	if err == nil {
+		err = runGit(env, dir, logger, "checkout", "-q", "--detach", "FETCH_HEAD")
+	} else if commitPattern.MatchString(ref) {
+		err = runGit(env, dir, logger, "fetch", source.URL, "+refs/heads/*:refs/remotes/origin/*", "+refs/tags/*:refs/tags/*")
+		if err == nil {
+			commit, parseErr := revParse(env, dir, ref+"^{commit}")
+			if parseErr != nil {
+				return "", fmt.Errorf("commit %s is not found in %s", ref, source.URL)
+			}
+			err = runGit(env, dir, logger, "checkout", "-q", "--detach", commit)
+		}
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch %s %s. %v", source.URL, ref, err)
+	}
+
+	commit, err := revParse(env, dir, "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("unable to read commit of %s %s %v", source.URL, ref, err)
+	}
+
+	return commit, nil
+
+}
+
+//revParse full commit of the revision
+func revParse(env []string, dir string, revision string) (string, error) {
+
+	output := &bytes.Buffer{}
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", revision)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = output
+
+	err := cmd.Run()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(output.String()), nil
+
+}
+
+//prepareEnv git never prompts. credentials are passed through env and files, so they are not visible in the process args
+//returned func removes the ssh key and the askpass helper
+func prepareEnv(repositoryURL string, credentials Credentials) ([]string, func(), error) {
+
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	release := func() {}
+
+	if isSSH(repositoryURL) {
+
+		sshCommand := "ssh -o BatchMode=yes -o StrictHostKeyChecking=accept-new"
+
+		if credentials.PrivateKey != "" {
+
+			keyDir, err := ioutil.TempDir("", "quebic-git-key-")
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to keep ssh key %v", err)
+			}
+			release = func() { os.RemoveAll(keyDir) }
+
+			keyFile := filepath.Join(keyDir, "id")
+			err = ioutil.WriteFile(keyFile, []byte(strings.TrimSpace(credentials.PrivateKey)+"\n"), 0600)
+			if err != nil {
+				release()
+				return nil, nil, fmt.Errorf("unable to keep ssh key %v", err)
+			}
+
+			sshCommand += " -o IdentitiesOnly=yes -i " + keyFile
+
+		}
+
+		return append(env, "GIT_SSH_COMMAND="+sshCommand), release, nil
+
+	}
+
+	//askpass works with any git version. http.extraHeader through GIT_CONFIG_COUNT requires git 2.31
+	if credentials.Password != "" {
+
+		askPassDir, err := ioutil.TempDir("", "quebic-git-askpass-")
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to keep git credentials %v", err)
+		}
+		release = func() { os.RemoveAll(askPassDir) }
+
+		askPassFile := filepath.Join(askPassDir, "askpass")
+		err = ioutil.WriteFile(askPassFile, []byte(askPassScript), 0700)
+		if err != nil {
+			release()
+			return nil, nil, fmt.Errorf("unable to keep git credentials %v", err)
+		}
+
+		env = append(env,
+			"GIT_ASKPASS="+askPassFile,
+			"QUEBIC_GIT_USERNAME="+credentials.Username,
+			"QUEBIC_GIT_PASSWORD="+credentials.Password)
+
+	}
+
+	return env, release, nil
+
+}
+
+//runGit run git. output is passed into logger. last output line is the cause of the failure
+func runGit(env []string, dir string, logger func(line string), args ...string) error {
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = env
+
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+
+	err := cmd.Start()
+	if err != nil {
+		return fmt.Errorf("unable to run git %v", err)
+	}
+
+	lastLine := ""
+	done := make(chan struct{})
+
+	go func() {
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			lastLine = scanner.Text()
+			logger(lastLine)
+		}
+		io.Copy(ioutil.Discard, reader)
+		close(done)
+	}()
+
+	err = cmd.Wait()
+	writer.Close()
+	<-done
+
+	if err != nil {
+		return fmt.Errorf("git %s failed %v %s", args[0], err, lastLine)
+	}
+
+	return nil
+
+}
@@ -0,0 +1,156 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package gitsource
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//Package tar sub directory of the checkout into target. it is the artifact of the function
+//.git of the checkout is not added. entries are relative to the sub directory
+func Package(dir string, subPath string, target string) error {
+
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return fmt.Errorf("unable to resolve the repository %v", err)
+	}
+
+	//path may be a symlink of the repository. it is resolved and has to stay inside the repository
+	root, err := filepath.EvalSymlinks(filepath.Join(dir, filepath.FromSlash(subPath)))
+	if err != nil {
+		return fmt.Errorf("git path %s is not found in the repository", subPath)
+	}
+
+	rel, err := filepath.Rel(dir, root)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("git path %s is outside of the repository", subPath)
+	}
+
+	if rel == ".git" || strings.HasPrefix(rel, ".git"+string(filepath.Separator)) {
+		return fmt.Errorf("git path %s is not found in the repository", subPath)
+	}
+
+	info, err := os.Stat(root)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("git path %s is not found in the repository", subPath)
+	}
+
+	targetFile, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("unable to create function package %v", err)
+	}
+	defer targetFile.Close()
+
+	tw := tar.NewWriter(targetFile)
+
+	err = filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(root, filePath)
+		if err != nil || name == "." {
+			return err
+		}
+		name = filepath.ToSlash(name)
+
+		if info.IsDir() && filePath == filepath.Join(dir, ".git") {
+			return filepath.SkipDir
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(filePath)
+			if err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		header.Name = name
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		//same commit gives the same package, so the artifact digest only depends on the content
+		header.ModTime = time.Unix(0, 0)
+		header.AccessTime = time.Time{}
+		header.ChangeTime = time.Time{}
+		header.Uid = 0
+		header.Gid = 0
+		header.Uname = ""
+		header.Gname = ""
+		header.Mode = packageMode(info)
+
+		err = tw.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+
+		return err
+
+	})
+
+	if err != nil {
+		return fmt.Errorf("unable to package %s %v", strings.TrimPrefix(subPath, "/"), err)
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return fmt.Errorf("unable to package %s %v", subPath, err)
+	}
+
+	return nil
+
+}
+
+//packageMode permissions of the checkout depend on the umask. only the executable bit is kept
+func packageMode(info os.FileInfo) int64 {
+
+	switch {
+	case info.IsDir():
+		return 0755
+	case info.Mode()&os.ModeSymlink != 0:
+		return 0777
+	case info.Mode()&0100 != 0:
+		return 0755
+	default:
+		return 0644
+	}
+
+}
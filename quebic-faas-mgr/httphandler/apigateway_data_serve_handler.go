@@ -53,6 +53,20 @@ func (httphandler *Httphandler) ApigatewayDataServe(router *mux.Router) {
 			return
 		}
 
+		//grpc-services
+		var grpcServices []types.GRPCService
+		err = dao.GetAll(db, &types.GRPCService{}, func(k, v []byte) error {
+
+			grpcService := types.GRPCService{}
+			json.Unmarshal(v, &grpcService)
+			grpcServices = append(grpcServices, grpcService)
+			return nil
+		})
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
 		//manager-components
 		allowComponents := [1]string{
 			common.ComponentEventBus,
@@ -87,8 +101,13 @@ func (httphandler *Httphandler) ApigatewayDataServe(router *mux.Router) {
 			resources = make([]types.Resource, 0)
 		}
 
+		if grpcServices == nil {
+			grpcServices = make([]types.GRPCService, 0)
+		}
+
 		//assign data
 		apigatewayData.Resources = resources
+		apigatewayData.GRPCServices = grpcServices
 		apigatewayData.ManagerComponents = components
 
 		writeResponse(w, apigatewayData, http.StatusOK)
@@ -0,0 +1,105 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"net/http"
+	"quebic-faas/auth"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/types"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const defaultAuditLimit = 50
+
+//AuditHandler entity change history handler
+func (httphandler *Httphandler) AuditHandler(router *mux.Router) {
+
+	authConfig := httphandler.config.Auth
+	db := httphandler.db
+
+	router.HandleFunc("/audit", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		filter, limit, errors := prepareAuditFilter(r)
+		if len(errors) > 0 {
+			status := http.StatusBadRequest
+			writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: errors, Status: status}, status)
+			return
+		}
+
+		page, err := dao.GetAuditRecords(db, filter, r.FormValue("cursor"), limit)
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeResponse(w, page, http.StatusOK)
+
+	}, auth.RoleAdmin, authConfig)).Methods("GET")
+
+}
+
+func prepareAuditFilter(r *http.Request) (dao.AuditFilter, int, []string) {
+
+	var errors []string
+
+	filter := dao.AuditFilter{
+		Entity: r.FormValue("entity"),
+		ID:     r.FormValue("id"),
+		Actor:  r.FormValue("actor"),
+	}
+
+	if filter.Entity != "" {
+		if _, err := dao.ValidateBackupEntities([]string{filter.Entity}); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	if filter.ID != "" && filter.Entity == "" {
+		errors = append(errors, "entity should be provided with id")
+	}
+
+	var err error
+
+	if from := r.FormValue("from"); from != "" {
+		filter.From, err = time.ParseInLocation(common.DefaultTimeLayout, from, time.Local)
+		if err != nil {
+			errors = append(errors, "from should be in "+common.DefaultTimeLayout+" format")
+		}
+	}
+
+	if to := r.FormValue("to"); to != "" {
+		filter.To, err = time.ParseInLocation(common.DefaultTimeLayout, to, time.Local)
+		if err != nil {
+			errors = append(errors, "to should be in "+common.DefaultTimeLayout+" format")
+		}
+	}
+
+	limit := defaultAuditLimit
+	if l := r.FormValue("limit"); l != "" {
+		v, err := strconv.Atoi(l)
+		if err != nil || v <= 0 {
+			errors = append(errors, "limit should be a positive number")
+		}
+		limit = v
+	}
+
+	return filter, limit, errors
+
+}
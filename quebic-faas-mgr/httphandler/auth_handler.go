@@ -23,9 +23,9 @@ import (
 	"quebic-faas/common"
 	"quebic-faas/quebic-faas-mgr/config"
 	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/storage"
 	"quebic-faas/types"
 
-	bolt "github.com/coreos/bbolt"
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/gorilla/context"
 	"github.com/gorilla/mux"
@@ -120,7 +120,7 @@ func (httphandler *Httphandler) AuthHandler(router *mux.Router) {
 			return
 		}
 
-		err = dao.AddUser(db, user)
+		err = dao.AddUser(db, user, getAuthUserName(r))
 		if err != nil {
 			log.Printf("user save failed. err : %v", err)
 			makeErrorResponse(w, http.StatusBadRequest, fmt.Errorf("User save failed"))
@@ -153,8 +153,13 @@ func (httphandler *Httphandler) AuthHandler(router *mux.Router) {
 		}
 
 		savedUser.Firstname = user.Firstname
+		savedUser.Revision = user.Revision
 
-		err = dao.SaveUser(db, savedUser)
+		err = dao.UpdateUser(db, savedUser, authUserName)
+		if dao.IsRevisionConflict(err) {
+			makeConflictResponse(w, err)
+			return
+		}
 		if err != nil {
 			log.Printf("user save failed. err : %v", err)
 			makeErrorResponse(w, http.StatusBadRequest, fmt.Errorf("User save failed"))
@@ -186,7 +191,7 @@ func (httphandler *Httphandler) AuthHandler(router *mux.Router) {
 
 		savedUser.Password = user.Password
 
-		err = dao.SaveUser(db, savedUser)
+		err = dao.UpdateUser(db, savedUser, authUserName)
 		if err != nil {
 			log.Printf("user save failed. err : %v", err)
 			makeErrorResponse(w, http.StatusBadRequest, fmt.Errorf("User save failed"))
@@ -278,7 +283,7 @@ func getAuthUserName(r *http.Request) string {
 	return currentAuthContext[auth.ClaimsUsername].(string)
 }
 
-func getUser(db *bolt.DB, authUserName string) (*types.User, error) {
+func getUser(db storage.Store, authUserName string) (*types.User, error) {
 	savedUser := &types.User{}
 	err := dao.GetByID(db, &types.User{Username: authUserName}, func(savedUserINBytes []byte) error {
 
@@ -296,7 +301,7 @@ func getUser(db *bolt.DB, authUserName string) (*types.User, error) {
 
 }
 
-func validateUser(db *bolt.DB, user *types.User, isCreate bool) []string {
+func validateUser(db storage.Store, user *types.User, isCreate bool) []string {
 
 	var errors []string
 
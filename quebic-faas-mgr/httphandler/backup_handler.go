@@ -0,0 +1,163 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"quebic-faas/auth"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/function/function_util"
+	"quebic-faas/types"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+//BackupHandler manager backup and restore handler
+func (httphandler *Httphandler) BackupHandler(router *mux.Router) {
+
+	authConfig := httphandler.config.Auth
+	db := httphandler.db
+
+	router.HandleFunc("/manager/backup", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		entities, err := dao.ValidateBackupEntities(prepareBackupEntities(r))
+		if err != nil {
+			status := http.StatusBadRequest
+			writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: []string{err.Error()}, Status: status}, status)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", "attachment; filename=quebic-faas-mgr-backup.json")
+
+		//headers are already sent. failure is visible to client as truncated stream
+		err = dao.WriteBackup(db, entities, w)
+		if err != nil {
+			log.Printf("manager backup failed : %v", err)
+		}
+
+	}, auth.RoleAdmin, authConfig)).Methods("GET")
+
+	router.HandleFunc("/manager/restore", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		entities, err := dao.ValidateBackupEntities(prepareBackupEntities(r))
+		if err != nil {
+			status := http.StatusBadRequest
+			writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: []string{err.Error()}, Status: status}, status)
+			return
+		}
+
+		dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+		redeploy, _ := strconv.ParseBool(r.URL.Query().Get("redeploy"))
+
+		report, err := dao.RestoreBackup(db, entities, r.Body, dryRun)
+		if err != nil {
+			makeErrorResponse(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if !dryRun {
+			httphandler.reloadRestoredRoutes(report)
+		}
+
+		if !dryRun && redeploy {
+			httphandler.redeployFunctions(report)
+		}
+
+		writeResponse(w, report, http.StatusOK)
+
+	}, auth.RoleAdmin, authConfig)).Methods("POST")
+
+}
+
+//prepareBackupEntities entities=functions,routes
+func prepareBackupEntities(r *http.Request) []string {
+
+	var entities []string
+
+	for _, entity := range strings.Split(r.URL.Query().Get("entities"), ",") {
+		entity = strings.TrimSpace(entity)
+		if entity != "" {
+			entities = append(entities, entity)
+		}
+	}
+
+	return entities
+
+}
+
+//reloadRestoredRoutes running apigateways reload routes which were changed by restore
+func (httphandler *Httphandler) reloadRestoredRoutes(report *types.RestoreReport) {
+
+	for _, diff := range report.Entities {
+
+		var route func(id string) types.Entity
+		switch diff.Entity {
+		case "routes":
+			route = func(id string) types.Entity { return &types.Resource{ID: id} }
+		case "grpc-services":
+			route = func(id string) types.Entity { return &types.GRPCService{ID: id} }
+		default:
+			continue
+		}
+
+		for _, keys := range [][]string{diff.Added, diff.Updated, diff.Removed} {
+			for _, id := range keys {
+				reloadAPIGatewayRoutes(httphandler.messenger, route(id))
+			}
+		}
+
+	}
+
+}
+
+//redeployFunctions create-or-update deployments of all saved functions
+func (httphandler *Httphandler) redeployFunctions(report *types.RestoreReport) {
+
+	report.Redeployed = []string{}
+	report.RedeployFailed = make(map[string]string)
+
+	var functions []*types.Function
+	dao.GetAll(httphandler.db, &types.Function{}, func(k, v []byte) error {
+		function := &types.Function{}
+		json.Unmarshal(v, function)
+		functions = append(functions, function)
+		return nil
+	})
+
+	//deploy outside of the read transaction
+	for _, function := range functions {
+
+		_, err := function_util.FunctionDeploy(
+			httphandler.config,
+			httphandler.db,
+			httphandler.deployment,
+			httphandler.messenger,
+			function)
+		if err != nil {
+			report.RedeployFailed[function.GetID()] = err.Error()
+			continue
+		}
+
+		report.Redeployed = append(report.Redeployed, function.GetID())
+
+	}
+
+}
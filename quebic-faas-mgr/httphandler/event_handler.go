@@ -20,10 +20,10 @@ import (
 	"quebic-faas/auth"
 	"quebic-faas/common"
 	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/storage"
 	"quebic-faas/types"
 	"strings"
 
-	bolt "github.com/coreos/bbolt"
 	"github.com/gorilla/mux"
 )
 
@@ -63,7 +63,7 @@ func (httphandler *Httphandler) EventHandler(router *mux.Router) {
 
 }
 
-func createEventFromEventID(db *bolt.DB, eventID string) (*types.Event, []string) {
+func createEventFromEventID(db storage.Store, eventID string) (*types.Event, []string) {
 
 	event := extractEventFromEventID(eventID)
 
@@ -77,13 +77,14 @@ func createEventFromEventID(db *bolt.DB, eventID string) (*types.Event, []string
 
 	processEvent(event)
 
-	dao.Add(db, event)
+	//events are created implicitly when they are referenced
+	dao.Add(db, event, dao.ActorSystem)
 
 	return event, nil
 
 }
 
-func getAllEvents(w http.ResponseWriter, r *http.Request, db *bolt.DB, entity types.Entity) {
+func getAllEvents(w http.ResponseWriter, r *http.Request, db storage.Store, entity types.Entity) {
 
 	var events []types.Event
 	err := dao.GetAll(db, entity, func(k, v []byte) error {
@@ -154,7 +155,7 @@ func processEvent(event *types.Event) {
 /*
 	name = event.Group + event.Name
 */
-func checkEventGroupAndNameIsValide(db *bolt.DB, name string) bool {
+func checkEventGroupAndNameIsValide(db storage.Store, name string) bool {
 
 	id := common.EventPrefixUserDefined + common.EventJOIN + name
 
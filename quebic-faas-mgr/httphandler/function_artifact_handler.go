@@ -0,0 +1,100 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"quebic-faas/auth"
+	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/function/function_artifact"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+const artifactDigestHeader string = "X-Quebic-Artifact-Digest"
+
+//FunctionArtifactHandler function artifacts handler
+//artifact of any function version can be downloaded for audits
+func (httphandler *Httphandler) FunctionArtifactHandler(router *mux.Router) {
+
+	db := httphandler.db
+	appConfig := httphandler.config
+	authConfig := appConfig.Auth
+	store := function_artifact.Store{Dir: appConfig.Artifacts.Path}
+
+	router.HandleFunc("/functions/{name}/artifacts", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		artifacts, err := dao.GetFunctionArtifacts(db, mux.Vars(r)["name"])
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeResponse(w, artifacts, http.StatusOK)
+
+	}, auth.RoleAny, authConfig)).Methods("GET")
+
+	router.HandleFunc("/functions/{name}/artifacts/{version}", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		vars := mux.Vars(r)
+
+		artifact, err := dao.GetFunctionArtifact(db, vars["name"], vars["version"])
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if artifact == nil {
+			makeErrorResponse(w, http.StatusNotFound, fmt.Errorf("resource not found"))
+			return
+		}
+
+		//stored content must still match the digest recorded for the version
+		err = store.Verify(artifact.Digest)
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		file, err := store.Open(artifact.Digest)
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+		defer file.Close()
+
+		fileName := filepath.Base(artifact.FileName)
+		if artifact.FileName == "" {
+			fileName = artifact.Function + "-" + artifact.Version
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", "attachment; filename="+strconv.Quote(fileName))
+		w.Header().Set("Content-Length", strconv.FormatInt(artifact.Size, 10))
+		w.Header().Set(artifactDigestHeader, artifact.Digest)
+
+		_, err = io.Copy(w, file)
+		if err != nil {
+			log.Printf("function %s artifact %s download failed : %v", artifact.Function, artifact.Version, err)
+		}
+
+	}, auth.RoleAny, authConfig)).Methods("GET")
+
+}
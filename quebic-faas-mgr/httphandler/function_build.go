@@ -0,0 +1,177 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+//functionBuildStream streams states and build output of the function job to the client when function is saved with stream=true
+//response is started with the first job message, so failures before the job is accepted are replied as usual
+type functionBuildStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	encoder *json.Encoder
+	enabled bool
+	started bool
+}
+
+func newFunctionBuildStream(w http.ResponseWriter, r *http.Request) *functionBuildStream {
+
+	stream := &functionBuildStream{w: w}
+
+	if r.FormValue(common.FunctionSaveParam_STREAM) != "true" {
+		return stream
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Printf("streaming is not supported. job is not streamed")
+		return stream
+	}
+
+	stream.flusher = flusher
+	stream.encoder = json.NewEncoder(w)
+	stream.enabled = true
+
+	return stream
+
+}
+
+//send write errors are ignored. job keeps running when the client is gone
+func (stream *functionBuildStream) send(message types.FunctionBuildMessage) {
+
+	if !stream.enabled {
+		return
+	}
+
+	if !stream.started {
+		stream.w.Header().Set("Content-Type", common.FunctionBuildContentType)
+		stream.w.WriteHeader(http.StatusOK)
+		stream.started = true
+	}
+
+	stream.encoder.Encode(message)
+	stream.flusher.Flush()
+
+}
+
+//log send a build output line
+func (stream *functionBuildStream) log(job types.Job, line string) {
+	stream.send(types.FunctionBuildMessage{JobID: job.ID, BuildID: job.BuildID, Stream: line})
+}
+
+//state send the state which job moved into. finished job is the last message of the stream
+func (stream *functionBuildStream) state(job types.Job) {
+
+	message := types.FunctionBuildMessage{JobID: job.ID, BuildID: job.BuildID, State: job.State}
+	if job.IsFinished() {
+		message.Job = &job
+	}
+
+	stream.send(message)
+
+}
+
+//functionBuildJob image build of a function
+//build output is kept on the function, so it can be inspected after the build
+type functionBuildJob struct {
+	db       storage.Store
+	function *types.Function
+	job      *jobTracker
+	build    types.FunctionBuild
+}
+
+func startFunctionBuild(db storage.Store, function *types.Function, job *jobTracker) (*functionBuildJob, error) {
+
+	buildID, err := uuid.NewV4()
+	if err != nil {
+		return nil, fmt.Errorf("unable to assign build id %v", err)
+	}
+
+	buildJob := &functionBuildJob{
+		db:       db,
+		function: function,
+		job:      job,
+		build: types.FunctionBuild{
+			ID:        buildID.String(),
+			Status:    common.FunctionBuildStatusRunning,
+			StartedAt: common.CurrentTime(),
+		},
+	}
+
+	err = dao.SetFunctionBuild(db, function, buildJob.build)
+	if err != nil {
+		return nil, err
+	}
+
+	entityLog := types.EntityLog{State: common.LogStateDockerImageCreating, Message: buildJob.build.ID}
+	dao.AddFunctionLog(db, function, entityLog, common.KubeStatusFalse)
+
+	job.setBuildID(buildJob.build.ID)
+
+	log.Printf("function %s build %s started", function.GetID(), buildJob.build.ID)
+
+	return buildJob, nil
+
+}
+
+//Log keep build output line and pass it into the job
+func (buildJob *functionBuildJob) Log(line string) {
+
+	buildJob.build.Logs = append(buildJob.build.Logs, line)
+	if len(buildJob.build.Logs) > common.FunctionBuildMaxLogLines {
+		buildJob.build.Logs = buildJob.build.Logs[len(buildJob.build.Logs)-common.FunctionBuildMaxLogLines:]
+	}
+
+	buildJob.job.log(line)
+
+}
+
+//Publishing image is going to be pushed
+func (buildJob *functionBuildJob) Publishing() {
+	buildJob.job.setState(common.JobStatePushing)
+}
+
+//finish save build result into the function
+func (buildJob *functionBuildJob) finish(image string, buildErr error) {
+
+	buildJob.build.FinishedAt = common.CurrentTime()
+
+	if buildErr != nil {
+		buildJob.build.Status = common.FunctionBuildStatusFailed
+		buildJob.build.Error = buildErr.Error()
+	} else {
+		buildJob.build.Status = common.FunctionBuildStatusSucceeded
+		buildJob.build.Image = image
+	}
+
+	err := dao.SetFunctionBuild(buildJob.db, buildJob.function, buildJob.build)
+	if err != nil {
+		log.Printf("function %s build %s save failed %v", buildJob.function.GetID(), buildJob.build.ID, err)
+	}
+
+	log.Printf("function %s build %s %s", buildJob.function.GetID(), buildJob.build.ID, buildJob.build.Status)
+
+}
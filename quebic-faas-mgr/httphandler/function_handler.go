@@ -17,25 +17,30 @@ package httphandler
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"path/filepath"
 	"quebic-faas/auth"
 	"quebic-faas/common"
 	quebic_messenger "quebic-faas/messenger"
 	"quebic-faas/quebic-faas-mgr/config"
 	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/function/function_artifact"
+	"quebic-faas/quebic-faas-mgr/function/function_builder"
 	"quebic-faas/quebic-faas-mgr/function/function_runtime"
-	"quebic-faas/quebic-faas-mgr/function/function_runtime/function_java_8_runtime"
-	"quebic-faas/quebic-faas-mgr/function/function_runtime/function_nodejs_runtime"
-	"quebic-faas/quebic-faas-mgr/function/function_runtime/function_python_2_7_runtime"
-	"quebic-faas/quebic-faas-mgr/function/function_runtime/function_python_3_6_runtime"
+	"quebic-faas/quebic-faas-mgr/function/function_runtime/runtime_catalog"
 	"quebic-faas/quebic-faas-mgr/function/function_util"
+	"quebic-faas/quebic-faas-mgr/gitsource"
+	"quebic-faas/quebic-faas-mgr/registry"
+	"quebic-faas/quebic-faas-mgr/storage"
 	"quebic-faas/types"
 	"strings"
 	"time"
 
 	dep "quebic-faas/quebic-faas-mgr/deployment"
 
-	bolt "github.com/coreos/bbolt"
 	"github.com/gorilla/mux"
 	uuid "github.com/satori/go.uuid"
 
@@ -50,6 +55,7 @@ func (httphandler *Httphandler) FunctionHandler(router *mux.Router) {
 	authConfig := appConfig.Auth
 	deployment := httphandler.deployment
 	messenger := httphandler.messenger
+	builder := httphandler.builder
 
 	router.HandleFunc("/functions", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
 
@@ -78,7 +84,7 @@ func (httphandler *Httphandler) FunctionHandler(router *mux.Router) {
 			return
 		}
 
-		saveFunctionDTO(w, r, db, functionDTO, appConfig, deployment, messenger, true)
+		saveFunctionDTO(w, r, db, functionDTO, appConfig, deployment, messenger, builder, true)
 
 	}, auth.RoleAny, authConfig)).Methods("POST")
 
@@ -91,7 +97,7 @@ func (httphandler *Httphandler) FunctionHandler(router *mux.Router) {
 			return
 		}
 
-		saveFunctionDTO(w, r, db, functionDTO, appConfig, deployment, messenger, false)
+		saveFunctionDTO(w, r, db, functionDTO, appConfig, deployment, messenger, builder, false)
 
 	}, auth.RoleAny, authConfig)).Methods("PUT")
 
@@ -123,7 +129,16 @@ func (httphandler *Httphandler) FunctionHandler(router *mux.Router) {
 
 		function.Version = requestVersion
 
-		err = dao.Update(db, function)
+		//version is rolled out with the image which is built from its artifact
+		artifact, err := dao.GetFunctionArtifact(db, function.GetID(), requestVersion)
+		if err == nil && artifact != nil && artifact.Image != "" {
+			function.DockerImageID = artifact.Image
+			function.ImageDigest = artifact.ImageDigest
+			function.ArtifactDigest = artifact.Digest
+			function.SourceCommit = artifact.Commit
+		}
+
+		err = dao.Update(db, function, getAuthUserName(r))
 		if err != nil {
 			makeErrorResponse(w, http.StatusInternalServerError, err)
 			return
@@ -131,6 +146,7 @@ func (httphandler *Httphandler) FunctionHandler(router *mux.Router) {
 
 		_, err = function_util.FunctionDeploy(
 			appConfig,
+			db,
 			deployment,
 			messenger,
 			function)
@@ -171,7 +187,7 @@ func (httphandler *Httphandler) FunctionHandler(router *mux.Router) {
 
 		function.Replicas = requestReplicas
 
-		err = dao.Update(db, function)
+		err = dao.Update(db, function, getAuthUserName(r))
 		if err != nil {
 			makeErrorResponse(w, http.StatusInternalServerError, err)
 			return
@@ -179,6 +195,7 @@ func (httphandler *Httphandler) FunctionHandler(router *mux.Router) {
 
 		_, err = function_util.FunctionDeploy(
 			appConfig,
+			db,
 			deployment,
 			messenger,
 			function)
@@ -260,7 +277,7 @@ func (httphandler *Httphandler) FunctionHandler(router *mux.Router) {
 			return
 		}
 
-		err = dao.Delete(db, function)
+		err = dao.Delete(db, function, getAuthUserName(r))
 		if err != nil {
 			makeErrorResponse(w, http.StatusInternalServerError, err)
 			return
@@ -279,11 +296,6 @@ func (httphandler *Httphandler) FunctionHandler(router *mux.Router) {
 
 	router.HandleFunc("/function_containers/logs", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
 
-		/*if deployment.DeploymentType() != config.Deployment_Docker {
-			makeErrorResponse(w, http.StatusBadRequest, fmt.Errorf("funtion-logs access only allow for docker deployment mode"))
-			return
-		}
-
 		logDTO := &types.FunctionContainerLogDTO{}
 		err := processRequest(r, logDTO)
 		if err != nil {
@@ -298,29 +310,51 @@ func (httphandler *Httphandler) FunctionHandler(router *mux.Router) {
 			return
 		}
 
-		options := types.FunctionContainerLogOptions{
-			Details:    logDTO.Options.Details,
-			Follow:     logDTO.Options.Follow,
-			ShowStderr: logDTO.Options.ShowStderr,
-			ShowStdout: logDTO.Options.ShowStdout,
-			Since:      logDTO.Options.Since,
-			Tail:       logDTO.Options.Tail,
-			Timestamps: logDTO.Options.Timestamps,
-			Until:      logDTO.Options.Until,
-		}
+		streamFunctionLogs(w, r, deployment, logDTO.Function, logDTO.Options)
 
-		functionService := function_util.GetServiceID(logDTO.Function.Name)
-		logs, err := deployment.LogsByName(functionService, options)
-		if err != nil {
-			makeErrorResponse(w, http.StatusInternalServerError, err)
-			return
-		}
+	}, auth.RoleAny, authConfig)).Methods("POST")
+
+}
 
-		writeResponse(w, logs, 200)*/
+//streamFunctionLogs write logs of all replicas as one interleaved stream. each line is prefixed with replica
+func streamFunctionLogs(
+	w http.ResponseWriter,
+	r *http.Request,
+	deployment dep.Deployment,
+	function types.Function,
+	options types.FunctionContainerLogOptions) {
 
-		writeResponse(w, "not-implemented", 200)
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		makeErrorResponse(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
 
-	}, auth.RoleAny, authConfig)).Methods("POST")
+	done := make(chan struct{})
+	defer close(done)
+
+	lines, err := dep.AggregateLogs(deployment, function_util.GetDeploymentID(function), options, done)
+	if err != nil {
+		makeErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "[%s] %s\n", line.Container, line.Line)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
 
 }
 
@@ -344,7 +378,11 @@ func processFunctionSaveReqest(r *http.Request, functionDTO *types.FunctionDTO)
 	}
 
 	//Source
+	//not required when function is deployed from an existing image
 	sourceFile, sourceFileHandler, err := r.FormFile(fieldSource)
+	if err == http.ErrMissingFile {
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("unable to load %s file in request", fieldSource)
 	}
@@ -358,7 +396,7 @@ func processFunctionSaveReqest(r *http.Request, functionDTO *types.FunctionDTO)
 func getAllFunctions(
 	w http.ResponseWriter,
 	r *http.Request,
-	db *bolt.DB,
+	db storage.Store,
 	entity types.Entity,
 	appConfig config.AppConfig,
 	deployment dep.Deployment,
@@ -395,7 +433,7 @@ func getAllFunctions(
 
 }
 
-func getFunctionByID(db *bolt.DB, function *types.Function) error {
+func getFunctionByID(db storage.Store, function *types.Function) error {
 
 	err := dao.GetByID(db, function, func(savedObj []byte) error {
 
@@ -419,11 +457,12 @@ func getFunctionByID(db *bolt.DB, function *types.Function) error {
 func saveFunctionDTO(
 	w http.ResponseWriter,
 	r *http.Request,
-	db *bolt.DB,
+	db storage.Store,
 	functionDTO *types.FunctionDTO,
 	appConfig config.AppConfig,
 	deployment dep.Deployment,
 	messenger quebic_messenger.Messenger,
+	builder function_builder.Builder,
 	isCreate bool) {
 
 	function := &functionDTO.Function
@@ -441,14 +480,112 @@ func saveFunctionDTO(
 		return
 	}
 
-	err := saveFunction(db, functionDTO, appConfig, isCreate)
+	actor := getAuthUserName(r)
+
+	//uploaded source is removed when the request is finished. it is kept in the artifact store
+	artifactStore := function_artifact.Store{Dir: appConfig.Artifacts.Path}
+	artifact, releaseSource, err := storeFunctionSource(artifactStore, functionDTO, actor)
+	if err != nil {
+		makeErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = saveFunction(db, functionDTO, isCreate, actor)
+	if dao.IsRevisionConflict(err) {
+		releaseSource()
+		makeConflictResponse(w, err)
+		return
+	}
 	if err != nil {
+		releaseSource()
 		makeErrorResponse(w, http.StatusInternalServerError, err)
 		return
 	}
 
+	//version is linked to the artifact which produced it
+	if artifact != nil {
+		err = dao.SaveFunctionArtifact(db, artifact, actor)
+		if err != nil {
+			releaseSource()
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	jobKind := common.JobKindFunctionUpdate
+	if isCreate {
+		jobKind = common.JobKindFunctionCreate
+	}
+
+	stream := newFunctionBuildStream(w, r)
+
+	job, err := newJobTracker(db, jobKind, function.GetID(), actor, stream)
+	if err != nil {
+		releaseSource()
+		makeErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	process := func() {
+		defer releaseSource()
+		processFunctionJob(job, db, functionDTO, appConfig, deployment, messenger, builder, isCreate, actor)
+	}
+
+	//job is processed within the request, so its progress is streamed until it is finished
+	if stream.enabled {
+		stream.state(job.job)
+		process()
+		return
+	}
+
+	go process()
+
+	writeResponse(w, job.job, http.StatusAccepted)
+
+}
+
+//processFunctionJob build, deploy and route the saved function
+func processFunctionJob(
+	job *jobTracker,
+	db storage.Store,
+	functionDTO *types.FunctionDTO,
+	appConfig config.AppConfig,
+	deployment dep.Deployment,
+	messenger quebic_messenger.Messenger,
+	builder function_builder.Builder,
+	isCreate bool,
+	actor string) {
+
+	function := &functionDTO.Function
+	route := &functionDTO.Route
+
+	//git source is fetched by the job. uploaded source takes precedence
+	if function.Git != nil && functionDTO.SourceFile.File == nil {
+
+		job.setState(common.JobStateFetching)
+
+		releaseGitSource, err := fetchFunctionGitSource(db, appConfig, functionDTO, job, actor)
+		if err != nil {
+			job.fail(err)
+			return
+		}
+		defer releaseGitSource()
+
+	}
+
+	job.setState(common.JobStateBuilding)
+
+	err := postProcessFunction(db, appConfig, builder, functionDTO, job)
+	if err != nil {
+		job.fail(err)
+		return
+	}
+
+	job.setState(common.JobStateDeploying)
+
 	_, err = function_util.FunctionDeploy(
 		appConfig,
+		db,
 		deployment,
 		messenger,
 		function)
@@ -457,17 +594,144 @@ func saveFunctionDTO(
 		entityLog := types.EntityLog{State: common.LogStateDeploymentFailed, Message: err.Error()}
 		dao.AddFunctionLog(db, function, entityLog, common.KubeStatusFalse)
 
-		makeErrorResponse(w, http.StatusInternalServerError, err)
+		job.fail(err)
 		return
 	}
 
-	err = saveRoute(db, route, appConfig, deployment, messenger, isCreate)
+	err = saveRoute(db, route, messenger, isCreate, actor)
 	if err != nil {
-		makeErrorResponse(w, http.StatusInternalServerError, err)
+		job.fail(err)
 		return
 	}
 
-	writeResponse(w, functionDTO, 200)
+	job.setState(common.JobStateReady)
+
+}
+
+//fetchFunctionGitSource fetch git source of the function. sub directory is packaged as .tar and kept in the artifact store
+//commit is recorded on the function and on the artifact of the version. returned func closes the stored package
+func fetchFunctionGitSource(
+	db storage.Store,
+	appConfig config.AppConfig,
+	functionDTO *types.FunctionDTO,
+	job *jobTracker,
+	actor string) (func(), error) {
+
+	function := &functionDTO.Function
+	source := *function.Git
+
+	//local repositories do not have credentials
+	credentials := gitsource.Credentials{}
+	if host := gitsource.Host(source.URL); host != "" {
+		var err error
+		credentials, err = dao.GetGitCredentials(db, host, appConfig.DockerConfig.CredentialsKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	workDir, err := ioutil.TempDir("", "quebic-function-git-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch git source %v", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	checkoutDir := filepath.Join(workDir, "checkout")
+	packagePath := filepath.Join(workDir, gitsource.PackageName(function.GetID()))
+
+	job.log("fetching " + source.URL + " " + source.Ref)
+
+	commit, err := gitsource.Fetch(source, credentials, checkoutDir, job.log)
+	if err != nil {
+		return nil, err
+	}
+
+	err = gitsource.Package(checkoutDir, source.Path, packagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	packageFile, err := os.Open(packagePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open function package %v", err)
+	}
+
+	functionDTO.SourceFile = types.FunctionSourceFile{
+		File:       packageFile,
+		FileHeader: &multipart.FileHeader{Filename: gitsource.PackageName(function.GetID())},
+	}
+
+	artifactStore := function_artifact.Store{Dir: appConfig.Artifacts.Path}
+	artifact, release, err := storeFunctionSource(artifactStore, functionDTO, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	artifact.Commit = commit
+	function.SourceCommit = commit
+
+	err = dao.SaveFunctionArtifact(db, artifact, actor)
+	if err == nil {
+		err = dao.SetFunctionSource(db, function)
+	}
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	job.log("fetched commit " + commit)
+
+	return release, nil
+
+}
+
+//storeFunctionSource copy uploaded source into the artifact store. build reads it from the store
+//returned func closes the stored artifact. nil artifact when function has no source
+func storeFunctionSource(
+	store function_artifact.Store,
+	functionDTO *types.FunctionDTO,
+	actor string) (*types.FunctionArtifact, func(), error) {
+
+	function := &functionDTO.Function
+	sourceFile := &functionDTO.SourceFile
+
+	function.ArtifactDigest = ""
+	function.SourceCommit = ""
+
+	if sourceFile.File == nil {
+		return nil, func() {}, nil
+	}
+	defer sourceFile.File.Close()
+
+	digest, size, err := store.Save(sourceFile.File)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	storedFile, err := store.Open(digest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sourceFile.File = storedFile
+	function.ArtifactDigest = digest
+
+	fileName := ""
+	if sourceFile.FileHeader != nil {
+		fileName = sourceFile.FileHeader.Filename
+	}
+
+	artifact := &types.FunctionArtifact{
+		Function:  function.GetID(),
+		Version:   function.Version,
+		Digest:    digest,
+		Size:      size,
+		FileName:  fileName,
+		Actor:     actor,
+		CreatedAt: common.CurrentTime(),
+	}
+
+	return artifact, func() { storedFile.Close() }, nil
 
 }
 
@@ -478,10 +742,10 @@ func fillRouteDataFromFunction(function *types.Function, route *types.Resource)
 }
 
 func saveFunction(
-	db *bolt.DB,
+	db storage.Store,
 	functionDTO *types.FunctionDTO,
-	appConfig config.AppConfig,
-	isCreate bool) error {
+	isCreate bool,
+	actor string) error {
 
 	function := &functionDTO.Function
 
@@ -495,22 +759,22 @@ func saveFunction(
 	}
 
 	if isCreate {
-		err = dao.Add(db, function)
+		err = dao.Add(db, function, actor)
 		if err != nil {
 			return err
 		}
 	} else {
-		err = dao.Update(db, function)
+		err = dao.Update(db, function, actor)
 		if err != nil {
 			return err
 		}
 	}
 
-	return postProcessFunction(db, appConfig.DockerConfig, functionDTO)
+	return nil
 
 }
 
-func saveRoute(db *bolt.DB, route *types.Resource, appConfig config.AppConfig, deployment dep.Deployment, messenger quebic_messenger.Messenger, isCreate bool) error {
+func saveRoute(db storage.Store, route *types.Resource, messenger quebic_messenger.Messenger, isCreate bool, actor string) error {
 
 	err := preProcessResource(route)
 	if err != nil {
@@ -524,25 +788,25 @@ func saveRoute(db *bolt.DB, route *types.Resource, appConfig config.AppConfig, d
 	route.SetModifiedAt()
 
 	if checkRouteISAlreadyExists(db, route) {
-		err = dao.Update(db, route)
+		err = dao.Update(db, route, actor)
 		if err != nil {
 			return err
 		}
 	} else {
-		err = dao.Add(db, route)
+		err = dao.Add(db, route, actor)
 		if err != nil {
 			return err
 		}
 	}
 
-	//re-start apigateway
-	restartAPIGateway(appConfig, db, deployment, messenger)
+	//reload apigateway routes
+	reloadAPIGatewayRoutes(messenger, route)
 
 	return nil
 
 }
 
-func validateFunctionDTO(db *bolt.DB, functionDTO *types.FunctionDTO, isCreate bool) map[string][]string {
+func validateFunctionDTO(db storage.Store, functionDTO *types.FunctionDTO, isCreate bool) map[string][]string {
 
 	var errors = make(map[string][]string)
 
@@ -573,7 +837,7 @@ func trimStringFieldsFunction(function *types.Function) {
 	function.Name = Trim(function.Name)
 }
 
-func validationFunction(db *bolt.DB, function *types.Function, functionArtifactFile types.FunctionSourceFile, isCreate bool) []string {
+func validationFunction(db storage.Store, function *types.Function, functionArtifactFile types.FunctionSourceFile, isCreate bool) []string {
 
 	var errors []string
 
@@ -589,27 +853,55 @@ func validationFunction(db *bolt.DB, function *types.Function, functionArtifactF
 		errors = append(errors, "runtime field should not be empty")
 	}
 
-	if function.Handler == "" {
-		errors = append(errors, "handler field should not be empty")
-	}
+	//isCustom : custom runtime or a runtime of the catalog which is based on it
+	var functionRunTime function_runtime.FunctionRunTime
+	isCustom := false
 
 	if function.Runtime != "" {
-
-		if !common.RuntimeValidate(common.Runtime(function.Runtime)) {
+		functionRunTime = prepareFunctionRunTime(db, common.Runtime(function.Runtime))
+		if functionRunTime == nil {
 			errors = append(errors, "runtime not match")
 		} else {
+			isCustom = functionRunTime.RuntimeType() == common.RuntimeCustom
+		}
+	}
 
-			functionRunTime := prepareFunctionRunTime(common.Runtime(function.Runtime))
+	if function.Handler == "" && !isCustom {
+		errors = append(errors, "handler field should not be empty")
+	}
 
-			if function.Handler != "" {
+	if function.Image != "" && !isCustom {
+		errors = append(errors, "image field is only allowed for custom runtime")
+	}
 
-				err := functionRunTime.SetFunctionHandler(function, functionArtifactFile)
-				if err != nil {
-					errors = append(errors, err.Error())
-				}
+	//git source is packaged as .tar. runtime validates the package which is going to be fetched
+	//source is not compiled, so runtimes which need a compiled artifact can not use it. eg: java .jar
+	if function.Git != nil && functionArtifactFile.File == nil {
 
-			}
+		errors = append(errors, gitsource.Validate(*function.Git)...)
 
+		packageName := gitsource.PackageName(function.GetID())
+		if functionRunTime != nil && !runtime_catalog.AcceptsArtifact(functionRunTime, packageName) {
+			errors = append(errors, fmt.Sprintf("git source is not supported by %s runtime. it needs a compiled artifact, upload it as source", function.Runtime))
+			functionRunTime = nil
+		}
+
+		functionArtifactFile.FileHeader = &multipart.FileHeader{Filename: packageName}
+
+	}
+
+	hasSource := functionArtifactFile.FileHeader != nil
+
+	if !hasSource && function.Image == "" {
+		errors = append(errors, "source file should not be empty")
+	}
+
+	if functionRunTime != nil && (function.Handler != "" || isCustom) &&
+		(hasSource || function.Image != "") {
+
+		err := functionRunTime.SetFunctionHandler(function, functionArtifactFile)
+		if err != nil {
+			errors = append(errors, err.Error())
 		}
 
 	}
@@ -650,22 +942,12 @@ func validationFunction(db *bolt.DB, function *types.Function, functionArtifactF
 
 }
 
-func prepareFunctionRunTime(runtime common.Runtime) function_runtime.FunctionRunTime {
-
-	if runtime == common.RuntimeJava {
-		return function_java_8_runtime.FunctionRunTime{}
-	} else if runtime == common.RuntimeNodeJS {
-		return function_nodejs_runtime.FunctionRunTime{}
-	} else if runtime == common.RuntimePython_2_7 {
-		return function_python_2_7_runtime.FunctionRunTime{}
-	} else if runtime == common.RuntimePython_3_6 {
-		return function_python_3_6_runtime.FunctionRunTime{}
-	}
-
-	return nil
+//prepareFunctionRunTime runtime of the runtime catalog. nil when runtime is not found
+func prepareFunctionRunTime(db storage.Store, runtime common.Runtime) function_runtime.FunctionRunTime {
+	return runtime_catalog.GetFunctionRunTime(db, string(runtime))
 }
 
-func validationFunctionContainer(db *bolt.DB, function *types.Function) []string {
+func validationFunctionContainer(db storage.Store, function *types.Function) []string {
 
 	var errors []string
 
@@ -684,7 +966,7 @@ func validationFunctionContainer(db *bolt.DB, function *types.Function) []string
 
 }
 
-func checkFunctionISAlreadyExists(db *bolt.DB, function *types.Function) bool {
+func checkFunctionISAlreadyExists(db storage.Store, function *types.Function) bool {
 
 	newVersion := function.Version
 
@@ -758,29 +1040,30 @@ func prepareFunctionEvent(functionID string) string {
 }
 
 func postProcessFunction(
-	db *bolt.DB,
-	dockerConfig config.DockerConfig,
-	functionDTO *types.FunctionDTO) error {
+	db storage.Store,
+	appConfig config.AppConfig,
+	builder function_builder.Builder,
+	functionDTO *types.FunctionDTO,
+	job *jobTracker) error {
 
 	function := &functionDTO.Function
 
 	entityLog := types.EntityLog{State: common.LogStateSaved}
 	dao.AddFunctionLog(db, function, entityLog, common.KubeStatusFalse)
 
-	//TODO
-	/*
-		authConfig, err := dockerConfig.GetDockerAuthConfig()
-		if err != nil {
-			log.Print(err)
-			return err
-		}*/
+	authConfig, err := getRegistryAuthConfig(db, appConfig.DockerConfig)
+	if err != nil {
+		return err
+	}
 
-	authConfig := docker_types.AuthConfig{
-		Username: "",
+	buildJob, err := startFunctionBuild(db, function, job)
+	if err != nil {
+		return err
 	}
 
-	functionRunTime := prepareFunctionRunTime(common.Runtime(function.Runtime))
-	dockerImageID, err := function_util.FunctionCreate(authConfig, *functionDTO, functionRunTime)
+	functionRunTime := prepareFunctionRunTime(db, common.Runtime(function.Runtime))
+	dockerImageID, err := function_util.FunctionCreate(authConfig, *functionDTO, functionRunTime, builder, buildJob)
+	buildJob.finish(dockerImageID, err)
 
 	if err != nil {
 		entityLog = types.EntityLog{State: common.LogStateDockerImageCreatingFailed, Message: err.Error()}
@@ -794,10 +1077,51 @@ func postProcessFunction(
 
 	function.DockerImageID = dockerImageID
 
+	//deployments are pinned to the digest of the built image
+	if appConfig.DockerConfig.VerifyImageDigest {
+
+		imageDigest, err := function_util.FunctionImageDigest(appConfig, db, dockerImageID)
+		if err != nil {
+			return fmt.Errorf("unable to record image digest %v", err)
+		}
+
+		err = dao.SetFunctionImageDigest(db, function, imageDigest)
+		if err != nil {
+			return err
+		}
+
+		job.log("image digest " + imageDigest)
+
+	}
+
+	dao.SetFunctionArtifactImage(db, function, dockerImageID, function.ImageDigest)
+
 	return nil
 
 }
 
+//getRegistryAuthConfig credentials of the registry which images are pushed into. empty auth config => images are not pushed
+func getRegistryAuthConfig(db storage.Store, dockerConfig config.DockerConfig) (docker_types.AuthConfig, error) {
+
+	if dockerConfig.RegistryAddress == "" {
+		return docker_types.AuthConfig{}, nil
+	}
+
+	server := registry.ParseImage(dockerConfig.RegistryAddress + "/image").Server
+
+	reg, credentials, err := dao.GetRegistryCredentials(db, server, dockerConfig.CredentialsKey)
+	if err != nil {
+		return docker_types.AuthConfig{}, err
+	}
+
+	if reg == nil {
+		return docker_types.AuthConfig{}, nil
+	}
+
+	return registry.AuthConfig(*reg, credentials.Password), nil
+
+}
+
 func checkVersionIsExists(function types.Function, version string) bool {
 
 	for _, v := range function.Versions {
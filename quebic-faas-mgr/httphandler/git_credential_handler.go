@@ -0,0 +1,231 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"quebic-faas/auth"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/config"
+	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+//GitCredentialHandler credentials of the private git repositories
+//password and private key are sealed in db and they are never replied
+func (httphandler *Httphandler) GitCredentialHandler(router *mux.Router) {
+
+	db := httphandler.db
+	appConfig := httphandler.config
+	authConfig := appConfig.Auth
+
+	router.HandleFunc("/git_credentials", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		getAllGitCredentials(w, r, db)
+	}, auth.RoleAny, authConfig)).Methods("GET")
+
+	router.HandleFunc("/git_credentials/{host}", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		credential, err := dao.GetGitCredential(db, strings.ToLower(mux.Vars(r)["host"]))
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if credential == nil {
+			makeErrorResponse(w, http.StatusNotFound, fmt.Errorf("resource not found"))
+			return
+		}
+
+		hideGitCredentialSecrets(credential)
+
+		writeResponse(w, credential, http.StatusOK)
+
+	}, auth.RoleAny, authConfig)).Methods("GET")
+
+	router.HandleFunc("/git_credentials", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		saveGitCredential(w, r, db, appConfig.DockerConfig, true)
+	}, auth.RoleAdmin, authConfig)).Methods("POST")
+
+	router.HandleFunc("/git_credentials", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		saveGitCredential(w, r, db, appConfig.DockerConfig, false)
+	}, auth.RoleAdmin, authConfig)).Methods("PUT")
+
+	router.HandleFunc("/git_credentials/{host}", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		credential, err := dao.GetGitCredential(db, strings.ToLower(mux.Vars(r)["host"]))
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if credential == nil {
+			status := http.StatusBadRequest
+			writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: []string{"git credential is not found"}, Status: status}, status)
+			return
+		}
+
+		err = dao.Delete(db, credential, getAuthUserName(r))
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		hideGitCredentialSecrets(credential)
+
+		writeResponse(w, credential, http.StatusOK)
+
+	}, auth.RoleAdmin, authConfig)).Methods("DELETE")
+
+}
+
+func saveGitCredential(
+	w http.ResponseWriter,
+	r *http.Request,
+	db storage.Store,
+	dockerConfig config.DockerConfig,
+	isCreate bool) {
+
+	credential := &types.GitCredential{}
+	err := processRequest(r, credential)
+	if err != nil {
+		makeErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	trimStringFieldsGitCredential(credential)
+
+	errors := validationGitCredential(db, credential, dockerConfig, isCreate)
+	if errors != nil {
+		status := http.StatusBadRequest
+		writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: errors, Status: status}, status)
+		return
+	}
+
+	if isCreate {
+		err = dao.AddGitCredential(db, credential, dockerConfig.CredentialsKey, getAuthUserName(r))
+	} else {
+		err = dao.UpdateGitCredential(db, credential, dockerConfig.CredentialsKey, getAuthUserName(r))
+	}
+
+	if dao.IsRevisionConflict(err) {
+		makeConflictResponse(w, err)
+		return
+	}
+	if err != nil {
+		makeErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	hideGitCredentialSecrets(credential)
+
+	if isCreate {
+		writeResponse(w, credential, http.StatusCreated)
+	} else {
+		writeResponse(w, credential, http.StatusAccepted)
+	}
+
+}
+
+func getAllGitCredentials(w http.ResponseWriter, r *http.Request, db storage.Store) {
+
+	var credentials []types.GitCredential
+	err := dao.GetAll(db, &types.GitCredential{}, func(k, v []byte) error {
+
+		credential := types.GitCredential{}
+		json.Unmarshal(v, &credential)
+
+		hideGitCredentialSecrets(&credential)
+
+		credentials = append(credentials, credential)
+		return nil
+	})
+
+	if err != nil {
+		makeErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if credentials == nil {
+		var emptyStr [0]string
+		writeResponse(w, emptyStr, http.StatusOK)
+	} else {
+		writeResponse(w, credentials, http.StatusOK)
+	}
+
+}
+
+func hideGitCredentialSecrets(credential *types.GitCredential) {
+	credential.Password = ""
+	credential.PrivateKey = ""
+}
+
+func trimStringFieldsGitCredential(credential *types.GitCredential) {
+	credential.Host = strings.ToLower(Trim(credential.Host))
+	credential.Username = Trim(credential.Username)
+}
+
+func validationGitCredential(db storage.Store, credential *types.GitCredential, dockerConfig config.DockerConfig, isCreate bool) []string {
+
+	var errors []string
+
+	if dockerConfig.CredentialsKey == "" {
+		return append(errors, "credentials key of the manager is not configured. set dockerConfig.credentialsKey")
+	}
+
+	if credential.Host == "" {
+		errors = append(errors, "host should not be empty")
+	}
+
+	if strings.ContainsAny(credential.Host, "/@ ") {
+		errors = append(errors, "host should be a host name. eg: github.com, git.example.com:8443")
+	}
+
+	if errors != nil {
+		return errors
+	}
+
+	saved, _ := dao.GetGitCredential(db, credential.Host)
+
+	if isCreate {
+
+		if saved != nil {
+			errors = append(errors, "git credential is already exists")
+		}
+
+		if credential.Password == "" && credential.PrivateKey == "" {
+			errors = append(errors, "password or private key should not be empty")
+		}
+
+		if credential.Password != "" && credential.Username == "" {
+			errors = append(errors, "username should not be empty")
+		}
+
+	} else {
+
+		if saved == nil {
+			errors = append(errors, "git credential is not found")
+		}
+
+	}
+
+	return errors
+
+}
@@ -0,0 +1,234 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"quebic-faas/auth"
+	"quebic-faas/common"
+	"quebic-faas/grpcutil"
+	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+//GRPCServiceHandler handler
+func (httphandler *Httphandler) GRPCServiceHandler(router *mux.Router) {
+
+	db := httphandler.db
+	appConfig := httphandler.config
+	authConfig := appConfig.Auth
+	messenger := httphandler.messenger
+
+	router.HandleFunc("/grpc-services", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		qID := r.FormValue("id")
+		if qID == "" {
+			getAllGRPCServices(w, r, db, &types.GRPCService{})
+			return
+		}
+
+		grpcService := &types.GRPCService{}
+		grpcService.ID = qID
+		getByID(w, r, db, grpcService)
+
+	}, auth.RoleAny, authConfig)).Methods("GET")
+
+	router.HandleFunc("/grpc-services", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		grpcService := &types.GRPCService{}
+		err := processRequest(r, grpcService)
+		if err != nil {
+			makeErrorResponse(w, http.StatusBadRequest, err)
+			return
+		}
+
+		trimStringFieldsGRPCService(grpcService)
+
+		errors := validationGRPCService(db, grpcService, true)
+		if errors != nil {
+			status := http.StatusBadRequest
+			writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: errors, Status: status}, status)
+			return
+		}
+
+		add(w, r, db, grpcService)
+
+		reloadAPIGatewayRoutes(messenger, grpcService)
+
+	}, auth.RoleAny, authConfig)).Methods("POST")
+
+	router.HandleFunc("/grpc-services", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		grpcService := &types.GRPCService{}
+		err := processRequest(r, grpcService)
+		if err != nil {
+			makeErrorResponse(w, http.StatusBadRequest, err)
+			return
+		}
+
+		trimStringFieldsGRPCService(grpcService)
+
+		errors := validationGRPCService(db, grpcService, false)
+		if errors != nil {
+			status := http.StatusBadRequest
+			writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: errors, Status: status}, status)
+			return
+		}
+
+		update(w, r, db, grpcService)
+
+		reloadAPIGatewayRoutes(messenger, grpcService)
+
+	}, auth.RoleAny, authConfig)).Methods("PUT")
+
+}
+
+func getAllGRPCServices(w http.ResponseWriter, r *http.Request, db storage.Store, entity types.Entity) {
+
+	var grpcServices []types.GRPCService
+	err := dao.GetAll(db, entity, func(k, v []byte) error {
+
+		grpcService := types.GRPCService{}
+		json.Unmarshal(v, &grpcService)
+		grpcServices = append(grpcServices, grpcService)
+		return nil
+	})
+
+	if err != nil {
+		makeErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if grpcServices == nil {
+		var emptyStr [0]string
+		writeResponse(w, emptyStr, http.StatusOK)
+	} else {
+		writeResponse(w, grpcServices, http.StatusOK)
+	}
+
+}
+
+func trimStringFieldsGRPCService(grpcService *types.GRPCService) {
+	grpcService.Name = Trim(grpcService.Name)
+	for i := range grpcService.Methods {
+		grpcService.Methods[i].Method = Trim(grpcService.Methods[i].Method)
+		grpcService.Methods[i].Event = Trim(grpcService.Methods[i].Event)
+	}
+}
+
+func validationGRPCService(db storage.Store, grpcService *types.GRPCService, isCreate bool) []string {
+
+	var errors []string
+
+	if grpcService.Name == "" {
+		errors = append(errors, "name field should not be empty")
+	}
+
+	if strings.Contains(grpcService.Name, " ") {
+		errors = append(errors, "name field not allow to contain spaces")
+	}
+
+	if len(grpcService.Descriptor) == 0 {
+		errors = append(errors, "descriptor field should not be empty")
+	}
+
+	if len(grpcService.Methods) == 0 {
+		errors = append(errors, "methods field should not be empty")
+	}
+
+	if errors != nil {
+		return errors
+	}
+
+	serviceDescriptor, err := grpcutil.FindServiceDescriptor(grpcService.Descriptor, grpcService.Name)
+	if err != nil {
+		return append(errors, err.Error())
+	}
+
+	methods := make(map[string]bool)
+	for i := range grpcService.Methods {
+
+		binding := &grpcService.Methods[i]
+
+		if methods[binding.Method] {
+			errors = append(errors, fmt.Sprintf("method %s is duplicated", binding.Method))
+		}
+		methods[binding.Method] = true
+
+		methodDescriptor := serviceDescriptor.FindMethodByName(binding.Method)
+		if methodDescriptor == nil {
+			errors = append(errors, fmt.Sprintf("method %s is not found in the descriptor", binding.Method))
+		} else if methodDescriptor.IsClientStreaming() || methodDescriptor.IsServerStreaming() {
+			errors = append(errors, fmt.Sprintf("method %s is streaming. only unary methods are allowed", binding.Method))
+		}
+
+		if binding.Event == "" {
+			errors = append(errors, fmt.Sprintf("method %s event field should not be empty", binding.Method))
+			continue
+		}
+
+		event, eventErrors := createEventFromEventID(db, binding.Event)
+		if eventErrors != nil {
+			errors = append(errors, eventErrors...)
+		} else {
+			binding.Event = event.GetID()
+		}
+
+		if binding.RequestTimeout <= 0 {
+			binding.RequestTimeout = 10
+		}
+
+	}
+
+	grpcService.ID = grpcService.Name
+
+	if isCreate {
+
+		if checkGRPCServiceISAlreadyExists(db, grpcService) {
+			errors = append(errors, "grpc-service is already exists")
+		}
+
+	} else {
+
+		if !checkGRPCServiceISAlreadyExists(db, grpcService) {
+			errors = append(errors, "grpc-service is not found")
+		}
+
+	}
+
+	return errors
+
+}
+
+func checkGRPCServiceISAlreadyExists(db storage.Store, grpcService *types.GRPCService) bool {
+
+	found := false
+	_ = dao.GetByID(db, &types.GRPCService{ID: grpcService.ID}, func(savedObj []byte) error {
+
+		if savedObj != nil {
+			found = true
+		}
+
+		return nil
+	})
+
+	return found
+}
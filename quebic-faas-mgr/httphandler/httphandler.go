@@ -20,36 +20,41 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"quebic-faas/common"
 	_messenger "quebic-faas/messenger"
-	"quebic-faas/quebic-faas-mgr/components"
+	"quebic-faas/metrics"
 	"quebic-faas/quebic-faas-mgr/config"
 	"quebic-faas/quebic-faas-mgr/dao"
 	dep "quebic-faas/quebic-faas-mgr/deployment"
+	"quebic-faas/quebic-faas-mgr/function/function_builder"
 	"quebic-faas/quebic-faas-mgr/logger"
+	"quebic-faas/quebic-faas-mgr/storage"
 	"quebic-faas/types"
 	"strings"
+	"time"
 
-	bolt "github.com/coreos/bbolt"
 	"github.com/gorilla/mux"
 )
 
 //Httphandler handlers
 type Httphandler struct {
 	config     config.AppConfig
-	db         *bolt.DB
+	db         storage.Store
 	messenger  _messenger.Messenger
 	loggerUtil logger.Logger
 	deployment dep.Deployment
+	builder    function_builder.Builder
 }
 
 //SetUpHTTPHandlers setUpHTTPHandlers
 func SetUpHTTPHandlers(
 	config config.AppConfig,
 	router *mux.Router,
-	db *bolt.DB,
+	db storage.Store,
 	messenger _messenger.Messenger,
 	loggerUtil logger.Logger,
-	deployment dep.Deployment) {
+	deployment dep.Deployment,
+	builder function_builder.Builder) {
 
 	//root handler
 	type StatusResponse struct {
@@ -63,26 +68,38 @@ func SetUpHTTPHandlers(
 		)
 	}).Methods("GET")
 
+	//prometheus scrape endpoint
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+
 	http := &Httphandler{
 		config:     config,
 		db:         db,
 		messenger:  messenger,
 		loggerUtil: loggerUtil,
 		deployment: deployment,
+		builder:    builder,
 	}
 
 	http.AuthHandler(router)
 	http.EventHandler(router)
 	http.ResourceHandler(router)
+	http.GRPCServiceHandler(router)
 	http.FunctionHandler(router)
+	http.FunctionArtifactHandler(router)
+	http.RuntimeHandler(router)
+	http.RegistryHandler(router)
+	http.GitCredentialHandler(router)
+	http.JobHandler(router)
 	http.ApigatewayDataServe(router)
 	http.MgrComponentHandler(router)
 	http.RequestTrackerHandler(router)
 	http.EventBoxHandler(router)
+	http.BackupHandler(router)
+	http.AuditHandler(router)
 
 }
 
-func getByID(w http.ResponseWriter, r *http.Request, db *bolt.DB, entity types.Entity) {
+func getByID(w http.ResponseWriter, r *http.Request, db storage.Store, entity types.Entity) {
 
 	if entity.GetID() == "" {
 		makeErrorResponse(w, http.StatusBadRequest, makeError("id is empty", nil))
@@ -107,14 +124,14 @@ func getByID(w http.ResponseWriter, r *http.Request, db *bolt.DB, entity types.E
 
 }
 
-func add(w http.ResponseWriter, r *http.Request, db *bolt.DB, entity types.Entity) {
+func add(w http.ResponseWriter, r *http.Request, db storage.Store, entity types.Entity) {
 
 	if entity.GetID() == "" {
 		makeErrorResponse(w, http.StatusBadRequest, makeError("unable find id field", nil))
 		return
 	}
 
-	err := dao.Add(db, entity)
+	err := dao.Add(db, entity, getAuthUserName(r))
 	if err != nil {
 		makeErrorResponse(w, http.StatusBadRequest, err)
 		return
@@ -124,14 +141,18 @@ func add(w http.ResponseWriter, r *http.Request, db *bolt.DB, entity types.Entit
 
 }
 
-func update(w http.ResponseWriter, r *http.Request, db *bolt.DB, entity types.Entity) {
+func update(w http.ResponseWriter, r *http.Request, db storage.Store, entity types.Entity) {
 
 	if entity.GetID() == "" {
 		makeErrorResponse(w, http.StatusBadRequest, makeError("unable find id field", nil))
 		return
 	}
 
-	err := dao.Update(db, entity)
+	err := dao.Update(db, entity, getAuthUserName(r))
+	if dao.IsRevisionConflict(err) {
+		makeConflictResponse(w, err)
+		return
+	}
 	if err != nil {
 		makeErrorResponse(w, http.StatusNotFound, err)
 		return
@@ -180,6 +201,12 @@ func makeErrorResponse(w http.ResponseWriter, status int, cause error) {
 	writeResponse(w, &errorResponse, status)
 }
 
+//makeConflictResponse entity has been modified after client read it. client should reload and retry
+func makeConflictResponse(w http.ResponseWriter, cause error) {
+	status := http.StatusConflict
+	writeResponse(w, types.ErrorResponse{Cause: common.ErrorRevisionConflict, Message: []string{cause.Error()}, Status: status}, status)
+}
+
 func writeResponse(w http.ResponseWriter, v interface{}, status int) {
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	w.WriteHeader(status)
@@ -193,15 +220,23 @@ func Trim(s string) string {
 	return strings.Trim(s, " ")
 }
 
-func restartAPIGateway(appConfig config.AppConfig, db *bolt.DB, deployment dep.Deployment, msg _messenger.Messenger) {
-
-	go func() {
-
-		err := components.ApigatewaySetup(&appConfig, db, deployment, msg)
-		if err != nil {
-			log.Printf("apigateway re-start failed : %v", err)
-		}
-
-	}()
+//reloadAPIGatewayRoutes notify running apigateways to reload routes in place
+//route can be a resource or a grpc-service
+func reloadAPIGatewayRoutes(msg _messenger.Messenger, route types.Entity) {
+
+	_, err := msg.Publish(
+		common.EventRouteChange,
+		types.RouteChangeMessage{
+			Route:     route.GetID(),
+			ChangedAt: time.Now().String(),
+		},
+		nil,
+		nil,
+		nil,
+		0,
+	)
+	if err != nil {
+		log.Printf("route-change publish failed : %v", err)
+	}
 
 }
@@ -0,0 +1,174 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"quebic-faas/auth"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+	"time"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+)
+
+//jobLogSaveInterval output lines are saved into the job at most once in this interval
+const jobLogSaveInterval = time.Second
+
+//JobHandler job handler
+func (httphandler *Httphandler) JobHandler(router *mux.Router) {
+
+	db := httphandler.db
+	appConfig := httphandler.config
+	authConfig := appConfig.Auth
+
+	router.HandleFunc("/jobs/{id}", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		getByID(w, r, db, processRequestParmForID(r, "id", &types.Job{}))
+	}, auth.RoleAny, authConfig)).Methods("GET")
+
+}
+
+//jobTracker keeps state and output of a job in db. clients poll GET /jobs/{id}
+//or follow the stream of the request which started the job
+//a job is processed by a single goroutine
+//tracker stops once the job is finished outside of it. eg: failed as stale
+type jobTracker struct {
+	db      storage.Store
+	job     types.Job
+	stream  *functionBuildStream
+	savedAt time.Time
+	stopped bool
+}
+
+func newJobTracker(db storage.Store, kind string, target string, actor string, stream *functionBuildStream) (*jobTracker, error) {
+
+	jobID, err := uuid.NewV4()
+	if err != nil {
+		return nil, fmt.Errorf("unable to assign job id %v", err)
+	}
+
+	//jobs of a restarted manager are failed at its startup
+	manager, _ := os.Hostname()
+
+	tracker := &jobTracker{
+		db:     db,
+		stream: stream,
+		job: types.Job{
+			ID:        jobID.String(),
+			Kind:      kind,
+			Target:    target,
+			State:     common.JobStateUploaded,
+			Manager:   manager,
+			Actor:     actor,
+			CreatedAt: common.CurrentTime(),
+		},
+	}
+
+	err = tracker.save()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("job %s %s %s accepted", tracker.job.ID, kind, target)
+
+	return tracker, nil
+
+}
+
+func (tracker *jobTracker) save() error {
+
+	//unfinished jobs which are not updated for a while are failed by the leader
+	tracker.job.SetModifiedAt()
+
+	finished, err := dao.SaveJob(tracker.db, &tracker.job)
+	if err != nil {
+		log.Printf("job %s save failed %v", tracker.job.ID, err)
+		return err
+	}
+
+	//job is reloaded. its state is kept and later changes are dropped
+	if finished && !tracker.stopped {
+		tracker.stopped = true
+		tracker.stream.state(tracker.job)
+		log.Printf("job %s is already %s. %s", tracker.job.ID, tracker.job.State, tracker.job.Error)
+	}
+
+	tracker.savedAt = time.Now()
+
+	return nil
+
+}
+
+func (tracker *jobTracker) setState(state string) {
+
+	if tracker.stopped {
+		return
+	}
+
+	tracker.job.State = state
+	tracker.save()
+
+	if tracker.stopped {
+		return
+	}
+
+	tracker.stream.state(tracker.job)
+
+	log.Printf("job %s %s", tracker.job.ID, state)
+
+}
+
+func (tracker *jobTracker) setBuildID(buildID string) {
+	if tracker.stopped {
+		return
+	}
+	tracker.job.BuildID = buildID
+	tracker.save()
+}
+
+//log keep output line. saved with the next state change or after jobLogSaveInterval
+func (tracker *jobTracker) log(line string) {
+
+	if tracker.stopped {
+		return
+	}
+
+	tracker.job.Logs = append(tracker.job.Logs, line)
+	tracker.job.LogCount++
+	if len(tracker.job.Logs) > common.JobMaxLogLines {
+		tracker.job.Logs = tracker.job.Logs[len(tracker.job.Logs)-common.JobMaxLogLines:]
+	}
+
+	tracker.stream.log(tracker.job, line)
+
+	if time.Since(tracker.savedAt) >= jobLogSaveInterval {
+		tracker.save()
+	}
+
+}
+
+func (tracker *jobTracker) fail(err error) {
+	if tracker.stopped {
+		return
+	}
+	tracker.job.Error = err.Error()
+	tracker.setState(common.JobStateFailed)
+}
@@ -0,0 +1,261 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"quebic-faas/auth"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/config"
+	"quebic-faas/quebic-faas-mgr/dao"
+	dep "quebic-faas/quebic-faas-mgr/deployment"
+	"quebic-faas/quebic-faas-mgr/registry"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+
+	"github.com/gorilla/mux"
+)
+
+//RegistryHandler registry credentials handler
+//passwords are sealed in db and they are never replied
+func (httphandler *Httphandler) RegistryHandler(router *mux.Router) {
+
+	db := httphandler.db
+	deployment := httphandler.deployment
+	appConfig := httphandler.config
+	authConfig := appConfig.Auth
+
+	router.HandleFunc("/registries", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		getAllRegistries(w, r, db)
+	}, auth.RoleAny, authConfig)).Methods("GET")
+
+	router.HandleFunc("/registries/{server}", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		server := registry.NormalizeServer(mux.Vars(r)["server"])
+
+		reg, err := dao.GetRegistry(db, server)
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if reg == nil {
+			makeErrorResponse(w, http.StatusNotFound, fmt.Errorf("resource not found"))
+			return
+		}
+
+		reg.Password = ""
+
+		writeResponse(w, reg, http.StatusOK)
+
+	}, auth.RoleAny, authConfig)).Methods("GET")
+
+	router.HandleFunc("/registries", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		saveRegistry(w, r, db, deployment, appConfig.DockerConfig, true)
+	}, auth.RoleAdmin, authConfig)).Methods("POST")
+
+	router.HandleFunc("/registries", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		saveRegistry(w, r, db, deployment, appConfig.DockerConfig, false)
+	}, auth.RoleAdmin, authConfig)).Methods("PUT")
+
+	router.HandleFunc("/registries/{server}", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		server := registry.NormalizeServer(mux.Vars(r)["server"])
+
+		reg, err := dao.GetRegistry(db, server)
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if reg == nil {
+			status := http.StatusBadRequest
+			writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: []string{"registry is not found"}, Status: status}, status)
+			return
+		}
+
+		err = dao.Delete(db, reg, getAuthUserName(r))
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		err = deployment.RegistrySecretDelete(reg.PullSecret)
+		if err != nil {
+			log.Printf("registry %s pull secret delete failed : %v", reg.Server, err)
+		}
+
+		reg.Password = ""
+
+		writeResponse(w, reg, http.StatusOK)
+
+	}, auth.RoleAdmin, authConfig)).Methods("DELETE")
+
+}
+
+func saveRegistry(
+	w http.ResponseWriter,
+	r *http.Request,
+	db storage.Store,
+	deployment dep.Deployment,
+	dockerConfig config.DockerConfig,
+	isCreate bool) {
+
+	reg := &types.Registry{}
+	err := processRequest(r, reg)
+	if err != nil {
+		makeErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	trimStringFieldsRegistry(reg)
+
+	errors := validationRegistry(db, reg, dockerConfig, isCreate)
+	if errors != nil {
+		status := http.StatusBadRequest
+		writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: errors, Status: status}, status)
+		return
+	}
+
+	reg.PullSecret = registry.PullSecretName(reg.Server)
+
+	//pull secret keeps the opened password. empty password of an update keeps the saved password
+	password := reg.Password
+	if password == "" {
+		_, credentials, err := dao.GetRegistryCredentials(db, reg.Server, dockerConfig.CredentialsKey)
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+		password = credentials.Password
+	}
+
+	if isCreate {
+		err = dao.AddRegistry(db, reg, dockerConfig.CredentialsKey, getAuthUserName(r))
+	} else {
+		err = dao.UpdateRegistry(db, reg, dockerConfig.CredentialsKey, getAuthUserName(r))
+	}
+
+	if dao.IsRevisionConflict(err) {
+		makeConflictResponse(w, err)
+		return
+	}
+	if err != nil {
+		makeErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	dockerConfigJSON, err := registry.DockerConfigJSON(*reg, password)
+	if err != nil {
+		makeErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = deployment.RegistrySecretCreateOrUpdate(reg.PullSecret, dockerConfigJSON)
+	if err != nil {
+		makeErrorResponse(w, http.StatusInternalServerError, fmt.Errorf("registry is saved. but pull secret is not saved %v", err))
+		return
+	}
+
+	reg.Password = ""
+
+	if isCreate {
+		writeResponse(w, reg, http.StatusCreated)
+	} else {
+		writeResponse(w, reg, http.StatusAccepted)
+	}
+
+}
+
+func getAllRegistries(w http.ResponseWriter, r *http.Request, db storage.Store) {
+
+	var registries []types.Registry
+	err := dao.GetAll(db, &types.Registry{}, func(k, v []byte) error {
+
+		reg := types.Registry{}
+		json.Unmarshal(v, &reg)
+
+		reg.Password = ""
+
+		registries = append(registries, reg)
+		return nil
+	})
+
+	if err != nil {
+		makeErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if registries == nil {
+		var emptyStr [0]string
+		writeResponse(w, emptyStr, http.StatusOK)
+	} else {
+		writeResponse(w, registries, http.StatusOK)
+	}
+
+}
+
+func trimStringFieldsRegistry(reg *types.Registry) {
+	reg.Server = registry.NormalizeServer(reg.Server)
+	reg.Username = Trim(reg.Username)
+	reg.Email = Trim(reg.Email)
+}
+
+func validationRegistry(db storage.Store, reg *types.Registry, dockerConfig config.DockerConfig, isCreate bool) []string {
+
+	var errors []string
+
+	if dockerConfig.CredentialsKey == "" {
+		return append(errors, "credentials key of the manager is not configured. set dockerConfig.credentialsKey")
+	}
+
+	if reg.Server == "" {
+		errors = append(errors, "server should not be empty")
+	}
+
+	if reg.Username == "" {
+		errors = append(errors, "username should not be empty")
+	}
+
+	if errors != nil {
+		return errors
+	}
+
+	saved, _ := dao.GetRegistry(db, reg.Server)
+
+	if isCreate {
+
+		if saved != nil {
+			errors = append(errors, "registry is already exists")
+		}
+
+		if reg.Password == "" {
+			errors = append(errors, "password should not be empty")
+		}
+
+	} else {
+
+		if saved == nil {
+			errors = append(errors, "registry is not found")
+		}
+
+	}
+
+	return errors
+
+}
@@ -15,12 +15,22 @@
 package httphandler
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"quebic-faas/auth"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/logger"
+	"quebic-faas/types"
+	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
+const requestTrackerFollowHeartbeat = time.Second * 15
+
 //RequestTrackerHandler request-tracker handler
 func (httphandler *Httphandler) RequestTrackerHandler(router *mux.Router) {
 
@@ -31,13 +41,20 @@ func (httphandler *Httphandler) RequestTrackerHandler(router *mux.Router) {
 		qID := r.FormValue("id")
 		if qID == "" {
 
-			requestTrackers, err := httphandler.loggerUtil.GetRequestTrackers()
+			filter, limit, errors := prepareRequestTrackerFilter(r)
+			if errors != nil {
+				status := http.StatusBadRequest
+				writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: errors, Status: status}, status)
+				return
+			}
+
+			page, err := httphandler.loggerUtil.GetRequestTrackers(filter, r.FormValue("cursor"), limit)
 			if err != nil {
 				makeErrorResponse(w, http.StatusInternalServerError, err)
 				return
 			}
 
-			writeResponse(w, requestTrackers, http.StatusOK)
+			writeResponse(w, page, http.StatusOK)
 
 			return
 
@@ -53,6 +70,44 @@ func (httphandler *Httphandler) RequestTrackerHandler(router *mux.Router) {
 
 	}, auth.RoleAny, authConfig)).Methods("GET")
 
+	//should be registered before /request-trackers/{requestID}
+	router.HandleFunc("/request-trackers/follow", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		watch := logger.RequestTrackerWatch{
+			RequestID: r.FormValue("requestID"),
+			Function:  r.FormValue("function"),
+		}
+
+		if watch.RequestID == "" && watch.Function == "" {
+			status := http.StatusBadRequest
+			writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: []string{"requestID or function should be provided"}, Status: status}, status)
+			return
+		}
+
+		httphandler.followRequestTrackers(w, r, watch)
+
+	}, auth.RoleAny, authConfig)).Methods("GET")
+
+	//should be registered before /request-trackers/{requestID}
+	router.HandleFunc("/request-trackers/search", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		search, limit, errors := prepareLogSearch(r)
+		if errors != nil {
+			status := http.StatusBadRequest
+			writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: errors, Status: status}, status)
+			return
+		}
+
+		results, err := httphandler.loggerUtil.SearchRequestTrackers(search, limit)
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeResponse(w, results, http.StatusOK)
+
+	}, auth.RoleAny, authConfig)).Methods("GET")
+
 	router.HandleFunc("/request-trackers/{requestID}", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
 
 		params := mux.Vars(r)
@@ -68,3 +123,150 @@ func (httphandler *Httphandler) RequestTrackerHandler(router *mux.Router) {
 	}, auth.RoleAny, authConfig)).Methods("GET")
 
 }
+
+//followRequestTrackers stream request-tracker messages as server-sent events
+//stream is closed when followed request is completed or client is gone
+func (httphandler *Httphandler) followRequestTrackers(w http.ResponseWriter, r *http.Request, watch logger.RequestTrackerWatch) {
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		makeErrorResponse(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	messages, cancel := httphandler.loggerUtil.Follow(watch)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(requestTrackerFollowHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			data, _ := json.Marshal(message)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+
+			if watch.RequestID != "" && message.Completed {
+				return
+			}
+
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+}
+
+//prepareRequestTrackerFilter query params => from, to, source, status, state, limit
+func prepareRequestTrackerFilter(r *http.Request) (logger.RequestTrackerFilter, int, []string) {
+
+	var errors []string
+
+	filter := logger.RequestTrackerFilter{
+		From:   r.FormValue("from"),
+		To:     r.FormValue("to"),
+		Source: r.FormValue("source"),
+		State:  r.FormValue("state"),
+	}
+
+	for _, t := range []string{filter.From, filter.To} {
+		if t == "" {
+			continue
+		}
+		if _, err := time.Parse(common.DefaultTimeLayout, t); err != nil {
+			errors = append(errors, "from and to should be in "+common.DefaultTimeLayout+" format")
+			break
+		}
+	}
+
+	if !(filter.State == "" ||
+		filter.State == logger.RequestTrackerStateCompleted ||
+		filter.State == logger.RequestTrackerStatePending) {
+		errors = append(errors, "state is not match to any of thses states ( completed , pending )")
+	}
+
+	if status := r.FormValue("status"); status != "" {
+		s, err := strconv.Atoi(status)
+		if err != nil {
+			errors = append(errors, "status should be a number")
+		}
+		filter.Status = s
+	}
+
+	limit := 0
+	if l := r.FormValue("limit"); l != "" {
+		v, err := strconv.Atoi(l)
+		if err != nil || v <= 0 {
+			errors = append(errors, "limit should be a positive number")
+		}
+		limit = v
+	}
+
+	return filter, limit, errors
+
+}
+
+//prepareLogSearch query params => type, source, from, to, q, regex, limit
+func prepareLogSearch(r *http.Request) (logger.LogSearch, int, []string) {
+
+	var errors []string
+
+	search := logger.LogSearch{
+		Type:   r.FormValue("type"),
+		Source: r.FormValue("source"),
+		From:   r.FormValue("from"),
+		To:     r.FormValue("to"),
+		Query:  r.FormValue("q"),
+		Regex:  r.FormValue("regex"),
+	}
+
+	if search == (logger.LogSearch{}) {
+		errors = append(errors, "at least one of type, source, from, to, q or regex should be provided")
+	}
+
+	if search.Query != "" && search.Regex != "" {
+		errors = append(errors, "q and regex can not be used together")
+	}
+
+	if search.Regex != "" {
+		if _, err := regexp.Compile(search.Regex); err != nil {
+			errors = append(errors, fmt.Sprintf("regex is not valid : %v", err))
+		}
+	}
+
+	for _, t := range []string{search.From, search.To} {
+		if t == "" {
+			continue
+		}
+		if _, err := time.Parse(common.DefaultTimeLayout, t); err != nil {
+			errors = append(errors, "from and to should be in "+common.DefaultTimeLayout+" format")
+			break
+		}
+	}
+
+	limit := 0
+	if l := r.FormValue("limit"); l != "" {
+		v, err := strconv.Atoi(l)
+		if err != nil || v <= 0 {
+			errors = append(errors, "limit should be a positive number")
+		}
+		limit = v
+	}
+
+	return search, limit, errors
+
+}
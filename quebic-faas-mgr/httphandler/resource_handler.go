@@ -21,11 +21,11 @@ import (
 	"quebic-faas/auth"
 	"quebic-faas/common"
 	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/storage"
 	"quebic-faas/types"
 	"sort"
 	"strings"
 
-	bolt "github.com/coreos/bbolt"
 	"github.com/gorilla/mux"
 )
 
@@ -35,7 +35,6 @@ func (httphandler *Httphandler) ResourceHandler(router *mux.Router) {
 	db := httphandler.db
 	appConfig := httphandler.config
 	authConfig := appConfig.Auth
-	deployment := httphandler.deployment
 	messenger := httphandler.messenger
 
 	router.HandleFunc("/routes", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
@@ -107,7 +106,7 @@ func (httphandler *Httphandler) ResourceHandler(router *mux.Router) {
 
 		add(w, r, db, resource)
 
-		restartAPIGateway(appConfig, db, deployment, messenger)
+		reloadAPIGatewayRoutes(messenger, resource)
 
 	}, auth.RoleAny, authConfig)).Methods("POST")
 
@@ -137,13 +136,13 @@ func (httphandler *Httphandler) ResourceHandler(router *mux.Router) {
 
 		update(w, r, db, resource)
 
-		restartAPIGateway(appConfig, db, deployment, messenger)
+		reloadAPIGatewayRoutes(messenger, resource)
 
 	}, auth.RoleAny, authConfig)).Methods("PUT")
 
 }
 
-func getAllResources(w http.ResponseWriter, r *http.Request, db *bolt.DB, entity types.Entity) {
+func getAllResources(w http.ResponseWriter, r *http.Request, db storage.Store, entity types.Entity) {
 
 	var resources []types.Resource
 	err := dao.GetAll(db, entity, func(k, v []byte) error {
@@ -175,9 +174,10 @@ func trimStringFieldsResource(resource *types.Resource) {
 	resource.Name = Trim(resource.Name)
 	resource.URL = Trim(resource.URL)
 	resource.RequestMethod = Trim(resource.RequestMethod)
+	resource.Stream = Trim(resource.Stream)
 }
 
-func validationRoute(db *bolt.DB, resource *types.Resource, isFounctionInvokeRoute bool, isCreate bool) []string {
+func validationRoute(db storage.Store, resource *types.Resource, isFounctionInvokeRoute bool, isCreate bool) []string {
 
 	var errors []string
 
@@ -206,8 +206,15 @@ func validationRoute(db *bolt.DB, resource *types.Resource, isFounctionInvokeRou
 		if !(resource.RequestMethod == common.ResourceRequestMethodGET ||
 			resource.RequestMethod == common.ResourceRequestMethodPOST ||
 			resource.RequestMethod == common.ResourceRequestMethodPUT ||
-			resource.RequestMethod == common.ResourceRequestMethodDELETE) {
-			errors = append(errors, "requestMethod is not match to any of thses methods ( GET , POST , PUT , DELETE )")
+			resource.RequestMethod == common.ResourceRequestMethodDELETE ||
+			resource.RequestMethod == common.ResourceRequestMethodWebSocket) {
+			errors = append(errors, "requestMethod is not match to any of thses methods ( GET , POST , PUT , DELETE , WEBSOCKET )")
+		}
+
+		if resource.RequestMethod == common.ResourceRequestMethodWebSocket {
+			if resource.Async || resource.Cache.Enabled || resource.Stream != "" {
+				errors = append(errors, "async, cache and stream are not allowed for websocket routes")
+			}
 		}
 
 	}
@@ -216,6 +223,38 @@ func validationRoute(db *bolt.DB, resource *types.Resource, isFounctionInvokeRou
 		errors = append(errors, "event field should not be empty")
 	}
 
+	if resource.Cache.Enabled {
+
+		if resource.RequestMethod != common.ResourceRequestMethodGET {
+			errors = append(errors, "cache is only allowed for GET routes")
+		}
+
+		if resource.Async {
+			errors = append(errors, "cache is not allowed for async routes")
+		}
+
+		if resource.Cache.TTL <= 0 {
+			errors = append(errors, "cache.ttl should be greater than zero")
+		}
+
+	}
+
+	if resource.Stream != "" {
+
+		if !(resource.Stream == common.ResourceStreamChunked || resource.Stream == common.ResourceStreamSSE) {
+			errors = append(errors, "stream is not match to any of thses modes ( chunked , sse )")
+		}
+
+		if resource.Async {
+			errors = append(errors, "stream is not allowed for async routes")
+		}
+
+		if resource.Cache.Enabled {
+			errors = append(errors, "stream is not allowed for cached routes")
+		}
+
+	}
+
 	if resource.Event != "" {
 
 		//event is saved only for user defined route. function invokers no need to save
@@ -255,7 +294,7 @@ func validationRoute(db *bolt.DB, resource *types.Resource, isFounctionInvokeRou
 
 }
 
-func checkRouteISAlreadyExists(db *bolt.DB, resource *types.Resource) bool {
+func checkRouteISAlreadyExists(db storage.Store, resource *types.Resource) bool {
 
 	found := false
 	_ = dao.GetByID(db, resource, func(savedObj []byte) error {
@@ -0,0 +1,202 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package httphandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"quebic-faas/auth"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/function/function_runtime/runtime_catalog"
+	"quebic-faas/quebic-faas-mgr/storage"
+	"quebic-faas/types"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+//RuntimeHandler runtime catalog handler
+func (httphandler *Httphandler) RuntimeHandler(router *mux.Router) {
+
+	db := httphandler.db
+	appConfig := httphandler.config
+	authConfig := appConfig.Auth
+
+	router.HandleFunc("/runtimes", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		getAllRuntimes(w, r, db)
+	}, auth.RoleAny, authConfig)).Methods("GET")
+
+	router.HandleFunc("/runtimes/{name}", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		getByID(w, r, db, processRequestParmForID(r, "name", &types.RuntimeSpec{}))
+	}, auth.RoleAny, authConfig)).Methods("GET")
+
+	router.HandleFunc("/runtimes", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		saveRuntime(w, r, db, true)
+	}, auth.RoleAdmin, authConfig)).Methods("POST")
+
+	router.HandleFunc("/runtimes", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		saveRuntime(w, r, db, false)
+	}, auth.RoleAdmin, authConfig)).Methods("PUT")
+
+	router.HandleFunc("/runtimes/{name}", validateMiddleware(func(w http.ResponseWriter, r *http.Request) {
+
+		runtimeSpec := &types.RuntimeSpec{}
+		processRequestParmForID(r, "name", runtimeSpec)
+
+		errors := validationRuntimeDelete(db, runtimeSpec)
+		if errors != nil {
+			status := http.StatusBadRequest
+			writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: errors, Status: status}, status)
+			return
+		}
+
+		err := dao.Delete(db, runtimeSpec, getAuthUserName(r))
+		if err != nil {
+			makeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeResponse(w, runtimeSpec, http.StatusOK)
+
+	}, auth.RoleAdmin, authConfig)).Methods("DELETE")
+
+}
+
+func saveRuntime(w http.ResponseWriter, r *http.Request, db storage.Store, isCreate bool) {
+
+	runtimeSpec := &types.RuntimeSpec{}
+	err := processRequest(r, runtimeSpec)
+	if err != nil {
+		makeErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	trimStringFieldsRuntime(runtimeSpec)
+
+	errors := validationRuntime(db, runtimeSpec, isCreate)
+	if errors != nil {
+		status := http.StatusBadRequest
+		writeResponse(w, types.ErrorResponse{Cause: common.ErrorValidationFailed, Message: errors, Status: status}, status)
+		return
+	}
+
+	if isCreate {
+		add(w, r, db, runtimeSpec)
+	} else {
+		update(w, r, db, runtimeSpec)
+	}
+
+}
+
+func getAllRuntimes(w http.ResponseWriter, r *http.Request, db storage.Store) {
+
+	var runtimes []types.RuntimeSpec
+	err := dao.GetAll(db, &types.RuntimeSpec{}, func(k, v []byte) error {
+
+		runtimeSpec := types.RuntimeSpec{}
+		json.Unmarshal(v, &runtimeSpec)
+		runtimes = append(runtimes, runtimeSpec)
+		return nil
+	})
+
+	if err != nil {
+		makeErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if runtimes == nil {
+		var emptyStr [0]string
+		writeResponse(w, emptyStr, http.StatusOK)
+	} else {
+		writeResponse(w, runtimes, http.StatusOK)
+	}
+
+}
+
+func trimStringFieldsRuntime(runtimeSpec *types.RuntimeSpec) {
+	runtimeSpec.Name = Trim(runtimeSpec.Name)
+	runtimeSpec.Base = Trim(runtimeSpec.Base)
+	runtimeSpec.Image = Trim(runtimeSpec.Image)
+	runtimeSpec.BuildImage = Trim(runtimeSpec.BuildImage)
+}
+
+func validationRuntime(db storage.Store, runtimeSpec *types.RuntimeSpec, isCreate bool) []string {
+
+	errors := runtime_catalog.ValidateRuntimeSpec(runtimeSpec)
+
+	if isCreate {
+
+		if checkRuntimeISAlreadyExists(db, runtimeSpec) {
+			errors = append(errors, "runtime is already exists")
+		}
+
+	} else {
+
+		if !checkRuntimeISAlreadyExists(db, runtimeSpec) {
+			errors = append(errors, "runtime is not found")
+		}
+
+	}
+
+	return errors
+
+}
+
+//validationRuntimeDelete runtime which is used by functions can not be deleted
+func validationRuntimeDelete(db storage.Store, runtimeSpec *types.RuntimeSpec) []string {
+
+	var errors []string
+
+	if !checkRuntimeISAlreadyExists(db, runtimeSpec) {
+		return append(errors, "runtime is not found")
+	}
+
+	var functions []string
+	_ = dao.GetAll(db, &types.Function{}, func(k, v []byte) error {
+
+		function := types.Function{}
+		json.Unmarshal(v, &function)
+
+		if function.Runtime == runtimeSpec.Name {
+			functions = append(functions, function.Name)
+		}
+
+		return nil
+	})
+
+	if functions != nil {
+		errors = append(errors, fmt.Sprintf("runtime is used by functions : %s", strings.Join(functions, ", ")))
+	}
+
+	return errors
+
+}
+
+func checkRuntimeISAlreadyExists(db storage.Store, runtimeSpec *types.RuntimeSpec) bool {
+
+	found := false
+	_ = dao.GetByID(db, &types.RuntimeSpec{Name: runtimeSpec.Name}, func(savedObj []byte) error {
+
+		if savedObj != nil {
+			found = true
+		}
+
+		return nil
+	})
+
+	return found
+}
@@ -0,0 +1,323 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+//Package leader elect a leader among manager replicas.
+//Every replica serves the api. background work which must run once in the cluster
+//(gc, function deploy reconciliation, schedulers) is run only by the leader.
+package leader
+
+import (
+	"log"
+	"quebic-faas/metrics"
+	"sync"
+	"time"
+)
+
+var leading = metrics.NewGauge(
+	"quebic_mgr_leader",
+	"1 when this manager replica is the leader")
+
+var leaderTransitions = metrics.NewCounter(
+	"quebic_mgr_leader_transitions_total",
+	"times this manager replica started or stopped leading",
+	"transition")
+
+//Record leader record kept by the lock
+type Record struct {
+	HolderIdentity       string    `json:"holderIdentity"`
+	LeaseDurationSeconds int       `json:"leaseDurationSeconds"`
+	AcquireTime          time.Time `json:"acquireTime"`
+	RenewTime            time.Time `json:"renewTime"`
+	LeaderTransitions    int       `json:"leaderTransitions"`
+}
+
+//Lock keeps the leader record.
+//Update must fail when the record has been changed after the last Get
+type Lock interface {
+	//Get nil when there is no record
+	Get() (*Record, error)
+	//Create fails when the record already exists
+	Create(record Record) error
+	Update(record Record) error
+	//Describe lock name for logs
+	Describe() string
+}
+
+//Task leader only work. stop is closed when leadership is lost
+type Task func(stop <-chan struct{})
+
+//Config elector timings
+// LeaseDuration : other replicas wait this long after the last renew before taking over
+// RenewDeadline : leader gives up when it is unable to renew within this
+// RetryPeriod : interval between acquire or renew attempts
+type Config struct {
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+//Elector leader elector
+type Elector struct {
+	identity string
+	lock     Lock
+	config   Config
+
+	mutex          sync.Mutex
+	observedRecord Record
+	observedTime   time.Time
+	lastRenew      time.Time
+	isLeader       bool
+	tasks          []Task
+	tasksStop      chan struct{}
+	stop           chan struct{}
+	wait           sync.WaitGroup
+}
+
+//NewElector nil lock => election is disabled and this replica is always the leader
+func NewElector(identity string, lock Lock, config Config) *Elector {
+	return &Elector{
+		identity: identity,
+		lock:     lock,
+		config:   config,
+		stop:     make(chan struct{}),
+	}
+}
+
+//Run start electing in background. tasks are started each time this replica becomes the leader
+func (elector *Elector) Run(tasks ...Task) {
+
+	elector.tasks = tasks
+
+	if elector.lock == nil {
+		elector.startLeading()
+		return
+	}
+
+	log.Printf("leader-election started. identity : %s, lock : %s", elector.identity, elector.lock.Describe())
+
+	elector.wait.Add(1)
+	go elector.loop()
+
+}
+
+//Stop stop electing. lease is released, so an other replica can take over without waiting for expiry
+func (elector *Elector) Stop() {
+
+	close(elector.stop)
+	elector.wait.Wait()
+
+	if elector.IsLeader() {
+		elector.stopLeading()
+		if elector.lock != nil {
+			elector.release()
+		}
+	}
+
+}
+
+//IsLeader is this replica the leader
+func (elector *Elector) IsLeader() bool {
+	elector.mutex.Lock()
+	defer elector.mutex.Unlock()
+	return elector.isLeader
+}
+
+//Leader identity of the current leader. empty when it is not known
+func (elector *Elector) Leader() string {
+	elector.mutex.Lock()
+	defer elector.mutex.Unlock()
+	if elector.lock == nil {
+		return elector.identity
+	}
+	return elector.observedRecord.HolderIdentity
+}
+
+//Identity identity of this replica
+func (elector *Elector) Identity() string {
+	return elector.identity
+}
+
+func (elector *Elector) loop() {
+
+	defer elector.wait.Done()
+
+	for {
+
+		if elector.tryAcquireOrRenew() {
+
+			if !elector.IsLeader() {
+				elector.startLeading()
+			}
+
+		} else if elector.IsLeader() && elector.sinceLastRenew() > elector.config.RenewDeadline {
+
+			log.Printf("leader-election unable to renew lease within %v. stop leading", elector.config.RenewDeadline)
+			elector.stopLeading()
+
+		}
+
+		select {
+		case <-elector.stop:
+			return
+		case <-time.After(elector.config.RetryPeriod):
+		}
+
+	}
+
+}
+
+func (elector *Elector) tryAcquireOrRenew() bool {
+
+	now := time.Now().UTC().Round(0)
+
+	record := Record{
+		HolderIdentity:       elector.identity,
+		LeaseDurationSeconds: int(elector.config.LeaseDuration / time.Second),
+		AcquireTime:          now,
+		RenewTime:            now,
+	}
+
+	oldRecord, err := elector.lock.Get()
+	if err != nil {
+		log.Printf("leader-election unable to get leader record : %v", err)
+		return false
+	}
+
+	if oldRecord == nil {
+
+		err = elector.lock.Create(record)
+		if err != nil {
+			log.Printf("leader-election unable to create leader record : %v", err)
+			return false
+		}
+
+		elector.observe(record, now)
+		return true
+
+	}
+
+	//expiry is measured with local clock from the time record has been changed. clocks of replicas may differ
+	if !sameRecord(*oldRecord, elector.observedRecord) {
+		elector.observe(*oldRecord, now)
+	}
+
+	leaseDuration := time.Duration(oldRecord.LeaseDurationSeconds) * time.Second
+	if oldRecord.HolderIdentity != "" &&
+		oldRecord.HolderIdentity != elector.identity &&
+		elector.observedTime.Add(leaseDuration).After(now) {
+		return false
+	}
+
+	if oldRecord.HolderIdentity == elector.identity {
+		record.AcquireTime = oldRecord.AcquireTime
+		record.LeaderTransitions = oldRecord.LeaderTransitions
+	} else {
+		record.LeaderTransitions = oldRecord.LeaderTransitions + 1
+	}
+
+	//fails when an other replica has updated the record in between
+	err = elector.lock.Update(record)
+	if err != nil {
+		return false
+	}
+
+	elector.observe(record, now)
+	return true
+
+}
+
+func (elector *Elector) observe(record Record, now time.Time) {
+
+	elector.mutex.Lock()
+	defer elector.mutex.Unlock()
+
+	elector.observedRecord = record
+	elector.observedTime = now
+
+	if record.HolderIdentity == elector.identity {
+		elector.lastRenew = now
+	}
+
+}
+
+func (elector *Elector) sinceLastRenew() time.Duration {
+	elector.mutex.Lock()
+	defer elector.mutex.Unlock()
+	return time.Since(elector.lastRenew)
+}
+
+func (elector *Elector) release() {
+
+	now := time.Now().UTC().Round(0)
+
+	oldRecord, err := elector.lock.Get()
+	if err != nil || oldRecord == nil || oldRecord.HolderIdentity != elector.identity {
+		return
+	}
+
+	err = elector.lock.Update(Record{
+		LeaseDurationSeconds: 1,
+		AcquireTime:          now,
+		RenewTime:            now,
+		LeaderTransitions:    oldRecord.LeaderTransitions,
+	})
+	if err != nil {
+		log.Printf("leader-election unable to release lease : %v", err)
+		return
+	}
+
+	log.Printf("leader-election lease released")
+
+}
+
+func (elector *Elector) startLeading() {
+
+	elector.mutex.Lock()
+	elector.isLeader = true
+	elector.tasksStop = make(chan struct{})
+	tasksStop := elector.tasksStop
+	elector.mutex.Unlock()
+
+	leading.Set(1)
+	leaderTransitions.Inc("started")
+
+	log.Printf("leader-election %s started leading", elector.identity)
+
+	for _, task := range elector.tasks {
+		go task(tasksStop)
+	}
+
+}
+
+func (elector *Elector) stopLeading() {
+
+	elector.mutex.Lock()
+	elector.isLeader = false
+	close(elector.tasksStop)
+	elector.mutex.Unlock()
+
+	leading.Set(0)
+	leaderTransitions.Inc("stopped")
+
+	log.Printf("leader-election %s stopped leading", elector.identity)
+
+}
+
+func sameRecord(a Record, b Record) bool {
+	return a.HolderIdentity == b.HolderIdentity &&
+		a.LeaseDurationSeconds == b.LeaseDurationSeconds &&
+		a.AcquireTime.Equal(b.AcquireTime) &&
+		a.RenewTime.Equal(b.RenewTime) &&
+		a.LeaderTransitions == b.LeaderTransitions
+}
@@ -0,0 +1,133 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package leader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"quebic-faas/quebic-faas-mgr/storage"
+)
+
+const leaderBucket = "Leader"
+
+//StorageLock leader record in the shared storage of the managers.
+//write transactions of the store are serializable, so only one replica can update the record at a time
+type StorageLock struct {
+	db       storage.Store
+	name     string
+	observed []byte
+}
+
+//NewStorageLock storage lock
+func NewStorageLock(db storage.Store, name string) *StorageLock {
+	return &StorageLock{db: db, name: name}
+}
+
+//Get implementation for Lock.Get()
+func (lock *StorageLock) Get() (*Record, error) {
+
+	var saved []byte
+
+	err := lock.db.View(func(tx storage.Tx) error {
+
+		bucket := tx.Bucket([]byte(leaderBucket))
+		if bucket == nil {
+			return nil
+		}
+
+		if v := bucket.Get([]byte(lock.name)); v != nil {
+			saved = append([]byte{}, v...)
+		}
+
+		return nil
+
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lock.observed = saved
+
+	if saved == nil {
+		return nil, nil
+	}
+
+	record := &Record{}
+	err = json.Unmarshal(saved, record)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse leader record, error : %v", err)
+	}
+
+	return record, nil
+
+}
+
+//Create implementation for Lock.Create()
+func (lock *StorageLock) Create(record Record) error {
+	return lock.put(record, func(current []byte) error {
+		if current != nil {
+			return fmt.Errorf("leader record %s already exists", lock.name)
+		}
+		return nil
+	})
+}
+
+//Update implementation for Lock.Update()
+func (lock *StorageLock) Update(record Record) error {
+	return lock.put(record, func(current []byte) error {
+		if !bytes.Equal(current, lock.observed) {
+			return fmt.Errorf("leader record %s has been changed", lock.name)
+		}
+		return nil
+	})
+}
+
+//Describe implementation for Lock.Describe()
+func (lock *StorageLock) Describe() string {
+	return "storage/" + lock.name
+}
+
+func (lock *StorageLock) put(record Record, check func(current []byte) error) error {
+
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed json parse, error : %v", err)
+	}
+
+	err = lock.db.Update(func(tx storage.Tx) error {
+
+		bucket, err := tx.CreateBucketIfNotExists([]byte(leaderBucket))
+		if err != nil {
+			return fmt.Errorf("unable to create bucket for %s, error : %v", leaderBucket, err)
+		}
+
+		err = check(bucket.Get([]byte(lock.name)))
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(lock.name), recordJSON)
+
+	})
+	if err != nil {
+		return err
+	}
+
+	lock.observed = recordJSON
+
+	return nil
+
+}
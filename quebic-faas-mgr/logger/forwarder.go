@@ -0,0 +1,236 @@
+package logger
+
+import (
+	"fmt"
+	"log"
+	"quebic-faas/metrics"
+	"quebic-faas/quebic-faas-mgr/config"
+	"quebic-faas/types"
+	"sync"
+	"time"
+)
+
+const forwarderDefaultBufferSize = 4096
+const forwarderDefaultBatchSize = 256
+const forwarderDefaultFlushInterval = time.Second * 5
+
+var forwarderDropped = metrics.NewCounter(
+	"quebic_mgr_log_forwarder_dropped_total",
+	"logs dropped because the forwarder buffer was full",
+	"forwarder")
+
+var forwarderFailed = metrics.NewCounter(
+	"quebic_mgr_log_forwarder_failed_total",
+	"logs which the forwarder failed to write into the sink",
+	"forwarder")
+
+//forwardedLog request-tracker log as written into sinks
+type forwardedLog struct {
+	RequestID string `json:"requestID"`
+	Function  string `json:"function"` //source of the request-tracker
+	Type      string `json:"type"`
+	Message   string `json:"message"`
+	Source    string `json:"source"`
+	Time      string `json:"time"`
+	Completed bool   `json:"completed,omitempty"`
+	Status    int    `json:"status,omitempty"`
+}
+
+//logSink external destination of forwarded logs
+type logSink interface {
+	write(logs []forwardedLog) error
+	close() error
+}
+
+//logForwarder buffer logs and write them into the sink in batches
+//saveLog never waits for the sink. logs are dropped when the buffer is full
+type logForwarder struct {
+	name          string
+	sink          logSink
+	batchSize     int
+	flushInterval time.Duration
+	queue         chan forwardedLog
+	done          chan bool
+	closed        bool
+	mutex         sync.RWMutex
+}
+
+//SetupForwarders start forwarding saved request-tracker logs into configured sinks
+func (logger *Logger) SetupForwarders(forwarderConfigs []config.LogForwarderConfig) error {
+
+	for i, forwarderConfig := range forwarderConfigs {
+
+		name := forwarderConfig.Name
+		if name == "" {
+			name = fmt.Sprintf("%s-%d", forwarderConfig.Type, i)
+		}
+
+		sink, batchSize, flushInterval, err := prepareLogSink(forwarderConfig)
+		if err != nil {
+			return fmt.Errorf("forwarder %s : %v", name, err)
+		}
+
+		bufferSize := forwarderConfig.BufferSize
+		if bufferSize <= 0 {
+			bufferSize = forwarderDefaultBufferSize
+		}
+
+		forwarder := &logForwarder{
+			name:          name,
+			sink:          sink,
+			batchSize:     batchSize,
+			flushInterval: flushInterval,
+			queue:         make(chan forwardedLog, bufferSize),
+			done:          make(chan bool),
+		}
+
+		go forwarder.run()
+
+		logger.forwarders = append(logger.forwarders, forwarder)
+
+		log.Printf("log-forwarder %s : forwarding logs to %s", name, forwarderConfig.Type)
+
+	}
+
+	return nil
+
+}
+
+//Close flush buffered logs into forwarders and stop them
+func (logger *Logger) Close() {
+	for _, forwarder := range logger.forwarders {
+		forwarder.stop()
+	}
+}
+
+func prepareLogSink(forwarderConfig config.LogForwarderConfig) (logSink, int, time.Duration, error) {
+
+	switch forwarderConfig.Type {
+	case config.LogForwarderFile:
+		sink, err := newFileSink(forwarderConfig.File)
+		return sink, forwarderDefaultBatchSize, forwarderDefaultFlushInterval, err
+
+	case config.LogForwarderSyslog:
+		sink, err := newSyslogSink(forwarderConfig.Syslog)
+		return sink, forwarderDefaultBatchSize, forwarderDefaultFlushInterval, err
+
+	case config.LogForwarderHTTP:
+		httpConfig := forwarderConfig.HTTP
+
+		batchSize := httpConfig.BatchSize
+		if batchSize <= 0 {
+			batchSize = forwarderDefaultBatchSize
+		}
+
+		flushInterval := forwarderDefaultFlushInterval
+		if httpConfig.FlushInterval > 0 {
+			flushInterval = time.Duration(httpConfig.FlushInterval) * time.Second
+		}
+
+		sink, err := newHTTPSink(httpConfig)
+		return sink, batchSize, flushInterval, err
+
+	default:
+		return nil, 0, 0, fmt.Errorf("unsupported forwarder type %s", forwarderConfig.Type)
+	}
+
+}
+
+//forward hand over the log into all forwarders without blocking
+func (logger *Logger) forward(requestTracker *types.RequestTracker, requestTrackerMessage types.RequestTrackerMessage) {
+
+	if len(logger.forwarders) == 0 {
+		return
+	}
+
+	l := requestTrackerMessage.Log
+
+	forwarded := forwardedLog{
+		RequestID: requestTracker.RequestID,
+		Function:  requestTracker.Source,
+		Type:      l.Type,
+		Message:   l.Message,
+		Source:    l.Source,
+		Time:      l.Time,
+		Completed: requestTrackerMessage.Completed,
+	}
+
+	if requestTrackerMessage.Completed {
+		forwarded.Status = requestTrackerMessage.Response.Status
+	}
+
+	for _, forwarder := range logger.forwarders {
+		forwarder.offer(forwarded)
+	}
+
+}
+
+func (forwarder *logForwarder) offer(l forwardedLog) {
+
+	forwarder.mutex.RLock()
+	defer forwarder.mutex.RUnlock()
+
+	if forwarder.closed {
+		return
+	}
+
+	select {
+	case forwarder.queue <- l:
+	default:
+		forwarderDropped.Inc(forwarder.name)
+	}
+
+}
+
+func (forwarder *logForwarder) stop() {
+
+	forwarder.mutex.Lock()
+	if forwarder.closed {
+		forwarder.mutex.Unlock()
+		return
+	}
+	forwarder.closed = true
+	close(forwarder.queue)
+	forwarder.mutex.Unlock()
+
+	<-forwarder.done
+
+}
+
+func (forwarder *logForwarder) run() {
+
+	batch := make([]forwardedLog, 0, forwarder.batchSize)
+	ticker := time.NewTicker(forwarder.flushInterval)
+	defer ticker.Stop()
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		err := forwarder.sink.write(batch)
+		if err != nil {
+			forwarderFailed.Add(float64(len(batch)), forwarder.name)
+			log.Printf("log-forwarder %s : failed to write %d logs, error : %v", forwarder.name, len(batch), err)
+		}
+		batch = make([]forwardedLog, 0, forwarder.batchSize)
+	}
+
+	for {
+		select {
+		case l, ok := <-forwarder.queue:
+			if !ok {
+				flush()
+				forwarder.sink.close()
+				forwarder.done <- true
+				return
+			}
+			batch = append(batch, l)
+			if len(batch) >= forwarder.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"quebic-faas/quebic-faas-mgr/config"
+)
+
+const fileSinkDefaultMaxSize = 100
+const fileSinkDefaultMaxBackups = 5
+
+//fileSink append logs as json lines. <path>.1 is the latest rotated file
+type fileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func newFileSink(fileConfig config.LogFileForwarderConfig) (*fileSink, error) {
+
+	if fileConfig.Path == "" {
+		return nil, fmt.Errorf("file path should not be empty")
+	}
+
+	maxSize := fileConfig.MaxSize
+	if maxSize <= 0 {
+		maxSize = fileSinkDefaultMaxSize
+	}
+
+	maxBackups := fileConfig.MaxBackups
+	if maxBackups <= 0 {
+		maxBackups = fileSinkDefaultMaxBackups
+	}
+
+	sink := &fileSink{
+		path:       fileConfig.Path,
+		maxSize:    int64(maxSize) * 1024 * 1024,
+		maxBackups: maxBackups,
+	}
+
+	err := os.MkdirAll(filepath.Dir(sink.path), 0755)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create log dir : %v", err)
+	}
+
+	err = sink.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return sink, nil
+
+}
+
+func (sink *fileSink) write(logs []forwardedLog) error {
+
+	for _, l := range logs {
+
+		line, err := json.Marshal(l)
+		if err != nil {
+			return err
+		}
+		line = append(line, '\n')
+
+		if sink.size+int64(len(line)) > sink.maxSize && sink.size > 0 {
+			err = sink.rotate()
+			if err != nil {
+				return err
+			}
+		}
+
+		n, err := sink.file.Write(line)
+		sink.size += int64(n)
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return nil
+
+}
+
+func (sink *fileSink) close() error {
+	return sink.file.Close()
+}
+
+func (sink *fileSink) open() error {
+
+	file, err := os.OpenFile(sink.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open log file : %v", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("unable to open log file : %v", err)
+	}
+
+	sink.file = file
+	sink.size = info.Size()
+
+	return nil
+
+}
+
+//rotate shift <path>.n into <path>.n+1, oldest beyond maxBackups is removed
+func (sink *fileSink) rotate() error {
+
+	err := sink.file.Close()
+	if err != nil {
+		return err
+	}
+
+	os.Remove(sink.backupPath(sink.maxBackups))
+
+	for i := sink.maxBackups - 1; i >= 1; i-- {
+		os.Rename(sink.backupPath(i), sink.backupPath(i+1))
+	}
+
+	err = os.Rename(sink.path, sink.backupPath(1))
+	if err != nil {
+		return fmt.Errorf("unable to rotate log file : %v", err)
+	}
+
+	return sink.open()
+
+}
+
+func (sink *fileSink) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", sink.path, n)
+}
@@ -0,0 +1,190 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"quebic-faas/common"
+	"quebic-faas/quebic-faas-mgr/config"
+	"strconv"
+	"time"
+)
+
+const httpSinkDefaultIndex = "quebic-faas-logs"
+const httpSinkLokiJob = "quebic-faas"
+
+//httpSink post logs in batches into Loki push api or Elasticsearch bulk api
+type httpSink struct {
+	url     string
+	format  string
+	index   string
+	headers map[string]string
+	client  *http.Client
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][]string        `json:"values"`
+}
+
+type elasticsearchBulkResponse struct {
+	Errors bool `json:"errors"`
+}
+
+func newHTTPSink(httpConfig config.LogHTTPForwarderConfig) (*httpSink, error) {
+
+	if httpConfig.URL == "" {
+		return nil, fmt.Errorf("url should not be empty")
+	}
+
+	if !(httpConfig.Format == config.LogFormatLoki || httpConfig.Format == config.LogFormatElasticsearch) {
+		return nil, fmt.Errorf("format is not match to any of thses formats ( loki , elasticsearch )")
+	}
+
+	index := httpConfig.Index
+	if index == "" {
+		index = httpSinkDefaultIndex
+	}
+
+	return &httpSink{
+		url:     httpConfig.URL,
+		format:  httpConfig.Format,
+		index:   index,
+		headers: httpConfig.Headers,
+		client:  &http.Client{Timeout: time.Second * 10},
+	}, nil
+
+}
+
+func (sink *httpSink) write(logs []forwardedLog) error {
+
+	var body []byte
+	var contentType string
+	var err error
+
+	if sink.format == config.LogFormatLoki {
+		body, err = prepareLokiPushRequest(logs)
+		contentType = "application/json"
+	} else {
+		body, err = prepareElasticsearchBulkRequest(sink.index, logs)
+		contentType = "application/x-ndjson"
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode logs : %v", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, sink.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", contentType)
+	for key, value := range sink.headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := sink.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s : %v", sink.format, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("%s responded %d", sink.format, response.StatusCode)
+	}
+
+	if sink.format == config.LogFormatElasticsearch {
+		bulkResponse := elasticsearchBulkResponse{}
+		json.NewDecoder(response.Body).Decode(&bulkResponse)
+		if bulkResponse.Errors {
+			return fmt.Errorf("elasticsearch rejected some of the logs")
+		}
+	}
+
+	return nil
+
+}
+
+func (sink *httpSink) close() error {
+	return nil
+}
+
+//prepareLokiPushRequest logs are grouped into streams by type. labels are kept low cardinality
+func prepareLokiPushRequest(logs []forwardedLog) ([]byte, error) {
+
+	streams := make(map[string]*lokiStream)
+	var order []string
+
+	for _, l := range logs {
+
+		line, err := json.Marshal(l)
+		if err != nil {
+			return nil, err
+		}
+
+		stream, ok := streams[l.Type]
+		if !ok {
+			stream = &lokiStream{Stream: map[string]string{"job": httpSinkLokiJob, "type": l.Type}}
+			streams[l.Type] = stream
+			order = append(order, l.Type)
+		}
+
+		timestamp := strconv.FormatInt(parseLogTime(l.Time).UnixNano(), 10)
+		stream.Values = append(stream.Values, []string{timestamp, string(line)})
+
+	}
+
+	request := lokiPushRequest{}
+	for _, logType := range order {
+		request.Streams = append(request.Streams, *streams[logType])
+	}
+
+	return json.Marshal(request)
+
+}
+
+func prepareElasticsearchBulkRequest(index string, logs []forwardedLog) ([]byte, error) {
+
+	action, err := json.Marshal(map[string]interface{}{"index": map[string]string{"_index": index}})
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	for _, l := range logs {
+
+		doc, err := json.Marshal(struct {
+			forwardedLog
+			Timestamp string `json:"@timestamp"`
+		}{l, parseLogTime(l.Time).Format(time.RFC3339Nano)})
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(action)
+		buffer.WriteByte('\n')
+		buffer.Write(doc)
+		buffer.WriteByte('\n')
+
+	}
+
+	return buffer.Bytes(), nil
+
+}
+
+//parseLogTime log time is in common.DefaultTimeLayout. falls back to now
+func parseLogTime(t string) time.Time {
+
+	parsed, err := time.ParseInLocation(common.DefaultTimeLayout, t, time.Local)
+	if err != nil {
+		return time.Now()
+	}
+
+	return parsed
+
+}
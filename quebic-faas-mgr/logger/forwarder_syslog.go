@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/syslog"
+	"quebic-faas/quebic-faas-mgr/config"
+	"strings"
+)
+
+const syslogSinkDefaultTag = "quebic-faas"
+
+//syslogSink write logs into syslog. severity is selected by log type
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+func newSyslogSink(syslogConfig config.LogSyslogForwarderConfig) (*syslogSink, error) {
+
+	tag := syslogConfig.Tag
+	if tag == "" {
+		tag = syslogSinkDefaultTag
+	}
+
+	writer, err := syslog.Dial(syslogConfig.Network, syslogConfig.Address, syslog.LOG_INFO|syslog.LOG_LOCAL0, tag)
+	if err != nil {
+		return nil, makeError("unable to connect syslog : %v", err)
+	}
+
+	return &syslogSink{writer: writer}, nil
+
+}
+
+func (sink *syslogSink) write(logs []forwardedLog) error {
+
+	for _, l := range logs {
+
+		message, err := json.Marshal(l)
+		if err != nil {
+			return err
+		}
+
+		switch strings.ToLower(l.Type) {
+		case "error":
+			err = sink.writer.Err(string(message))
+		case "warn", "warning":
+			err = sink.writer.Warning(string(message))
+		case "debug":
+			err = sink.writer.Debug(string(message))
+		default:
+			err = sink.writer.Info(string(message))
+		}
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return nil
+
+}
+
+func (sink *syslogSink) close() error {
+	return sink.writer.Close()
+}
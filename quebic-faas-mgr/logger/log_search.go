@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"fmt"
+	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/types"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//candidates loaded from index per search. keeps a broad search bounded
+const logSearchMaxCandidates = 5000
+
+//LogSearch filters for searching logs of request-trackers. empty fields are ignored
+// From, To : log time range in common.DefaultTimeLayout
+// Query : case-insensitive substring of the message
+// Regex : RE2 regular expression matched against the message
+type LogSearch struct {
+	Type   string
+	Source string
+	From   string
+	To     string
+	Query  string
+	Regex  string
+}
+
+//SearchRequestTrackers find request-trackers which have logs matched to the search
+func (logger *Logger) SearchRequestTrackers(search LogSearch, limit int) (*types.RequestTrackerSearchResults, error) {
+
+	if limit <= 0 {
+		limit = requestTrackerDefaultPageLimit
+	}
+
+	if limit > requestTrackerMaxPageLimit {
+		limit = requestTrackerMaxPageLimit
+	}
+
+	matcher, err := search.prepareMatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	requestIDs, err := dao.FindRequestTrackerCandidates(logger.db, search.prepareIndexQuery(), logSearchMaxCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	requestTrackers, err := dao.GetRequestTrackersByIDs(logger.db, requestIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &types.RequestTrackerSearchResults{
+		Results:   make([]types.RequestTrackerSearchResult, 0),
+		Truncated: len(requestIDs) >= logSearchMaxCandidates,
+	}
+
+	for _, requestTracker := range requestTrackers {
+
+		highlights := search.highlight(requestTracker, matcher)
+		if len(highlights) == 0 {
+			continue
+		}
+
+		results.Results = append(results.Results, types.RequestTrackerSearchResult{
+			RequestTracker: requestTracker,
+			Highlights:     highlights,
+		})
+
+	}
+
+	sort.Slice(results.Results, func(i, j int) bool {
+		return results.Results[i].RequestTracker.CreatedAt > results.Results[j].RequestTracker.CreatedAt
+	})
+
+	if len(results.Results) > limit {
+		results.Results = results.Results[:limit]
+		results.Truncated = true
+	}
+
+	return results, nil
+
+}
+
+func (search LogSearch) prepareMatcher() (*regexp.Regexp, error) {
+
+	if search.Query != "" {
+		return regexp.MustCompile("(?i)" + regexp.QuoteMeta(search.Query)), nil
+	}
+
+	if search.Regex != "" {
+		matcher, err := regexp.Compile(search.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex : %v", err)
+		}
+		return matcher, nil
+	}
+
+	return nil, nil
+
+}
+
+//prepareIndexQuery literal text of the search narrow candidates by trigrams
+func (search LogSearch) prepareIndexQuery() dao.LogIndexQuery {
+
+	query := dao.LogIndexQuery{
+		Type:   search.Type,
+		Source: search.Source,
+		From:   search.From,
+		To:     search.To,
+	}
+
+	literal := search.Query
+	if literal == "" && search.Regex != "" {
+		//case-insensitive flags are fine. trigrams are lowercased
+		if matcher, err := regexp.Compile(search.Regex); err == nil {
+			literal, _ = matcher.LiteralPrefix()
+		}
+	}
+
+	query.Trigrams = dao.PrepareTrigrams(literal)
+
+	return query
+
+}
+
+func (search LogSearch) highlight(requestTracker types.RequestTracker, matcher *regexp.Regexp) []types.LogHighlight {
+
+	var highlights []types.LogHighlight
+
+	for i, l := range requestTracker.Logs {
+
+		if search.Type != "" && !strings.EqualFold(l.Type, search.Type) {
+			continue
+		}
+
+		if search.Source != "" && l.Source != search.Source {
+			continue
+		}
+
+		if search.From != "" && l.Time < search.From {
+			continue
+		}
+
+		if search.To != "" && l.Time > search.To {
+			continue
+		}
+
+		matches := [][]int{}
+		if matcher != nil {
+			matches = matcher.FindAllStringIndex(l.Message, -1)
+			if len(matches) == 0 {
+				continue
+			}
+		}
+
+		highlights = append(highlights, types.LogHighlight{LogIndex: i, Log: l, Matches: matches})
+
+	}
+
+	return highlights
+
+}
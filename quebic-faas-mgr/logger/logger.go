@@ -8,23 +8,66 @@ import (
 	"log"
 	"quebic-faas/common"
 	_messenger "quebic-faas/messenger"
+	"quebic-faas/quebic-faas-mgr/config"
 	"quebic-faas/quebic-faas-mgr/dao"
+	"quebic-faas/quebic-faas-mgr/storage"
 	"quebic-faas/types"
-
-	bolt "github.com/coreos/bbolt"
+	"strings"
+	"time"
 )
 
+const requestTrackerDefaultPageLimit = 50
+const requestTrackerMaxPageLimit = 500
+
+//RequestTrackerStateCompleted function has replied
+const RequestTrackerStateCompleted = "completed"
+
+//RequestTrackerStatePending function has not replied yet
+const RequestTrackerStatePending = "pending"
+
 //Logger apigateway-logger
 type Logger struct {
-	db        *bolt.DB
-	messenger _messenger.Messenger
+	db            storage.Store
+	messenger     _messenger.Messenger
+	trackerConfig config.RequestTrackerConfig
+	watchHub      *trackerWatchHub
+	forwarders    []*logForwarder
+}
+
+//RequestTrackerFilter filters for listing request-trackers. empty fields are ignored
+// From, To : created time range in common.DefaultTimeLayout
+// Source : matched against tracker source and log sources
+type RequestTrackerFilter struct {
+	From   string
+	To     string
+	Source string
+	Status int
+	State  string
 }
 
 //Init init
-func (logger *Logger) Init(db *bolt.DB, messenger _messenger.Messenger) {
+func (logger *Logger) Init(db storage.Store, messenger _messenger.Messenger, trackerConfig config.RequestTrackerConfig) {
 
 	logger.db = db
 	logger.messenger = messenger
+	logger.watchHub = newTrackerWatchHub()
+
+	//config saved before retention settings
+	defaultConfig := config.DefaultRequestTrackerConfig()
+	if trackerConfig.TTL == 0 {
+		trackerConfig.TTL = defaultConfig.TTL
+	}
+	if trackerConfig.MaxTrackers == 0 {
+		trackerConfig.MaxTrackers = defaultConfig.MaxTrackers
+	}
+	if trackerConfig.MaxLogs == 0 {
+		trackerConfig.MaxLogs = defaultConfig.MaxLogs
+	}
+	if trackerConfig.CompactInterval <= 0 {
+		trackerConfig.CompactInterval = defaultConfig.CompactInterval
+	}
+
+	logger.trackerConfig = trackerConfig
 
 }
 
@@ -38,8 +81,18 @@ func (logger *Logger) Listen() error {
 		return fmt.Errorf("log-listener setup failed error : logger is not initialized")
 	}
 
+	err := dao.RebuildRequestTrackerIndex(db)
+	if err != nil {
+		return fmt.Errorf("log-listener setup failed error : %v", err)
+	}
+
+	err = dao.RebuildRequestTrackerLogIndex(db)
+	if err != nil {
+		return fmt.Errorf("log-listener setup failed error : %v", err)
+	}
+
 	//setup log listener
-	err := messenger.Subscribe(common.EventRequestTracker, func(event _messenger.BaseEvent) {
+	err = messenger.Subscribe(common.EventRequestTracker, func(event _messenger.BaseEvent) {
 		err := logger.saveLog(event)
 		if err != nil {
 			log.Printf("log-listener log event save failed error : %v", err)
@@ -81,27 +134,122 @@ func (logger *Logger) Listen() error {
 
 }
 
-//GetRequestTrackers get all logs for requestID
-func (logger *Logger) GetRequestTrackers() ([]types.RequestTracker, error) {
+//GetRequestTrackers get page of request-trackers which are matched to the filter. newest first
+func (logger *Logger) GetRequestTrackers(filter RequestTrackerFilter, cursor string, limit int) (*types.RequestTrackerPage, error) {
 
-	var requestTrackers []types.RequestTracker
-	err := dao.GetAll(logger.db, &types.RequestTracker{}, func(k, v []byte) error {
+	if limit <= 0 {
+		limit = requestTrackerDefaultPageLimit
+	}
 
-		requestTracker := types.RequestTracker{}
-		json.Unmarshal(v, &requestTracker)
-		requestTrackers = append(requestTrackers, requestTracker)
-		return nil
+	if limit > requestTrackerMaxPageLimit {
+		limit = requestTrackerMaxPageLimit
+	}
+
+	page := &types.RequestTrackerPage{RequestTrackers: make([]types.RequestTracker, 0)}
+	lastIndexKey := ""
+
+	err := dao.ScanRequestTrackers(logger.db, cursor, func(indexKey string, requestTracker types.RequestTracker) bool {
+
+		//index is in created order. nothing older can match
+		if filter.From != "" && requestTracker.CreatedAt < filter.From {
+			return false
+		}
+
+		if !filter.match(requestTracker) {
+			return true
+		}
+
+		if len(page.RequestTrackers) == limit {
+			page.NextCursor = lastIndexKey
+			return false
+		}
+
+		page.RequestTrackers = append(page.RequestTrackers, requestTracker)
+		lastIndexKey = indexKey
+
+		return true
 
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	if requestTrackers == nil {
-		requestTrackers = make([]types.RequestTracker, 0)
+	return page, nil
+
+}
+
+func (filter RequestTrackerFilter) match(requestTracker types.RequestTracker) bool {
+
+	if filter.To != "" && requestTracker.CreatedAt > filter.To {
+		return false
 	}
 
-	return requestTrackers, nil
+	if filter.Status != 0 && requestTracker.Response.Status != filter.Status {
+		return false
+	}
+
+	completed := requestTracker.CompletedAt != ""
+	if filter.State == RequestTrackerStateCompleted && !completed {
+		return false
+	}
+	if filter.State == RequestTrackerStatePending && completed {
+		return false
+	}
+
+	if filter.Source != "" {
+
+		if strings.Contains(requestTracker.Source, filter.Source) {
+			return true
+		}
+
+		for _, l := range requestTracker.Logs {
+			if strings.Contains(l.Source, filter.Source) {
+				return true
+			}
+		}
+
+		return false
+
+	}
+
+	return true
+
+}
+
+//Compact remove request-trackers which are beyond retention settings
+func (logger *Logger) Compact() (int, error) {
+
+	ttl := time.Hour * time.Duration(logger.trackerConfig.TTL)
+	createdBefore := ""
+	if ttl > 0 {
+		createdBefore = time.Now().Add(-ttl).Format(common.DefaultTimeLayout)
+	}
+
+	return dao.CompactRequestTrackers(logger.db, createdBefore, logger.trackerConfig.MaxTrackers)
+
+}
+
+//RunCompactor compact request-trackers periodically until stop is closed
+func (logger *Logger) RunCompactor(stop <-chan struct{}) {
+
+	interval := time.Minute * time.Duration(logger.trackerConfig.CompactInterval)
+
+	for {
+
+		removed, err := logger.Compact()
+		if err != nil {
+			log.Printf("request-tracker compaction failed : %v", err)
+		} else if removed > 0 {
+			log.Printf("request-tracker compaction removed %v trackers", removed)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+
+	}
 
 }
 
@@ -140,13 +288,27 @@ func (logger *Logger) saveLog(event _messenger.BaseEvent) error {
 
 	requestTracker.Logs = append(requestTracker.Logs, log)
 
+	//keep latest logs only
+	var removedLogs []types.Log
+	maxLogs := logger.trackerConfig.MaxLogs
+	if maxLogs > 0 && len(requestTracker.Logs) > maxLogs {
+		removedLogs = requestTracker.Logs[:len(requestTracker.Logs)-maxLogs]
+		requestTracker.Logs = requestTracker.Logs[len(requestTracker.Logs)-maxLogs:]
+	}
+
 	//check for completed
 	if requestTrackerMessage.Completed {
 		requestTracker.Response = requestTrackerMessage.Response
 		requestTracker.CompletedAt = log.Time
 	}
 
-	dao.Save(db, requestTracker)
+	err = dao.SaveRequestTracker(db, requestTracker, []types.Log{log}, removedLogs)
+	if err != nil {
+		return err
+	}
+
+	logger.watchHub.publish(requestTrackerMessage)
+	logger.forward(requestTracker, requestTrackerMessage)
 
 	return nil
 }
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"quebic-faas/types"
+	"sync"
+)
+
+const watchBufferSize = 64
+
+//RequestTrackerWatch select which request-tracker messages are followed
+// RequestID : messages of a single request
+// Function : messages of all requests which are touched by the function
+type RequestTrackerWatch struct {
+	RequestID string
+	Function  string
+}
+
+type trackerWatcher struct {
+	watch      RequestTrackerWatch
+	messages   chan types.RequestTrackerMessage
+	requestIDs map[string]bool //requests already matched to the function
+}
+
+//trackerWatchHub fan-out saved request-tracker messages into followers
+type trackerWatchHub struct {
+	watchers map[*trackerWatcher]bool
+	mutex    sync.Mutex
+}
+
+func newTrackerWatchHub() *trackerWatchHub {
+	return &trackerWatchHub{watchers: make(map[*trackerWatcher]bool)}
+}
+
+//Follow receive request-tracker messages as they are saved. cancel should be called to stop following
+func (logger *Logger) Follow(watch RequestTrackerWatch) (<-chan types.RequestTrackerMessage, func()) {
+
+	hub := logger.watchHub
+
+	watcher := &trackerWatcher{
+		watch:      watch,
+		messages:   make(chan types.RequestTrackerMessage, watchBufferSize),
+		requestIDs: make(map[string]bool),
+	}
+
+	hub.mutex.Lock()
+	hub.watchers[watcher] = true
+	hub.mutex.Unlock()
+
+	cancel := func() {
+		hub.mutex.Lock()
+		defer hub.mutex.Unlock()
+		if hub.watchers[watcher] {
+			delete(hub.watchers, watcher)
+			close(watcher.messages)
+		}
+	}
+
+	return watcher.messages, cancel
+
+}
+
+func (hub *trackerWatchHub) publish(message types.RequestTrackerMessage) {
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for watcher := range hub.watchers {
+
+		if !watcher.match(message) {
+			continue
+		}
+
+		//slow followers miss messages instead of blocking the log listener
+		select {
+		case watcher.messages <- message:
+		default:
+		}
+
+	}
+
+}
+
+func (watcher *trackerWatcher) match(message types.RequestTrackerMessage) bool {
+
+	watch := watcher.watch
+
+	if watch.RequestID != "" && watch.RequestID != message.RequestID {
+		return false
+	}
+
+	if watch.Function == "" {
+		return true
+	}
+
+	if watcher.requestIDs[message.RequestID] {
+		if message.Completed {
+			delete(watcher.requestIDs, message.RequestID)
+		}
+		return true
+	}
+
+	//log source is the function id
+	if message.Log.Source == watch.Function {
+		if !message.Completed {
+			watcher.requestIDs[message.RequestID] = true
+		}
+		return true
+	}
+
+	return false
+
+}
@@ -0,0 +1,93 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+//Seal encrypt password with the credentials key. AES-GCM, key is sha256 of the credentials key
+func Seal(credentialsKey string, password string) (string, error) {
+
+	gcm, err := newGCM(credentialsKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return "", fmt.Errorf("unable to seal password %v", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(password), nil)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+
+}
+
+//Open decrypt password which is sealed by Seal
+func Open(credentialsKey string, sealedPassword string) (string, error) {
+
+	if sealedPassword == "" {
+		return "", nil
+	}
+
+	gcm, err := newGCM(credentialsKey)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(sealedPassword)
+	if err != nil {
+		return "", fmt.Errorf("unable to open password %v", err)
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("unable to open password. sealed password is too short")
+	}
+
+	nonce := sealed[:gcm.NonceSize()]
+	password, err := gcm.Open(nil, nonce, sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to open password. credentials key is not the key which sealed it")
+	}
+
+	return string(password), nil
+
+}
+
+func newGCM(credentialsKey string) (cipher.AEAD, error) {
+
+	if credentialsKey == "" {
+		return nil, fmt.Errorf("credentials key of the manager is not configured")
+	}
+
+	key := sha256.Sum256([]byte(credentialsKey))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("unable to create cipher %v", err)
+	}
+
+	return cipher.NewGCM(block)
+
+}
@@ -0,0 +1,212 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//dockerHubAPI docker hub serves the registry api under another host
+const dockerHubAPI = "registry-1.docker.io"
+
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+var httpClient = &http.Client{Timeout: time.Second * 30}
+
+//Credentials opened credentials of a registry. empty username => anonymous
+type Credentials struct {
+	Username string
+	Password string
+}
+
+//ResolveDigest digest of the image manifest in the registry. eg: sha256:...
+//registry v2 api. token auth and basic auth are supported
+func ResolveDigest(image string, credentials Credentials) (string, error) {
+
+	ref := ParseImage(image)
+
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", apiBase(ref.Server), ref.Repository, ref.Reference)
+
+	response, err := manifestHead(manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+
+		authorization, err := authorize(response.Header.Get("Www-Authenticate"), ref, credentials)
+		if err != nil {
+			return "", err
+		}
+
+		response, err = manifestHead(manifestURL, authorization)
+		if err != nil {
+			return "", err
+		}
+
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to resolve digest of %s. registry replied %s", image, response.Status)
+	}
+
+	digest := response.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("unable to resolve digest of %s. registry did not reply the digest", image)
+	}
+
+	return digest, nil
+
+}
+
+func manifestHead(manifestURL string, authorization string) (*http.Response, error) {
+
+	request, err := http.NewRequest(http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("registry request failed %v", err)
+	}
+	response.Body.Close()
+
+	return response, nil
+
+}
+
+//authorize answer the auth challenge of the registry
+// Basic : credentials are sent
+// Bearer : token is taken from the realm of the challenge for pull scope of the repository
+func authorize(challenge string, ref ImageRef, credentials Credentials) (string, error) {
+
+	scheme, params := parseChallenge(challenge)
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+
+		if credentials.Username == "" {
+			return "", fmt.Errorf("registry %s requires credentials", ref.Server)
+		}
+
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials.Username+":"+credentials.Password)), nil
+
+	case "bearer":
+
+		realm := params["realm"]
+		if realm == "" {
+			return "", fmt.Errorf("registry %s auth challenge has no realm", ref.Server)
+		}
+
+		query := url.Values{}
+		if service := params["service"]; service != "" {
+			query.Set("service", service)
+		}
+		query.Set("scope", "repository:"+ref.Repository+":pull")
+
+		request, err := http.NewRequest(http.MethodGet, realm+"?"+query.Encode(), nil)
+		if err != nil {
+			return "", err
+		}
+
+		if credentials.Username != "" {
+			request.SetBasicAuth(credentials.Username, credentials.Password)
+		}
+
+		response, err := httpClient.Do(request)
+		if err != nil {
+			return "", fmt.Errorf("registry token request failed %v", err)
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("registry token request failed. registry replied %s", response.Status)
+		}
+
+		token := struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}{}
+		err = json.NewDecoder(response.Body).Decode(&token)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse registry token %v", err)
+		}
+
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+
+		return "Bearer " + token.Token, nil
+
+	default:
+		return "", fmt.Errorf("registry %s auth scheme %s is not supported", ref.Server, scheme)
+	}
+
+}
+
+//parseChallenge eg: Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func parseChallenge(challenge string) (string, map[string]string) {
+
+	params := make(map[string]string)
+
+	parts := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	if len(parts) < 2 {
+		return parts[0], params
+	}
+
+	for _, param := range strings.Split(parts[1], ",") {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) == 2 {
+			params[strings.ToLower(kv[0])] = strings.Trim(kv[1], "\"")
+		}
+	}
+
+	return parts[0], params
+
+}
+
+//apiBase local registries are served without tls
+func apiBase(server string) string {
+
+	if server == DefaultServer {
+		return "https://" + dockerHubAPI
+	}
+
+	host := strings.Split(server, ":")[0]
+	if host == "localhost" || host == "127.0.0.1" {
+		return "http://" + server
+	}
+
+	return "https://" + server
+
+}
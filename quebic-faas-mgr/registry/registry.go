@@ -0,0 +1,181 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+//Package registry docker registries of the functions images.
+//credentials of the registries, image pull secrets and image digests
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"quebic-faas/types"
+	"regexp"
+	"strings"
+
+	docker_types "github.com/docker/docker/api/types"
+)
+
+//DefaultServer registry of the images which are not qualified by a registry host
+const DefaultServer = "index.docker.io"
+
+//defaultServerConfigKey docker keeps docker hub credentials under this key
+const defaultServerConfigKey = "https://index.docker.io/v1/"
+
+const pullSecretPrefix = "quebic-faas-registry-"
+
+var invalidSecretNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+//ImageRef parts of an image reference
+// eg: registry.example.com:5000/team/hello:1.0.0 => registry.example.com:5000, team/hello, 1.0.0
+// Reference : tag or digest
+type ImageRef struct {
+	Server     string
+	Repository string
+	Reference  string
+}
+
+//ParseImage split image reference into registry host, repository and tag or digest
+func ParseImage(image string) ImageRef {
+
+	ref := ImageRef{Server: DefaultServer}
+
+	name := image
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Reference = name[i+1:]
+		name = name[:i]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Reference = name[i+1:]
+		name = name[:i]
+	}
+
+	if ref.Reference == "" {
+		ref.Reference = "latest"
+	}
+
+	//first component is a registry host when it looks like a host
+	if i := strings.Index(name, "/"); i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.Server = NormalizeServer(host)
+			name = name[i+1:]
+		}
+	}
+
+	//official images of docker hub
+	if ref.Server == DefaultServer && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+
+	ref.Repository = name
+
+	return ref
+
+}
+
+//Name image reference without tag or digest
+func (ref ImageRef) Name() string {
+	return ref.Server + "/" + ref.Repository
+}
+
+//IsDigest reference is a digest. eg: sha256:...
+func (ref ImageRef) IsDigest() bool {
+	return strings.Contains(ref.Reference, ":")
+}
+
+//WithDigest image reference which is pinned to the digest. tag or digest of the image is replaced
+func WithDigest(image string, digest string) string {
+
+	name := image
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+
+	return name + "@" + digest
+
+}
+
+//NormalizeServer docker hub has several names
+func NormalizeServer(server string) string {
+
+	server = strings.TrimSpace(strings.ToLower(server))
+	server = strings.TrimPrefix(server, "https://")
+	server = strings.TrimPrefix(server, "http://")
+	server = strings.TrimSuffix(server, "/")
+	server = strings.TrimSuffix(server, "/v1")
+
+	switch server {
+	case "docker.io", "registry-1.docker.io", "index.docker.io":
+		return DefaultServer
+	}
+
+	return server
+
+}
+
+//PullSecretName kubernetes image pull secret of the registry
+func PullSecretName(server string) string {
+	name := invalidSecretNameChars.ReplaceAllString(NormalizeServer(server), "-")
+	return pullSecretPrefix + strings.Trim(name, "-")
+}
+
+//AuthConfig docker auth of the registry. password is the opened password
+func AuthConfig(registry types.Registry, password string) docker_types.AuthConfig {
+	return docker_types.AuthConfig{
+		Username:      registry.Username,
+		Password:      password,
+		Email:         registry.Email,
+		ServerAddress: configKey(registry.Server),
+	}
+}
+
+//DockerConfigJSON content of the kubernetes.io/dockerconfigjson secret of the registry
+func DockerConfigJSON(registry types.Registry, password string) ([]byte, error) {
+
+	type auth struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Email    string `json:"email,omitempty"`
+		Auth     string `json:"auth"`
+	}
+
+	dockerConfig := struct {
+		Auths map[string]auth `json:"auths"`
+	}{
+		Auths: map[string]auth{
+			configKey(registry.Server): auth{
+				Username: registry.Username,
+				Password: password,
+				Email:    registry.Email,
+				Auth:     base64.StdEncoding.EncodeToString([]byte(registry.Username + ":" + password)),
+			},
+		},
+	}
+
+	return json.Marshal(dockerConfig)
+
+}
+
+func configKey(server string) string {
+
+	if NormalizeServer(server) == DefaultServer {
+		return defaultServerConfigKey
+	}
+
+	return server
+
+}
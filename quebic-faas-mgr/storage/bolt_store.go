@@ -0,0 +1,132 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package storage
+
+import (
+	bolt "github.com/coreos/bbolt"
+)
+
+const defaultBoltPath = ".quebic-faas-mgr.db"
+
+//boltStore single file store. can be opened by only one manager
+type boltStore struct {
+	db *bolt.DB
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+type boltBucket struct {
+	bucket *bolt.Bucket
+}
+
+//OpenBolt open bolt file. empty path => default db file
+func OpenBolt(path string) (Store, error) {
+
+	if path == "" {
+		path = defaultBoltPath
+	}
+
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &boltStore{db: db}, nil
+
+}
+
+func (store *boltStore) View(fn func(tx Tx) error) error {
+	return store.db.View(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+func (store *boltStore) Update(fn func(tx Tx) error) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+func (store *boltStore) Close() error {
+	return store.db.Close()
+}
+
+func (tx *boltTx) Bucket(name []byte) Bucket {
+
+	bucket := tx.tx.Bucket(name)
+	if bucket == nil {
+		return nil
+	}
+
+	return &boltBucket{bucket: bucket}
+
+}
+
+func (tx *boltTx) CreateBucket(name []byte) (Bucket, error) {
+
+	bucket, err := tx.tx.CreateBucket(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &boltBucket{bucket: bucket}, nil
+
+}
+
+func (tx *boltTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+
+	bucket, err := tx.tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &boltBucket{bucket: bucket}, nil
+
+}
+
+func (tx *boltTx) DeleteBucket(name []byte) error {
+	return tx.tx.DeleteBucket(name)
+}
+
+func (tx *boltTx) Size() int64 {
+	return tx.tx.Size()
+}
+
+func (bucket *boltBucket) Get(key []byte) []byte {
+	return bucket.bucket.Get(key)
+}
+
+func (bucket *boltBucket) Put(key []byte, value []byte) error {
+	return bucket.bucket.Put(key, value)
+}
+
+func (bucket *boltBucket) Delete(key []byte) error {
+	return bucket.bucket.Delete(key)
+}
+
+func (bucket *boltBucket) ForEach(fn func(k, v []byte) error) error {
+	return bucket.bucket.ForEach(fn)
+}
+
+//Cursor bolt cursor already satisfies Cursor
+func (bucket *boltBucket) Cursor() Cursor {
+	return bucket.bucket.Cursor()
+}
+
+func (bucket *boltBucket) Count() int {
+	return bucket.bucket.Stats().KeyN
+}
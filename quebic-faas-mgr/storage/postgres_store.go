@@ -0,0 +1,42 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package storage
+
+import (
+	"database/sql"
+	"strings"
+
+	//postgres driver. also works with postgres compatible databases. eg: CockroachDB
+	_ "github.com/lib/pq"
+)
+
+//postgresDialect shared database. several managers can use the same database
+var postgresDialect = sqlDialect{
+	driver: "postgres",
+	schema: []string{
+		"CREATE TABLE IF NOT EXISTS quebic_buckets (name TEXT PRIMARY KEY)",
+		"CREATE TABLE IF NOT EXISTS quebic_kv (bucket TEXT NOT NULL, k BYTEA NOT NULL, v BYTEA NOT NULL, PRIMARY KEY (bucket, k))",
+	},
+	viewOptions:  &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true},
+	writeOptions: &sql.TxOptions{Isolation: sql.LevelSerializable},
+	sizeQuery:    "SELECT pg_total_relation_size('quebic_kv') + pg_total_relation_size('quebic_buckets')",
+	numbered:     true,
+	retryable: func(err error) bool {
+		message := err.Error()
+		return strings.Contains(message, "could not serialize access") ||
+			strings.Contains(message, "deadlock detected") ||
+			strings.Contains(message, "40001")
+	},
+}
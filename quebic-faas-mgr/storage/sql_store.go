@@ -0,0 +1,437 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const sqlCursorPageSize = 256
+const sqlUpdateRetries = 5
+
+//sqlDialect differences between sql backends
+type sqlDialect struct {
+	driver       string
+	schema       []string
+	viewOptions  *sql.TxOptions
+	writeOptions *sql.TxOptions
+	sizeQuery    string
+	numbered     bool //placeholders are $1, $2 ...
+	retryable    func(err error) bool
+}
+
+//sqlStore buckets are rows of a key-value table
+type sqlStore struct {
+	db      *sql.DB
+	dialect sqlDialect
+}
+
+type sqlTx struct {
+	tx      *sql.Tx
+	dialect sqlDialect
+	err     error //first error of methods which can not return errors
+}
+
+type sqlBucket struct {
+	tx   *sqlTx
+	name string
+}
+
+type sqlRow struct {
+	key   []byte
+	value []byte
+}
+
+//sqlCursor rows are fetched in pages in the walking direction
+type sqlCursor struct {
+	bucket  *sqlBucket
+	key     []byte
+	atEnd   bool
+	page    []sqlRow
+	forward bool
+}
+
+//openSQL open sql store and create the schema
+func openSQL(dialect sqlDialect, dataSource string) (Store, error) {
+
+	if dataSource == "" {
+		return nil, fmt.Errorf("%s data source should not be empty", dialect.driver)
+	}
+
+	db, err := sql.Open(dialect.driver, dataSource)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, statement := range dialect.schema {
+		_, err = db.Exec(statement)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("unable to create schema : %v", err)
+		}
+	}
+
+	return &sqlStore{db: db, dialect: dialect}, nil
+
+}
+
+func (store *sqlStore) View(fn func(tx Tx) error) error {
+
+	tx, err := store.db.BeginTx(context.Background(), store.dialect.viewOptions)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	t := &sqlTx{tx: tx, dialect: store.dialect}
+
+	err = fn(t)
+	if err != nil {
+		return err
+	}
+
+	return t.err
+
+}
+
+//Update conflicting writes of other managers are retried
+func (store *sqlStore) Update(fn func(tx Tx) error) error {
+
+	var err error
+
+	for i := 0; i < sqlUpdateRetries; i++ {
+
+		err = store.update(fn)
+		if err == nil || store.dialect.retryable == nil || !store.dialect.retryable(err) {
+			return err
+		}
+
+	}
+
+	return err
+
+}
+
+func (store *sqlStore) update(fn func(tx Tx) error) error {
+
+	tx, err := store.db.BeginTx(context.Background(), store.dialect.writeOptions)
+	if err != nil {
+		return err
+	}
+
+	t := &sqlTx{tx: tx, dialect: store.dialect}
+
+	err = fn(t)
+	if err == nil {
+		err = t.err
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+
+}
+
+func (store *sqlStore) Close() error {
+	return store.db.Close()
+}
+
+func (tx *sqlTx) Bucket(name []byte) Bucket {
+
+	var exists int
+	err := tx.queryRow("SELECT 1 FROM quebic_buckets WHERE name = ?", string(name)).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		tx.fail(err)
+		return nil
+	}
+
+	return &sqlBucket{tx: tx, name: string(name)}
+
+}
+
+func (tx *sqlTx) CreateBucket(name []byte) (Bucket, error) {
+
+	if tx.Bucket(name) != nil {
+		return nil, fmt.Errorf("bucket already exists")
+	}
+
+	return tx.CreateBucketIfNotExists(name)
+
+}
+
+func (tx *sqlTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+
+	_, err := tx.exec("INSERT INTO quebic_buckets (name) VALUES (?) ON CONFLICT (name) DO NOTHING", string(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlBucket{tx: tx, name: string(name)}, nil
+
+}
+
+func (tx *sqlTx) DeleteBucket(name []byte) error {
+
+	_, err := tx.exec("DELETE FROM quebic_kv WHERE bucket = ?", string(name))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.exec("DELETE FROM quebic_buckets WHERE name = ?", string(name))
+
+	return err
+
+}
+
+func (tx *sqlTx) Size() int64 {
+
+	var size int64
+	err := tx.queryRow(tx.dialect.sizeQuery).Scan(&size)
+	if err != nil {
+		return 0
+	}
+
+	return size
+
+}
+
+func (tx *sqlTx) fail(err error) {
+	if tx.err == nil {
+		tx.err = err
+	}
+}
+
+func (tx *sqlTx) exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.tx.Exec(tx.rebind(query), args...)
+}
+
+func (tx *sqlTx) queryRow(query string, args ...interface{}) *sql.Row {
+	return tx.tx.QueryRow(tx.rebind(query), args...)
+}
+
+//queryRows read all rows. other statements can not run in the transaction while rows are open
+func (tx *sqlTx) queryRows(query string, args ...interface{}) ([]sqlRow, error) {
+
+	rows, err := tx.tx.Query(tx.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []sqlRow
+	for rows.Next() {
+		row := sqlRow{}
+		err := rows.Scan(&row.key, &row.value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+
+}
+
+//rebind ? => $n for numbered placeholders
+func (tx *sqlTx) rebind(query string) string {
+
+	if !tx.dialect.numbered {
+		return query
+	}
+
+	var builder strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			builder.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+
+}
+
+func (bucket *sqlBucket) Get(key []byte) []byte {
+
+	var value []byte
+	err := bucket.tx.queryRow("SELECT v FROM quebic_kv WHERE bucket = ? AND k = ?", bucket.name, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		bucket.tx.fail(err)
+		return nil
+	}
+
+	if value == nil {
+		value = []byte{}
+	}
+
+	return value
+
+}
+
+func (bucket *sqlBucket) Put(key []byte, value []byte) error {
+
+	if len(key) == 0 {
+		return fmt.Errorf("key required")
+	}
+
+	if value == nil {
+		value = []byte{}
+	}
+
+	_, err := bucket.tx.exec(
+		"INSERT INTO quebic_kv (bucket, k, v) VALUES (?, ?, ?) ON CONFLICT (bucket, k) DO UPDATE SET v = excluded.v",
+		bucket.name, key, value)
+
+	return err
+
+}
+
+func (bucket *sqlBucket) Delete(key []byte) error {
+	_, err := bucket.tx.exec("DELETE FROM quebic_kv WHERE bucket = ? AND k = ?", bucket.name, key)
+	return err
+}
+
+func (bucket *sqlBucket) ForEach(fn func(k, v []byte) error) error {
+
+	rows, err := bucket.tx.queryRows("SELECT k, v FROM quebic_kv WHERE bucket = ? ORDER BY k", bucket.name)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		err := fn(row.key, row.value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+
+}
+
+func (bucket *sqlBucket) Cursor() Cursor {
+	return &sqlCursor{bucket: bucket}
+}
+
+func (bucket *sqlBucket) Count() int {
+
+	var count int
+	err := bucket.tx.queryRow("SELECT COUNT(*) FROM quebic_kv WHERE bucket = ?", bucket.name).Scan(&count)
+	if err != nil {
+		bucket.tx.fail(err)
+		return 0
+	}
+
+	return count
+
+}
+
+func (c *sqlCursor) First() ([]byte, []byte) {
+	return c.fetch(true, "SELECT k, v FROM quebic_kv WHERE bucket = ? ORDER BY k LIMIT ?", c.bucket.name, sqlCursorPageSize)
+}
+
+func (c *sqlCursor) Last() ([]byte, []byte) {
+	return c.fetch(false, "SELECT k, v FROM quebic_kv WHERE bucket = ? ORDER BY k DESC LIMIT ?", c.bucket.name, sqlCursorPageSize)
+}
+
+func (c *sqlCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.fetch(true, "SELECT k, v FROM quebic_kv WHERE bucket = ? AND k >= ? ORDER BY k LIMIT ?", c.bucket.name, seek, sqlCursorPageSize)
+}
+
+func (c *sqlCursor) Next() ([]byte, []byte) {
+
+	if c.atEnd {
+		return nil, nil
+	}
+
+	if c.forward && len(c.page) > 0 {
+		return c.pop()
+	}
+
+	if c.key == nil {
+		return c.First()
+	}
+
+	return c.fetch(true, "SELECT k, v FROM quebic_kv WHERE bucket = ? AND k > ? ORDER BY k LIMIT ?", c.bucket.name, c.key, sqlCursorPageSize)
+
+}
+
+func (c *sqlCursor) Prev() ([]byte, []byte) {
+
+	//moved past the last key. previous one is the last key
+	if c.atEnd {
+		return c.Last()
+	}
+
+	if !c.forward && len(c.page) > 0 {
+		return c.pop()
+	}
+
+	if c.key == nil {
+		return nil, nil
+	}
+
+	return c.fetch(false, "SELECT k, v FROM quebic_kv WHERE bucket = ? AND k < ? ORDER BY k DESC LIMIT ?", c.bucket.name, c.key, sqlCursorPageSize)
+
+}
+
+//fetch load next page of the direction and move to its first row
+func (c *sqlCursor) fetch(forward bool, query string, args ...interface{}) ([]byte, []byte) {
+
+	rows, err := c.bucket.tx.queryRows(query, args...)
+	if err != nil {
+		c.bucket.tx.fail(err)
+		rows = nil
+	}
+
+	c.forward = forward
+	c.page = rows
+
+	if len(rows) == 0 {
+		c.key = nil
+		c.atEnd = forward
+		return nil, nil
+	}
+
+	c.atEnd = false
+
+	return c.pop()
+
+}
+
+func (c *sqlCursor) pop() ([]byte, []byte) {
+
+	row := c.page[0]
+	c.page = c.page[1:]
+	c.key = row.key
+
+	return row.key, row.value
+
+}
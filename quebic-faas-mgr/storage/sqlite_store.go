@@ -0,0 +1,59 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package storage
+
+import (
+	"strings"
+)
+
+const defaultSQLitePath = ".quebic-faas-mgr.sqlite"
+
+//sqliteDialect embedded single file database
+var sqliteDialect = sqlDialect{
+	driver: "sqlite3",
+	schema: []string{
+		"CREATE TABLE IF NOT EXISTS quebic_buckets (name TEXT PRIMARY KEY)",
+		"CREATE TABLE IF NOT EXISTS quebic_kv (bucket TEXT NOT NULL, k BLOB NOT NULL, v BLOB NOT NULL, PRIMARY KEY (bucket, k))",
+	},
+	sizeQuery: "SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()",
+	retryable: func(err error) bool {
+		return strings.Contains(err.Error(), "database is locked")
+	},
+}
+
+//prepareSQLiteDataSource wal journal lets readers and the writer run together.
+//busy timeout wait for locks of other connections instead of failing
+func prepareSQLiteDataSource(path string) string {
+
+	params := []string{}
+	if !strings.Contains(path, "_journal_mode") {
+		params = append(params, "_journal_mode=WAL")
+	}
+	if !strings.Contains(path, "_busy_timeout") {
+		params = append(params, "_busy_timeout=5000")
+	}
+
+	if len(params) == 0 {
+		return path
+	}
+
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+
+	return path + separator + strings.Join(params, "&")
+
+}
@@ -0,0 +1,89 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+//Package storage key-value store of the manager state.
+//Entities are saved in buckets as sorted keys. bolt is the default backend,
+//sql backends let several manager replicas share the state.
+package storage
+
+import (
+	"fmt"
+	"quebic-faas/quebic-faas-mgr/config"
+)
+
+//Store transactional bucket store
+type Store interface {
+	//View read-only transaction. all reads see the same snapshot
+	View(fn func(tx Tx) error) error
+	//Update read-write transaction. changes are committed when fn returns nil
+	Update(fn func(tx Tx) error) error
+	Close() error
+}
+
+//Tx transaction of a store. valid only inside View or Update
+type Tx interface {
+	//Bucket return nil when the bucket is not exists
+	Bucket(name []byte) Bucket
+	CreateBucket(name []byte) (Bucket, error)
+	CreateBucketIfNotExists(name []byte) (Bucket, error)
+	DeleteBucket(name []byte) error
+	//Size storage size in bytes
+	Size() int64
+}
+
+//Bucket sorted key-value collection
+type Bucket interface {
+	//Get return nil when the key is not exists
+	Get(key []byte) []byte
+	Put(key []byte, value []byte) error
+	Delete(key []byte) error
+	//ForEach walk keys in order. returned error stops the walk
+	ForEach(fn func(k, v []byte) error) error
+	Cursor() Cursor
+	//Count number of keys
+	Count() int
+}
+
+//Cursor walk keys of a bucket in order. nil key => no more keys
+type Cursor interface {
+	First() ([]byte, []byte)
+	Last() ([]byte, []byte)
+	//Seek move to the key or the next key after it
+	Seek(seek []byte) ([]byte, []byte)
+	Next() ([]byte, []byte)
+	Prev() ([]byte, []byte)
+}
+
+//Open open store of the configured backend
+func Open(storageConfig config.StorageConfig) (Store, error) {
+
+	switch storageConfig.Backend {
+	case "", config.StorageBolt:
+		return OpenBolt(storageConfig.Path)
+	case config.StorageSQLite:
+		if !sqliteAvailable {
+			return nil, fmt.Errorf("sqlite backend is not available. manager is built without cgo (CGO_ENABLED=0), which sqlite driver requires. use bolt or postgres, or build the manager with cgo")
+		}
+		path := storageConfig.Path
+		if path == "" {
+			path = defaultSQLitePath
+		}
+		return openSQL(sqliteDialect, prepareSQLiteDataSource(path))
+	case config.StoragePostgres:
+		return openSQL(postgresDialect, storageConfig.DataSource)
+	default:
+		return nil, fmt.Errorf("unsupported storage backend %s", storageConfig.Backend)
+	}
+
+}
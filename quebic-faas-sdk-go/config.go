@@ -0,0 +1,120 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package quebic
+
+import (
+	"fmt"
+	"os"
+	"quebic-faas/common"
+	"quebic-faas/config"
+	"strings"
+	"time"
+)
+
+//runtimeConfig function container configuration prepared by the manager
+type runtimeConfig struct {
+	appID        string
+	deploymentID string
+	version      string
+	eventBus     config.EventBusConfig
+	tracing      config.TracingConfig
+
+	eventLog             string
+	eventFunctionAwake   string
+	eventDataFetch       string
+	eventNewVersion      string
+	eventShutDownRequest string
+
+	events       []string
+	functionPath string
+	functionAge  time.Duration //0 means function never become idle
+}
+
+func loadConfig() (runtimeConfig, error) {
+
+	config := runtimeConfig{
+		appID:        os.Getenv(common.EnvKey_appID),
+		deploymentID: os.Getenv(common.EnvKey_deploymentID),
+		version:      os.Getenv(common.EnvKey_version),
+		eventBus: config.EventBusConfig{
+			AMQPHost:           os.Getenv(common.EnvKey_rabbitmq_host),
+			AMQPPort:           common.StrToInt(os.Getenv(common.EnvKey_rabbitmq_port)),
+			ManagementUserName: os.Getenv(common.EnvKey_rabbitmq_management_username),
+			ManagementPassword: os.Getenv(common.EnvKey_rabbitmq_management_password),
+		},
+		tracing: config.TracingConfig{
+			Exporter:     os.Getenv(common.EnvKey_tracing_exporter),
+			OTLPEndpoint: os.Getenv(common.EnvKey_tracing_otlpEndpoint),
+			FilePath:     os.Getenv(common.EnvKey_tracing_filePath),
+		},
+		eventLog:             os.Getenv(common.EnvKey_eventConst_eventLog),
+		eventFunctionAwake:   os.Getenv(common.EnvKey_eventConst_eventFunctionAwake),
+		eventDataFetch:       os.Getenv(common.EnvKey_eventConst_eventDataFetch),
+		eventNewVersion:      os.Getenv(common.EnvKey_eventConst_eventNewVersion),
+		eventShutDownRequest: os.Getenv(common.EnvKey_eventConst_eventShutDownRequest),
+		functionPath:         os.Getenv(common.EnvKey_functionPath),
+	}
+
+	if config.appID == "" {
+		return config, fmt.Errorf("%s env is not set. function should be deployed through quebic manager", common.EnvKey_appID)
+	}
+
+	if config.functionPath == "" {
+		return config, fmt.Errorf("%s env is not set", common.EnvKey_functionPath)
+	}
+
+	//events eg: e1,e2,e3
+	for _, event := range strings.Split(os.Getenv(common.EnvKey_events), ",") {
+		if event != "" {
+			config.events = append(config.events, event)
+		}
+	}
+
+	functionAge, err := parseFunctionAge(os.Getenv(common.EnvKey_functionAge))
+	if err != nil {
+		return config, err
+	}
+	config.functionAge = functionAge
+
+	return config, nil
+
+}
+
+//parseFunctionAge functionAge eg: minutes:4
+func parseFunctionAge(functionAge string) (time.Duration, error) {
+
+	if functionAge == "" || functionAge == ":0" {
+		return 0, nil
+	}
+
+	s := strings.Split(functionAge, ":")
+	if len(s) != 2 {
+		return 0, fmt.Errorf("%s is invalide %s", common.EnvKey_functionAge, functionAge)
+	}
+
+	timeout := time.Duration(common.StrToInt(s[1]))
+
+	switch s[0] {
+	case "seconds":
+		return timeout * time.Second, nil
+	case "minutes", "":
+		return timeout * time.Minute, nil
+	case "hours":
+		return timeout * time.Hour, nil
+	}
+
+	return 0, fmt.Errorf("%s timeunit is invalide %s", common.EnvKey_functionAge, s[0])
+
+}
@@ -0,0 +1,72 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package quebic
+
+import (
+	"log"
+	"quebic-faas/common"
+	"quebic-faas/types"
+	"time"
+)
+
+const logTypeInfo = "INFO"
+const logTypeError = "ERROR"
+const logTypeWarn = "WARN"
+
+//Logger attach logs into request tracker of the request
+type Logger struct {
+	runtime   *functionRuntime
+	requestID string
+}
+
+//Info info log
+func (logger Logger) Info(message string) {
+	logger.log(logTypeInfo, message)
+}
+
+//Error error log
+func (logger Logger) Error(message string) {
+	logger.log(logTypeError, message)
+}
+
+//Warn warn log
+func (logger Logger) Warn(message string) {
+	logger.log(logTypeWarn, message)
+}
+
+func (logger Logger) log(logType string, message string) {
+
+	eventLog := logger.runtime.config.eventLog
+	if eventLog == "" || logger.requestID == "" {
+		log.Printf("%s : %s", logType, message)
+		return
+	}
+
+	requestTrackerMessage := types.RequestTrackerMessage{
+		RequestID: logger.requestID,
+		Log: types.Log{
+			Message: message,
+			Source:  logger.runtime.config.appID,
+			Time:    time.Now().Format(common.DefaultTimeLayout),
+			Type:    logType,
+		},
+	}
+
+	_, err := logger.runtime.messenger.Publish(eventLog, requestTrackerMessage, nil, nil, nil, 0)
+	if err != nil {
+		log.Printf("log publish failed %v", err)
+	}
+
+}
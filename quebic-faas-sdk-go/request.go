@@ -0,0 +1,123 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package quebic
+
+import (
+	"log"
+	"quebic-faas/messenger"
+	"sync"
+	"time"
+)
+
+//Request event comes into the function
+type Request struct {
+	event messenger.BaseEvent
+}
+
+//BaseEvent event details
+func (request Request) BaseEvent() messenger.BaseEvent {
+	return request.event
+}
+
+//EventID event id
+func (request Request) EventID() string {
+	return request.event.GetEventID()
+}
+
+//RequestID request id
+func (request Request) RequestID() string {
+	return request.event.GetRequestID()
+}
+
+//Payload raw payload
+func (request Request) Payload() []byte {
+	return request.event.GetPayload()
+}
+
+//ParsePayload parse payload into given object
+func (request Request) ParsePayload(payloadObject interface{}) error {
+	return request.event.ParsePayloadAsObject(payloadObject)
+}
+
+//Header header value of the request
+func (request Request) Header(key string) string {
+	return request.event.GetHeaderData(key)
+}
+
+//Context handler context
+type Context struct {
+	runtime *functionRuntime
+	event   messenger.BaseEvent
+}
+
+//Messenger messenger to publish events
+func (context Context) Messenger() *messenger.Messenger {
+	return context.runtime.messenger
+}
+
+//Logger logger attach logs into the request context
+func (context Context) Logger() Logger {
+	return Logger{runtime: context.runtime, requestID: context.event.GetRequestID()}
+}
+
+//FunctionID id of the function
+func (context Context) FunctionID() string {
+	return context.runtime.config.appID
+}
+
+//Version running version of the function
+func (context Context) Version() string {
+	return context.runtime.config.version
+}
+
+//CallBack reply the caller. only first reply is sent
+type CallBack struct {
+	runtime *functionRuntime
+	event   messenger.BaseEvent
+	start   time.Time
+	once    sync.Once
+}
+
+//Reply reply success response
+func (callback *CallBack) Reply(payload interface{}, statuscode int) {
+
+	callback.once.Do(func() {
+
+		err := callback.runtime.messenger.ReplySuccess(callback.event, payload, statuscode)
+		if err != nil {
+			log.Printf("reply failed for %s, cause : %v", callback.event.GetRequestID(), err)
+		}
+
+		callback.runtime.recordInvocation(callback.event.GetEventID(), statuscode, callback.start)
+
+	})
+
+}
+
+//Error reply error response
+func (callback *CallBack) Error(err error, statuscode int) {
+
+	callback.once.Do(func() {
+
+		replyErr := callback.runtime.messenger.ReplyError(callback.event, err.Error(), statuscode)
+		if replyErr != nil {
+			log.Printf("reply failed for %s, cause : %v", callback.event.GetRequestID(), replyErr)
+		}
+
+		callback.runtime.recordInvocation(callback.event.GetEventID(), statuscode, callback.start)
+
+	})
+
+}
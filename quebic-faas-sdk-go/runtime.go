@@ -0,0 +1,277 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package quebic
+
+import (
+	"fmt"
+	"log"
+	"quebic-faas/common"
+	"quebic-faas/messenger"
+	"quebic-faas/types"
+	"sync"
+	"time"
+)
+
+const eventbusConnectTimeout = time.Minute * 2
+const invocationsReportInterval = time.Second * 10
+
+//functionRuntime serve events of the function
+type functionRuntime struct {
+	config    runtimeConfig
+	handler   RequestHandler
+	messenger *messenger.Messenger
+
+	mutex             sync.Mutex
+	lastInvoked       time.Time
+	invocations       []types.FunctionInvocation
+	shutdownRequested bool
+	shutdown          chan struct{}
+	shutdownOnce      sync.Once
+}
+
+func newFunctionRuntime(config runtimeConfig, handler RequestHandler) *functionRuntime {
+	return &functionRuntime{
+		config:  config,
+		handler: handler,
+		messenger: &messenger.Messenger{
+			AppID:          config.appID,
+			EventBusConfig: config.eventBus,
+		},
+		lastInvoked: time.Now(),
+		shutdown:    make(chan struct{}),
+	}
+}
+
+func (runtime *functionRuntime) run() error {
+
+	err := connect(runtime.messenger)
+	if err != nil {
+		return err
+	}
+	defer runtime.messenger.Close()
+
+	for _, event := range runtime.config.events {
+		err = runtime.messenger.Subscribe(event, runtime.serve, common.ConsumerFunctionRequestPrefix)
+		if err != nil {
+			return fmt.Errorf("unable to subscribe %s, error : %v", event, err)
+		}
+	}
+
+	err = runtime.watchNewVersion()
+	if err != nil {
+		return err
+	}
+
+	//manager wait for this event before forward requests which woke up the function
+	if runtime.config.eventFunctionAwake != "" {
+		_, err = runtime.messenger.Publish(
+			runtime.config.eventFunctionAwake,
+			types.FunctionData{Version: runtime.config.version},
+			nil,
+			nil,
+			nil,
+			0)
+		if err != nil {
+			log.Printf("function-awake publish failed %v", err)
+		}
+	}
+
+	log.Printf("%s started. version : %s, handler : %s", runtime.config.appID, runtime.config.version, runtime.config.functionPath)
+
+	ticker := time.NewTicker(invocationsReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			runtime.reportInvocations()
+			runtime.checkIdle()
+		case <-runtime.shutdown:
+			runtime.reportInvocations()
+			log.Printf("%s stopped", runtime.config.appID)
+			return nil
+		}
+	}
+
+}
+
+//serve dispatch event into handler. handlers run concurrently
+func (runtime *functionRuntime) serve(event messenger.BaseEvent) {
+
+	runtime.mutex.Lock()
+	runtime.lastInvoked = time.Now()
+	runtime.mutex.Unlock()
+
+	go func() {
+
+		callback := &CallBack{runtime: runtime, event: event, start: time.Now()}
+
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("handler panic for %s, cause : %v", event.GetRequestID(), r)
+				callback.Error(fmt.Errorf("%v", r), 500)
+			}
+		}()
+
+		context := Context{
+			runtime: runtime,
+			event:   event,
+		}
+
+		runtime.handler(Request{event: event}, context, callback)
+
+	}()
+
+}
+
+//watchNewVersion new version of the function is deployed, old one requests to shutdown itself
+// new version event is consumed per deployment, so every version receives it
+func (runtime *functionRuntime) watchNewVersion() error {
+
+	if runtime.config.eventNewVersion == "" {
+		return nil
+	}
+
+	versionMessenger := &messenger.Messenger{
+		AppID:          runtime.config.deploymentID,
+		EventBusConfig: runtime.config.eventBus,
+	}
+
+	err := connect(versionMessenger)
+	if err != nil {
+		return err
+	}
+
+	return versionMessenger.Subscribe(runtime.config.eventNewVersion, func(event messenger.BaseEvent) {
+
+		newVersionMessage := types.NewVersionMessage{}
+		event.ParsePayloadAsObject(&newVersionMessage)
+
+		if newVersionMessage.Version == runtime.config.version {
+			return
+		}
+
+		log.Printf("new version %s is available. requesting shutdown", newVersionMessage.Version)
+		runtime.requestShutdown()
+
+	}, common.ConsumerFunctionRequestPrefix)
+
+}
+
+//checkIdle function has not been invoked for functionAge, requests to shutdown itself
+func (runtime *functionRuntime) checkIdle() {
+
+	if runtime.config.functionAge == 0 {
+		return
+	}
+
+	runtime.mutex.Lock()
+	idle := time.Since(runtime.lastInvoked)
+	runtime.mutex.Unlock()
+
+	if idle < runtime.config.functionAge {
+		return
+	}
+
+	log.Printf("function is idle for %v. requesting shutdown", idle)
+	runtime.requestShutdown()
+
+}
+
+//requestShutdown manager removes the deployment. container is stopped by the manager
+func (runtime *functionRuntime) requestShutdown() {
+
+	if runtime.config.eventShutDownRequest == "" {
+		return
+	}
+
+	runtime.mutex.Lock()
+	requested := runtime.shutdownRequested
+	runtime.shutdownRequested = true
+	runtime.mutex.Unlock()
+
+	if requested {
+		return
+	}
+
+	_, err := runtime.messenger.Publish(
+		runtime.config.eventShutDownRequest,
+		types.ShutDownRequest{DeploymentID: runtime.config.deploymentID},
+		nil,
+		nil,
+		nil,
+		0)
+	if err != nil {
+		log.Printf("shutdown-request publish failed %v", err)
+	}
+
+}
+
+func (runtime *functionRuntime) stop() {
+	runtime.shutdownOnce.Do(func() {
+		close(runtime.shutdown)
+	})
+}
+
+func (runtime *functionRuntime) recordInvocation(event string, statuscode int, start time.Time) {
+
+	runtime.mutex.Lock()
+	defer runtime.mutex.Unlock()
+
+	runtime.invocations = append(runtime.invocations, types.FunctionInvocation{
+		Event:          event,
+		Status:         statuscode,
+		DurationMillis: float64(time.Since(start)) / float64(time.Millisecond),
+	})
+
+}
+
+func (runtime *functionRuntime) reportInvocations() {
+
+	runtime.mutex.Lock()
+	invocations := runtime.invocations
+	runtime.invocations = nil
+	runtime.mutex.Unlock()
+
+	err := runtime.messenger.ReportInvocations(runtime.config.appID, invocations)
+	if err != nil {
+		log.Printf("%v", err)
+	}
+
+}
+
+//connect retry until eventbus is available
+func connect(m *messenger.Messenger) error {
+
+	deadline := time.Now().Add(eventbusConnectTimeout)
+
+	log.Printf("waiting for connecting to eventbus...")
+
+	for {
+
+		err := m.Init()
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("eventbus connect failed. reason : %v", err)
+		}
+
+		time.Sleep(time.Second * 2)
+
+	}
+
+}
@@ -0,0 +1,78 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+//Package quebic go sdk for quebic functions
+//
+//function container is configured by the manager through env keys. sdk reads them,
+//connects into the eventbus and dispatches events of the function into the registered handler
+//
+//	func main() {
+//		quebic.Register("HelloHandler", func(request quebic.Request, context quebic.Context, callback *quebic.CallBack) {
+//			callback.Reply("hello", 200)
+//		})
+//		log.Fatal(quebic.Start())
+//	}
+package quebic
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"quebic-faas/tracing"
+	"syscall"
+)
+
+//RequestHandler function logic. reply the caller through callback
+type RequestHandler func(request Request, context Context, callback *CallBack)
+
+var handlers = make(map[string]RequestHandler)
+
+//Register register handler under the name used in function handler spec
+// eg: handler: cmd/hello.HelloHandler ==> quebic.Register("HelloHandler", helloHandler)
+func Register(name string, handler RequestHandler) {
+	handlers[name] = handler
+}
+
+//Start connect into eventbus and serve events until function is shutdown. blocks the caller
+func Start() error {
+
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	handler, ok := handlers[config.functionPath]
+	if !ok {
+		return fmt.Errorf("handler %s is not registered", config.functionPath)
+	}
+
+	err = tracing.Init(config.appID, config.tracing)
+	if err != nil {
+		log.Printf("tracing init failed. spans are not exported. error : %v", err)
+	}
+	defer tracing.Shutdown()
+
+	runtime := newFunctionRuntime(config, handler)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		runtime.stop()
+	}()
+
+	return runtime.run()
+
+}
@@ -0,0 +1,220 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const otlpTracesPath = "/v1/traces"
+const instrumentationScope = "quebic-faas"
+
+const otlpStatusOk = 1
+const otlpStatusError = 2
+
+//Exporter send batch of finished spans
+type Exporter interface {
+	Export(serviceName string, spans []*Span) error
+}
+
+//OTLPExporter export spans to OTLP/HTTP collector using json encoding
+type OTLPExporter struct {
+	url    string
+	client *http.Client
+}
+
+//NewOTLPExporter create exporter. endpoint is the collector base url
+func NewOTLPExporter(endpoint string) *OTLPExporter {
+
+	url := strings.TrimRight(endpoint, "/")
+	if !strings.HasSuffix(url, otlpTracesPath) {
+		url = url + otlpTracesPath
+	}
+
+	return &OTLPExporter{url: url, client: &http.Client{Timeout: time.Second * 10}}
+
+}
+
+//Export post spans to the collector
+func (exporter *OTLPExporter) Export(serviceName string, spans []*Span) error {
+
+	body, err := json.Marshal(prepareOTLPRequest(serviceName, spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans : %v", err)
+	}
+
+	response, err := exporter.client.Post(exporter.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to reach collector : %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("collector responded %d", response.StatusCode)
+	}
+
+	return nil
+
+}
+
+//FileExporter append spans into file. each line is an OTLP/JSON request which can be replayed into a collector
+type FileExporter struct {
+	path  string
+	mutex sync.Mutex
+}
+
+//NewFileExporter create exporter
+func NewFileExporter(path string) *FileExporter {
+	return &FileExporter{path: path}
+}
+
+//Export append spans
+func (exporter *FileExporter) Export(serviceName string, spans []*Span) error {
+
+	line, err := json.Marshal(prepareOTLPRequest(serviceName, spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans : %v", err)
+	}
+
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	file, err := os.OpenFile(exporter.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s : %v", exporter.path, err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write %s : %v", exporter.path, err)
+	}
+
+	return nil
+
+}
+
+//OTLP/JSON trace request
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              SpanKind        `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func prepareOTLPRequest(serviceName string, spans []*Span) otlpRequest {
+
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		otlpSpans = append(otlpSpans, prepareOTLPSpan(span))
+	}
+
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			otlpResourceSpans{
+				Resource: otlpResource{
+					Attributes: []otlpAttribute{
+						otlpAttribute{Key: "service.name", Value: otlpValue{StringValue: serviceName}},
+					},
+				},
+				ScopeSpans: []otlpScopeSpans{
+					otlpScopeSpans{
+						Scope: otlpScope{Name: instrumentationScope},
+						Spans: otlpSpans,
+					},
+				},
+			},
+		},
+	}
+
+}
+
+func prepareOTLPSpan(span *Span) otlpSpan {
+
+	span.mutex.Lock()
+	defer span.mutex.Unlock()
+
+	attributes := make([]otlpAttribute, 0, len(span.Attributes))
+	for k, v := range span.Attributes {
+		attributes = append(attributes, otlpAttribute{Key: k, Value: otlpValue{StringValue: v}})
+	}
+
+	status := otlpStatus{Code: otlpStatusOk}
+	if span.Error != "" {
+		status = otlpStatus{Code: otlpStatusError, Message: span.Error}
+	}
+
+	return otlpSpan{
+		TraceID:           span.Context.TraceID,
+		SpanID:            span.Context.SpanID,
+		ParentSpanID:      span.ParentSpanID,
+		Name:              span.Name,
+		Kind:              span.Kind,
+		StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+		Attributes:        attributes,
+		Status:            status,
+	}
+
+}
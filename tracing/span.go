@@ -0,0 +1,121 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package tracing
+
+import (
+	"sync"
+	"time"
+)
+
+//SpanKind OTLP span kind
+type SpanKind int
+
+//span kinds
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+	SpanKindProducer SpanKind = 4
+	SpanKindConsumer SpanKind = 5
+)
+
+//Span timed operation of a trace
+type Span struct {
+	Name         string
+	Kind         SpanKind
+	Context      SpanContext
+	ParentSpanID string
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]string
+	Error        string
+	ended        bool
+	mutex        sync.Mutex
+}
+
+//StartSpan start span as a child of parent. new trace is started when parent is not valid
+func StartSpan(name string, kind SpanKind, parent SpanContext) *Span {
+	return StartSpanAt(name, kind, parent, time.Now())
+}
+
+//StartSpanAt start span with given start time
+func StartSpanAt(name string, kind SpanKind, parent SpanContext, start time.Time) *Span {
+
+	span := &Span{
+		Name:       name,
+		Kind:       kind,
+		Start:      start,
+		Attributes: make(map[string]string),
+	}
+
+	if parent.IsValid() {
+		span.Context = SpanContext{TraceID: parent.TraceID, SpanID: newSpanID(), Sampled: parent.Sampled}
+		span.ParentSpanID = parent.SpanID
+	} else {
+		span.Context = SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Sampled: true}
+	}
+
+	return span
+
+}
+
+//Traceparent traceparent which should be propagated to the child operations
+func (span *Span) Traceparent() string {
+	return span.Context.Traceparent()
+}
+
+//SetAttribute set attribute
+func (span *Span) SetAttribute(key string, value string) {
+	span.mutex.Lock()
+	defer span.mutex.Unlock()
+	span.Attributes[key] = value
+}
+
+//SetError mark span as failed
+func (span *Span) SetError(err string) {
+	span.mutex.Lock()
+	defer span.mutex.Unlock()
+	span.Error = err
+}
+
+//EndedAt end time. zero until the span is finished
+func (span *Span) EndedAt() time.Time {
+	span.mutex.Lock()
+	defer span.mutex.Unlock()
+	return span.End
+}
+
+//Finish end the span and hand it over to the exporter. only first call is recorded
+func (span *Span) Finish() {
+	span.FinishAt(time.Now())
+}
+
+//FinishAt end the span with given end time
+func (span *Span) FinishAt(end time.Time) {
+
+	span.mutex.Lock()
+	if span.ended {
+		span.mutex.Unlock()
+		return
+	}
+	span.ended = true
+	span.End = end
+	span.mutex.Unlock()
+
+	if span.Context.Sampled {
+		record(span)
+	}
+
+}
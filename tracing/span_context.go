@@ -0,0 +1,127 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const traceparentVersion = "00"
+const flagSampled = "01"
+const flagNotSampled = "00"
+
+//SpanContext identity of a span which is propagated to child spans
+type SpanContext struct {
+	TraceID string //32 hex
+	SpanID  string //16 hex
+	Sampled bool
+}
+
+//IsValid check wether trace-id and span-id are assigned
+func (spanContext SpanContext) IsValid() bool {
+	return isValidID(spanContext.TraceID, 32) && isValidID(spanContext.SpanID, 16)
+}
+
+//Traceparent format as W3C traceparent header value
+func (spanContext SpanContext) Traceparent() string {
+
+	flags := flagNotSampled
+	if spanContext.Sampled {
+		flags = flagSampled
+	}
+
+	return fmt.Sprintf("%s-%s-%s-%s", traceparentVersion, spanContext.TraceID, spanContext.SpanID, flags)
+
+}
+
+//ParseTraceparent parse W3C traceparent header value. invalid values give an empty SpanContext
+func ParseTraceparent(traceparent string) SpanContext {
+
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return SpanContext{}
+	}
+
+	//version ff is forbidden. future versions may append fields
+	version := parts[0]
+	if len(version) != 2 || version == "ff" || (version == traceparentVersion && len(parts) != 4) {
+		return SpanContext{}
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return SpanContext{}
+	}
+
+	spanContext := SpanContext{
+		TraceID: strings.ToLower(parts[1]),
+		SpanID:  strings.ToLower(parts[2]),
+		Sampled: flags[0]&1 == 1,
+	}
+
+	if !spanContext.IsValid() {
+		return SpanContext{}
+	}
+
+	return spanContext
+
+}
+
+func isValidID(id string, length int) bool {
+
+	if len(id) != length {
+		return false
+	}
+
+	decoded, err := hex.DecodeString(id)
+	if err != nil {
+		return false
+	}
+
+	//all zero ids are invalid
+	for _, b := range decoded {
+		if b != 0 {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+func newTraceID() string {
+	return randomID(16)
+}
+
+func newSpanID() string {
+	return randomID(8)
+}
+
+func randomID(size int) string {
+
+	for {
+		b := make([]byte, size)
+		rand.Read(b)
+
+		id := hex.EncodeToString(b)
+		if isValidID(id, size*2) {
+			return id
+		}
+	}
+
+}
@@ -0,0 +1,155 @@
+//    Copyright 2018 Tharanga Nilupul Thennakoon
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package tracing
+
+import (
+	"fmt"
+	"log"
+	"quebic-faas/config"
+	"sync"
+	"time"
+)
+
+//ExporterOTLP export spans to OTLP/HTTP collector
+const ExporterOTLP = "otlp"
+
+//ExporterFile append spans into local file
+const ExporterFile = "file"
+
+const queueSize = 4096
+const batchSize = 256
+const flushInterval = time.Second * 5
+
+//tracer exports finished spans in batches
+type tracer struct {
+	serviceName string
+	exporter    Exporter
+	queue       chan *Span
+	done        chan bool
+}
+
+var activeTracer *tracer
+var tracerMutex sync.RWMutex
+
+//Init start exporting spans of this service. spans are still propagated when exporter is not configured
+func Init(serviceName string, tracingConfig config.TracingConfig) error {
+
+	var exporter Exporter
+
+	switch tracingConfig.Exporter {
+	case "":
+		return nil
+	case ExporterOTLP:
+		if tracingConfig.OTLPEndpoint == "" {
+			return fmt.Errorf("otlpEndpoint should not be empty")
+		}
+		exporter = NewOTLPExporter(tracingConfig.OTLPEndpoint)
+	case ExporterFile:
+		if tracingConfig.FilePath == "" {
+			return fmt.Errorf("filePath should not be empty")
+		}
+		exporter = NewFileExporter(tracingConfig.FilePath)
+	default:
+		return fmt.Errorf("unsupported exporter %s", tracingConfig.Exporter)
+	}
+
+	t := &tracer{
+		serviceName: serviceName,
+		exporter:    exporter,
+		queue:       make(chan *Span, queueSize),
+		done:        make(chan bool),
+	}
+
+	go t.run()
+
+	tracerMutex.Lock()
+	activeTracer = t
+	tracerMutex.Unlock()
+
+	log.Printf("tracing : exporting spans to %s", tracingConfig.Exporter)
+
+	return nil
+
+}
+
+//Shutdown flush pending spans and stop exporting
+func Shutdown() {
+
+	tracerMutex.Lock()
+	t := activeTracer
+	activeTracer = nil
+	tracerMutex.Unlock()
+
+	if t == nil {
+		return
+	}
+
+	close(t.queue)
+	<-t.done
+
+}
+
+func record(span *Span) {
+
+	tracerMutex.RLock()
+	defer tracerMutex.RUnlock()
+
+	if activeTracer == nil {
+		return
+	}
+
+	//never block request path. drop when exporter is behind
+	select {
+	case activeTracer.queue <- span:
+	default:
+	}
+
+}
+
+func (t *tracer) run() {
+
+	batch := make([]*Span, 0, batchSize)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		err := t.exporter.Export(t.serviceName, batch)
+		if err != nil {
+			log.Printf("tracing : failed to export %d spans, error : %v", len(batch), err)
+		}
+		batch = make([]*Span, 0, batchSize)
+	}
+
+	for {
+		select {
+		case span, ok := <-t.queue:
+			if !ok {
+				flush()
+				t.done <- true
+				return
+			}
+			batch = append(batch, span)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+
+}
@@ -14,7 +14,10 @@
 
 package types
 
-import "mime/multipart"
+import (
+	"encoding/json"
+	"mime/multipart"
+)
 
 //FunctionDTO dto
 type FunctionDTO struct {
@@ -88,3 +91,83 @@ type JWTToken struct {
 type NewVersionMessage struct {
 	Version string `json:"version"`
 }
+
+//FunctionMetricsMessage invocation metrics reported by a function. runtimes may batch invocations
+type FunctionMetricsMessage struct {
+	Function    string               `json:"function"`
+	Invocations []FunctionInvocation `json:"invocations"`
+}
+
+//FunctionInvocation single invocation of a function
+type FunctionInvocation struct {
+	Event          string  `json:"event"`
+	Status         int     `json:"status"`
+	DurationMillis float64 `json:"durationMillis"`
+}
+
+//RouteChangeMessage route change message
+type RouteChangeMessage struct {
+	Route     string `json:"route"`
+	ChangedAt string `json:"changedAt"`
+}
+
+//BackupHeader first record of a manager backup stream
+type BackupHeader struct {
+	Version   int      `json:"version"`
+	CreatedAt string   `json:"createdAt"`
+	Entities  []string `json:"entities"`
+}
+
+//BackupRecord saved entity in a manager backup stream
+type BackupRecord struct {
+	Entity string          `json:"entity"`
+	Key    string          `json:"key"`
+	Value  json.RawMessage `json:"value"`
+}
+
+//RestoreReport changes made (or would be made in dry-run) by a restore
+type RestoreReport struct {
+	DryRun         bool                `json:"dryRun"`
+	Entities       []RestoreEntityDiff `json:"entities"`
+	Redeployed     []string            `json:"redeployed,omitempty"`
+	RedeployFailed map[string]string   `json:"redeployFailed,omitempty"`
+}
+
+//RestoreEntityDiff keys of an entity which differ from the backup
+type RestoreEntityDiff struct {
+	Entity    string   `json:"entity"`
+	Added     []string `json:"added"`
+	Updated   []string `json:"updated"`
+	Removed   []string `json:"removed"`
+	Unchanged int      `json:"unchanged"`
+}
+
+//AuditRecord change made to an entity. records are never modified
+// Changes : changed fields of an update
+// Before, After : whole entity of a delete or a create
+type AuditRecord struct {
+	ID       string          `json:"id"`
+	Time     string          `json:"time"`
+	Actor    string          `json:"actor"`
+	Action   string          `json:"action"`
+	Entity   string          `json:"entity"`
+	EntityID string          `json:"entityID"`
+	Revision int64           `json:"revision"`
+	Before   json.RawMessage `json:"before,omitempty"`
+	After    json.RawMessage `json:"after,omitempty"`
+	Changes  []AuditChange   `json:"changes,omitempty"`
+}
+
+//AuditChange changed field. Field is the json path. eg: life.awake
+type AuditChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+//AuditPage page of audit records. newest first
+// NextCursor : pass as cursor to get the next page. empty when there are no more
+type AuditPage struct {
+	Records    []AuditRecord `json:"records"`
+	NextCursor string        `json:"nextCursor"`
+}
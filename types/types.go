@@ -25,6 +25,8 @@ type Entity interface {
 	GetID() string
 	SetID(id string)
 	SetModifiedAt()
+	GetRevision() int64
+	SetRevision(revision int64)
 }
 
 //User model ######################################
@@ -33,6 +35,7 @@ type User struct {
 	Firstname  string `json:"firstname"`
 	Password   string `json:"password"`
 	Role       string `json:"role"`
+	Revision   int64  `json:"revision" yaml:"revision"`     //incremented on every update
 	CreatedAt  string `json:"createdAt" yaml:"createdAt"`   //created time
 	ModifiedAt string `json:"modifiedAt" yaml:"modifiedAt"` //modified time
 }
@@ -57,6 +60,16 @@ func (o *User) SetModifiedAt() {
 	o.ModifiedAt = common.CurrentTime()
 }
 
+//GetRevision get revision
+func (o *User) GetRevision() int64 {
+	return o.Revision
+}
+
+//SetRevision set revision
+func (o *User) SetRevision(revision int64) {
+	o.Revision = revision
+}
+
 //SetCreatedAt set create date
 func (o *User) SetCreatedAt() {
 	o.CreatedAt = common.CurrentTime()
@@ -68,6 +81,7 @@ type Event struct {
 	ID         string `json:"id"`
 	Group      string `json:"group"`
 	Name       string `json:"name"`
+	Revision   int64  `json:"revision" yaml:"revision"`     //incremented on every update
 	CreatedAt  string `json:"createdAt" yaml:"createdAt"`   //created time
 	ModifiedAt string `json:"modifiedAt" yaml:"modifiedAt"` //modified time
 }
@@ -92,6 +106,16 @@ func (o *Event) SetModifiedAt() {
 	o.ModifiedAt = common.CurrentTime()
 }
 
+//GetRevision get revision
+func (o *Event) GetRevision() int64 {
+	return o.Revision
+}
+
+//SetRevision set revision
+func (o *Event) SetRevision(revision int64) {
+	o.Revision = revision
+}
+
 //Resource model ######################################
 //ID => URL:RequestMethod
 type Resource struct {
@@ -106,6 +130,9 @@ type Resource struct {
 	RequestMapping        []RequestMappingTemplate `json:"requestMapping" yaml:"requestMapping"`
 	HeaderMapping         []HeaderMappingTemplate  `json:"headerMapping" yaml:"headerMapping"`
 	HeadersToPass         []string                 `json:"headersToPass" yaml:"headersToPass"` //header list pass to endpoint
+	Cache                 ResourceCache            `json:"cache" yaml:"cache"`                 //response cache settings
+	Stream                string                   `json:"stream" yaml:"stream"`               //chunked or sse. empty for single response
+	Revision              int64                    `json:"revision" yaml:"revision"`           //incremented on every update
 	CreatedAt             string                   `json:"createdAt" yaml:"createdAt"`         //created time
 	ModifiedAt            string                   `json:"modifiedAt" yaml:"modifiedAt"`       //modified time
 }
@@ -136,22 +163,156 @@ func (o *Resource) SetModifiedAt() {
 	o.ModifiedAt = common.CurrentTime()
 }
 
+//GetRevision get revision
+func (o *Resource) GetRevision() int64 {
+	return o.Revision
+}
+
+//SetRevision set revision
+func (o *Resource) SetRevision(revision int64) {
+	o.Revision = revision
+}
+
+//ResourceCache response cache settings of a resource
+// QueryParams, Headers : request values which are used to build the cache key
+// Functions : new version of these functions invalidate cached entries.
+//		function invoker routes are invalidated by their own function without listing it here
+type ResourceCache struct {
+	Enabled     bool     `json:"enabled" yaml:"enabled"`
+	TTL         int      `json:"ttl" yaml:"ttl"` //in second
+	QueryParams []string `json:"queryParams" yaml:"queryParams"`
+	Headers     []string `json:"headers" yaml:"headers"`
+	Functions   []string `json:"functions" yaml:"functions"`
+}
+
+//GRPCService model ######################################
+//ID => full name of the service. eg: reports.ReportService
+// Descriptor : FileDescriptorSet which contains the service
+//		protoc --include_imports --descriptor_set_out=report.pb report.proto
+type GRPCService struct {
+	ID         string              `json:"id"`
+	Name       string              `json:"name" yaml:"name"`             //full name of the service
+	Descriptor []byte              `json:"descriptor" yaml:"descriptor"` //base64 in json
+	Methods    []GRPCMethodBinding `json:"methods" yaml:"methods"`
+	Revision   int64               `json:"revision" yaml:"revision"`     //incremented on every update
+	CreatedAt  string              `json:"createdAt" yaml:"createdAt"`   //created time
+	ModifiedAt string              `json:"modifiedAt" yaml:"modifiedAt"` //modified time
+}
+
+//GetReflectObject get Reflect Object
+func (o *GRPCService) GetReflectObject() reflect.Value {
+	return reflect.ValueOf(o)
+}
+
+//GetID get ID
+func (o *GRPCService) GetID() string {
+	return o.ID
+}
+
+//SetID get ID
+func (o *GRPCService) SetID(id string) {
+	o.ID = id
+}
+
+//SetModifiedAt set modified date
+func (o *GRPCService) SetModifiedAt() {
+	o.ModifiedAt = common.CurrentTime()
+}
+
+//GetRevision get revision
+func (o *GRPCService) GetRevision() int64 {
+	return o.Revision
+}
+
+//SetRevision set revision
+func (o *GRPCService) SetRevision(revision int64) {
+	o.Revision = revision
+}
+
+//GRPCMethodBinding bind unary method of a grpc service into an event
+// request message fields become the event payload, function reply become the response message
+// HeaderMapping, HeadersToPass : work on grpc metadata
+type GRPCMethodBinding struct {
+	Method         string                  `json:"method" yaml:"method"` //method name. eg: GetReport
+	Event          string                  `json:"event" yaml:"event"`
+	RequestTimeout int                     `json:"requestTimeout" yaml:"requestTimeout"` //in second
+	HeaderMapping  []HeaderMappingTemplate `json:"headerMapping" yaml:"headerMapping"`
+	HeadersToPass  []string                `json:"headersToPass" yaml:"headersToPass"`
+}
+
+//RuntimeSpec runtime catalog entry
+// Name : runtime name used in function spec. eg: python_3.11
+// Base : built-in runtime which prepares handler and artifact. eg: python_3.6
+// DockerfileTemplate : {{.Image}} and {{.BuildImage}} are replaced. empty => Dockerfile of the base runtime
+// Extensions, HandlerPattern : checked before the base runtime rules
+type RuntimeSpec struct {
+	Name               string   `json:"name" yaml:"name"`
+	Version            string   `json:"version" yaml:"version"` //language version. eg: 3.11
+	Base               string   `json:"base" yaml:"base"`
+	Image              string   `json:"image" yaml:"image"`
+	BuildImage         string   `json:"buildImage" yaml:"buildImage"` //image of the build stage. eg: go builder
+	DockerfileTemplate string   `json:"dockerfileTemplate" yaml:"dockerfileTemplate"`
+	Extensions         []string `json:"extensions" yaml:"extensions"`         //accepted artifact extensions. eg: .tar
+	HandlerPattern     string   `json:"handlerPattern" yaml:"handlerPattern"` //regular expression
+	Revision           int64    `json:"revision" yaml:"revision"`             //incremented on every update
+	ModifiedAt         string   `json:"modifiedAt" yaml:"modifiedAt"`         //modified time
+}
+
+//GetReflectObject get Reflect Object
+func (o *RuntimeSpec) GetReflectObject() reflect.Value {
+	return reflect.ValueOf(o)
+}
+
+//GetID get ID
+func (o *RuntimeSpec) GetID() string {
+	return o.Name
+}
+
+//SetID get ID
+func (o *RuntimeSpec) SetID(id string) {
+	o.Name = id
+}
+
+//SetModifiedAt set modified date
+func (o *RuntimeSpec) SetModifiedAt() {
+	o.ModifiedAt = common.CurrentTime()
+}
+
+//GetRevision get revision
+func (o *RuntimeSpec) GetRevision() int64 {
+	return o.Revision
+}
+
+//SetRevision set revision
+func (o *RuntimeSpec) SetRevision(revision int64) {
+	o.Revision = revision
+}
+
 //Function model ######################################
 // Source : code / artifact stored file location
 // FunctionPath :
 // 		Runtime java : class path of handler
 //		Runtime node : myHandler
+//		Runtime go : handler name registered with sdk
+//		Runtime custom : passed into the image as it is
 // FunctionFile :
 //      Runtime jsvs : const value eg : function.jar
 //      Runtime node : const value eg : handler.js
 //      Runtime node package : user defined
+//      Runtime go : package inside module. eg: ./cmd/hello
+//      Runtime custom : not used
 // Route : function invoker route id. nor required
 type Function struct {
 	Name                 string                `json:"name" yaml:"name"`
 	Version              string                `json:"version" yaml:"version"` //current version
 	Versions             []string              `json:"versions" yaml:"versions"`
 	DockerImageID        string                `json:"dockerImageID" yaml:"dockerImageID"`
+	ImageDigest          string                `json:"imageDigest" yaml:"imageDigest"`       //digest of DockerImageID in the registry. deployments are pinned to it
+	ArtifactDigest       string                `json:"artifactDigest" yaml:"artifactDigest"` //sha256 of the uploaded artifact of the current version
 	Source               string                `json:"source" yaml:"source"`
+	Git                  *FunctionGitSource    `json:"git" yaml:"git"`                   //source is fetched from git instead of uploading
+	SourceCommit         string                `json:"sourceCommit" yaml:"sourceCommit"` //git commit of the current version
+	Image                string                `json:"image" yaml:"image"`               //existing image. runtime custom deploys it without building
 	Handler              string                `json:"handler" yaml:"handler"`
 	HandlerPath          string                `json:"handlerPath" yaml:"handlerPath"`
 	HandlerFile          string                `json:"handlerFile" yaml:"handlerFile"`
@@ -163,10 +324,22 @@ type Function struct {
 	Route                string                `json:"route"`
 	Life                 FunctionLife          `json:"life"`
 	Log                  EntityLog             `json:"log"`
+	Build                FunctionBuild         `json:"build"`                        //last image build
+	Revision             int64                 `json:"revision" yaml:"revision"`     //incremented on every update
 	ModifiedAt           string                `json:"modifiedAt" yaml:"modifiedAt"` //modified time
 	Status               string                `json:"status" yaml:"status"`
 }
 
+//FunctionGitSource git repository of the function source
+// URL : https, ssh or local repository. eg: https://github.com/org/functions.git, /srv/git/functions.git
+// Ref : branch, tag or commit. empty => HEAD of the repository
+// Path : sub directory of the function. empty => root of the repository
+type FunctionGitSource struct {
+	URL  string `json:"url" yaml:"url"`
+	Ref  string `json:"ref" yaml:"ref"`
+	Path string `json:"path" yaml:"path"`
+}
+
 //GetReflectObject get Reflect Object
 func (o *Function) GetReflectObject() reflect.Value {
 	return reflect.ValueOf(o)
@@ -187,6 +360,247 @@ func (o *Function) SetModifiedAt() {
 	o.ModifiedAt = common.CurrentTime()
 }
 
+//GetRevision get revision
+func (o *Function) GetRevision() int64 {
+	return o.Revision
+}
+
+//SetRevision set revision
+func (o *Function) SetRevision(revision int64) {
+	o.Revision = revision
+}
+
+//FunctionBuild image build of a function. each build is tracked with an id
+type FunctionBuild struct {
+	ID         string   `json:"id"`
+	Status     string   `json:"status"`
+	Image      string   `json:"image"`
+	Error      string   `json:"error"`
+	Logs       []string `json:"logs"`
+	StartedAt  string   `json:"startedAt"`
+	FinishedAt string   `json:"finishedAt"`
+}
+
+//FunctionBuildMessage streamed to the client while the function job is processed
+//first message carries the accepted job id. last message carries the finished job
+type FunctionBuildMessage struct {
+	JobID   string `json:"jobID"`
+	BuildID string `json:"buildID,omitempty"`
+	State   string `json:"state,omitempty"`
+	Stream  string `json:"stream,omitempty"`
+	Job     *Job   `json:"job,omitempty"`
+}
+
+//Job model ######################################
+// long running operation which is processed after the request is replied. eg: function create / update
+// Logs : last lines of the output. LogCount : all lines of the output, used to find new lines
+type Job struct {
+	ID         string   `json:"id" yaml:"id"`
+	Kind       string   `json:"kind" yaml:"kind"`
+	Target     string   `json:"target" yaml:"target"` //entity which the job works on. eg: function name
+	State      string   `json:"state" yaml:"state"`
+	Error      string   `json:"error" yaml:"error"`
+	BuildID    string   `json:"buildID" yaml:"buildID"`
+	Logs       []string `json:"logs" yaml:"logs"`
+	LogCount   int      `json:"logCount" yaml:"logCount"`
+	Manager    string   `json:"manager" yaml:"manager"` //manager replica which processes the job
+	Actor      string   `json:"actor" yaml:"actor"`
+	CreatedAt  string   `json:"createdAt" yaml:"createdAt"`
+	Revision   int64    `json:"revision" yaml:"revision"`     //incremented on every update
+	ModifiedAt string   `json:"modifiedAt" yaml:"modifiedAt"` //modified time
+}
+
+//GetReflectObject get Reflect Object
+func (o *Job) GetReflectObject() reflect.Value {
+	return reflect.ValueOf(o)
+}
+
+//GetID get ID
+func (o *Job) GetID() string {
+	return o.ID
+}
+
+//SetID get ID
+func (o *Job) SetID(id string) {
+	o.ID = id
+}
+
+//SetModifiedAt set modified date
+func (o *Job) SetModifiedAt() {
+	o.ModifiedAt = common.CurrentTime()
+}
+
+//GetRevision get revision
+func (o *Job) GetRevision() int64 {
+	return o.Revision
+}
+
+//SetRevision set revision
+func (o *Job) SetRevision(revision int64) {
+	o.Revision = revision
+}
+
+//IsFinished job is ready or failed
+func (o *Job) IsFinished() bool {
+	return o.State == common.JobStateReady || o.State == common.JobStateFailed
+}
+
+//FunctionArtifact model ######################################
+// uploaded artifact which produced a version of a function. ID => <function>:<version>
+// Digest : sha256 of the artifact. artifact is kept in the artifact store under it
+// Image : image which is built from the artifact
+type FunctionArtifact struct {
+	ID          string `json:"id" yaml:"id"`
+	Function    string `json:"function" yaml:"function"`
+	Version     string `json:"version" yaml:"version"`
+	Digest      string `json:"digest" yaml:"digest"`
+	Size        int64  `json:"size" yaml:"size"`
+	FileName    string `json:"fileName" yaml:"fileName"`
+	Image       string `json:"image" yaml:"image"`
+	ImageDigest string `json:"imageDigest" yaml:"imageDigest"`
+	Commit      string `json:"commit" yaml:"commit"` //git commit when the artifact is fetched from git
+	Actor       string `json:"actor" yaml:"actor"`
+	CreatedAt   string `json:"createdAt" yaml:"createdAt"`   //created time
+	Revision    int64  `json:"revision" yaml:"revision"`     //incremented on every update
+	ModifiedAt  string `json:"modifiedAt" yaml:"modifiedAt"` //modified time
+}
+
+//GetReflectObject get Reflect Object
+func (o *FunctionArtifact) GetReflectObject() reflect.Value {
+	return reflect.ValueOf(o)
+}
+
+//GetID get ID
+func (o *FunctionArtifact) GetID() string {
+	return o.ID
+}
+
+//SetID get ID
+func (o *FunctionArtifact) SetID(id string) {
+	o.ID = id
+}
+
+//SetModifiedAt set modified date
+func (o *FunctionArtifact) SetModifiedAt() {
+	o.ModifiedAt = common.CurrentTime()
+}
+
+//GetRevision get revision
+func (o *FunctionArtifact) GetRevision() int64 {
+	return o.Revision
+}
+
+//SetRevision set revision
+func (o *FunctionArtifact) SetRevision(revision int64) {
+	o.Revision = revision
+}
+
+//GetFunctionArtifactID id of the artifact of the function version
+func GetFunctionArtifactID(function string, version string) string {
+	return function + ":" + version
+}
+
+//Registry model ######################################
+// credentials of a docker registry. functions which use images of the registry pull them with these credentials
+// Server : registry host. eg: registry.example.com:5000, index.docker.io
+// Password : sealed with the credentials key of the manager. it is never replied
+// PullSecret : kubernetes image pull secret which is created for the registry
+type Registry struct {
+	Server     string `json:"server" yaml:"server"`
+	Username   string `json:"username" yaml:"username"`
+	Password   string `json:"password" yaml:"password"`
+	Email      string `json:"email" yaml:"email"`
+	PullSecret string `json:"pullSecret" yaml:"pullSecret"`
+	CreatedAt  string `json:"createdAt" yaml:"createdAt"`   //created time
+	Revision   int64  `json:"revision" yaml:"revision"`     //incremented on every update
+	ModifiedAt string `json:"modifiedAt" yaml:"modifiedAt"` //modified time
+}
+
+//GetReflectObject get Reflect Object
+func (o *Registry) GetReflectObject() reflect.Value {
+	return reflect.ValueOf(o)
+}
+
+//GetID get ID
+func (o *Registry) GetID() string {
+	return o.Server
+}
+
+//SetID get ID
+func (o *Registry) SetID(id string) {
+	o.Server = id
+}
+
+//SetModifiedAt set modified date
+func (o *Registry) SetModifiedAt() {
+	o.ModifiedAt = common.CurrentTime()
+}
+
+//GetRevision get revision
+func (o *Registry) GetRevision() int64 {
+	return o.Revision
+}
+
+//SetRevision set revision
+func (o *Registry) SetRevision(revision int64) {
+	o.Revision = revision
+}
+
+//SetCreatedAt set create date
+func (o *Registry) SetCreatedAt() {
+	o.CreatedAt = common.CurrentTime()
+}
+
+//GitCredential credentials of the private git repositories of the functions
+// Host : git server host. eg: github.com, git.example.com:8443
+// Password : password or access token. used with https
+// PrivateKey : ssh private key. used with ssh
+// Password and PrivateKey are sealed with the credentials key of the manager. they are never replied
+type GitCredential struct {
+	Host       string `json:"host" yaml:"host"`
+	Username   string `json:"username" yaml:"username"`
+	Password   string `json:"password" yaml:"password"`
+	PrivateKey string `json:"privateKey" yaml:"privateKey"`
+	CreatedAt  string `json:"createdAt" yaml:"createdAt"`   //created time
+	Revision   int64  `json:"revision" yaml:"revision"`     //incremented on every update
+	ModifiedAt string `json:"modifiedAt" yaml:"modifiedAt"` //modified time
+}
+
+//GetReflectObject get Reflect Object
+func (o *GitCredential) GetReflectObject() reflect.Value {
+	return reflect.ValueOf(o)
+}
+
+//GetID get ID
+func (o *GitCredential) GetID() string {
+	return o.Host
+}
+
+//SetID get ID
+func (o *GitCredential) SetID(id string) {
+	o.Host = id
+}
+
+//SetModifiedAt set modified date
+func (o *GitCredential) SetModifiedAt() {
+	o.ModifiedAt = common.CurrentTime()
+}
+
+//GetRevision get revision
+func (o *GitCredential) GetRevision() int64 {
+	return o.Revision
+}
+
+//SetRevision set revision
+func (o *GitCredential) SetRevision(revision int64) {
+	o.Revision = revision
+}
+
+//SetCreatedAt set create date
+func (o *GitCredential) SetCreatedAt() {
+	o.CreatedAt = common.CurrentTime()
+}
+
 //EnvironmentVariable environmentVariable
 type EnvironmentVariable struct {
 	Name  string `json:"name" yaml:"name"`
@@ -204,6 +618,7 @@ type ManagerComponent struct {
 	Name       string     `json:"name"`
 	Version    string     `json:"Version"`
 	Deployment Deployment `json:"deployment"`
+	Revision   int64      `json:"revision" yaml:"revision"`     //incremented on every update
 	ModifiedAt string     `json:"modifiedAt" yaml:"modifiedAt"` //modified time
 }
 
@@ -227,6 +642,16 @@ func (o *ManagerComponent) SetModifiedAt() {
 	o.ModifiedAt = common.CurrentTime()
 }
 
+//GetRevision get revision
+func (o *ManagerComponent) GetRevision() int64 {
+	return o.Revision
+}
+
+//SetRevision set revision
+func (o *ManagerComponent) SetRevision(revision int64) {
+	o.Revision = revision
+}
+
 //Deployment deployment details
 type Deployment struct {
 	Host string `json:"host"`
@@ -256,6 +681,7 @@ type EntityLog struct {
 type ApigatewayData struct {
 	ManagerComponents        []ManagerComponent `json:"managerComponents"`
 	Resources                []Resource         `json:"resources"`
+	GRPCServices             []GRPCService      `json:"grpcServices"`
 	CurrentDeploymentVersion string             `json:"currentDeploymentVersion"`
 }
 
@@ -288,6 +714,43 @@ func (o *RequestTracker) SetID(id string) {
 func (o *RequestTracker) SetModifiedAt() {
 }
 
+//GetRevision request-trackers are not revisioned
+func (o *RequestTracker) GetRevision() int64 {
+	return 0
+}
+
+//SetRevision request-trackers are not revisioned
+func (o *RequestTracker) SetRevision(revision int64) {
+}
+
+//RequestTrackerPage page of request-trackers. newest first
+// NextCursor : pass as cursor to get the next page. empty when there are no more
+type RequestTrackerPage struct {
+	RequestTrackers []RequestTracker `json:"requestTrackers"`
+	NextCursor      string           `json:"nextCursor"`
+}
+
+//RequestTrackerSearchResults request-trackers matched to log search. newest first
+// Truncated : more trackers may match. narrow the search
+type RequestTrackerSearchResults struct {
+	Results   []RequestTrackerSearchResult `json:"results"`
+	Truncated bool                         `json:"truncated"`
+}
+
+//RequestTrackerSearchResult matched request-tracker with its matched logs
+type RequestTrackerSearchResult struct {
+	RequestTracker RequestTracker `json:"requestTracker"`
+	Highlights     []LogHighlight `json:"highlights"`
+}
+
+//LogHighlight matched log
+// Matches : [start, end) byte offsets of matched parts of the message
+type LogHighlight struct {
+	LogIndex int     `json:"logIndex"`
+	Log      Log     `json:"log"`
+	Matches  [][]int `json:"matches"`
+}
+
 //RequestTrackerResponse requestTrackerResponse
 type RequestTrackerResponse struct {
 	Status  int         `json:"status"`